@@ -1,11 +1,9 @@
 package config
 
 import (
-	"context"
 	"fmt"
 	"os"
 	"reflect"
-	"strconv"
 
 	"github.com/joho/godotenv"
 
@@ -56,10 +54,6 @@ func LoadConfigs() error {
 		utils.Error(fmt.Errorf("failed to initialize redis connection"))
 	}
 
-	// Initialize the creditsea whatsapp current count of the day on redis, to check if the creditsea whatsapp count is exceeded
-	currentCountInt, _ := strconv.Atoi(Configs.CreditSeaWhatsappCurrentCount)
-	redis.InitCreditSeaCounter(context.Background(), redis.RDB, redis.CreditSeaWhatsappCount, currentCountInt)
-
 	/* Commented out because we are not using Analytics DB for now
 	// Connect Analytics DB
 	err := database.ConnectDB(database.Analytics, Configs)
@@ -81,6 +75,11 @@ func LoadConfigs() error {
 		return fmt.Errorf("failed to initialize AWS clients: %v", err)
 	}
 
+	// Messages published from this process are signed for the consumer
+	if err := queue.SetSigningKeys(Configs.CommMessageSigningKeys); err != nil {
+		return fmt.Errorf("failed to load COMM_MESSAGE_SIGNING_KEYS: %v", err)
+	}
+
 	/*
 		_, err = queue.GetSdkSnsClient(Configs.AWSRegion)
 		if err != nil {
@@ -3,25 +3,68 @@ package cron
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/robfig/cron/v3"
+	"github.com/wecredit/communication-sdk/internal/database"
 	"github.com/wecredit/communication-sdk/internal/redis"
+	apiServices "github.com/wecredit/communication-sdk/internal/services/apiServices"
+	"github.com/wecredit/communication-sdk/internal/vendorAudit"
 	"github.com/wecredit/communication-sdk/sdk/utils"
 )
 
-func StartMidnightResetCron() {
-	utils.Debug("Starting midnight reset cron job...")
-	c := cron.New(cron.WithSeconds()) // With seconds for full control
-	_, err := c.AddFunc("0 0 0 * * *", func() {
-		err := redis.ResetCreditSeaCounter(context.Background(), redis.RDB, redis.CreditSeaWhatsappCount)
+// StartTemplateActivationCron activates approved template versions once their scheduled time has passed.
+// Every pod schedules the activation, and the pod taking the lock runs it.
+func StartTemplateActivationCron() {
+	utils.Debug("Starting template activation cron job...")
+	c := cron.New(cron.WithSeconds())
+	_, err := c.AddFunc("0 * * * * *", func() {
+		// The lock expires before the next run, so a pod dying while holding it does not skip a minute
+		release, leader, err := redis.TryLock(context.Background(), redis.CronLockKey("template-activation"), 50*time.Second)
 		if err != nil {
-			utils.Error(fmt.Errorf("cron reset failed: %v", err))
-		} else {
-			utils.Info("Cron: Counter reset at 12:00 AM")
+			utils.Error(fmt.Errorf("cron template activation skipped, lock unavailable: %v", err))
+			return
 		}
+		if !leader {
+			utils.Debug("Cron: template activation is running on another pod")
+			return
+		}
+		defer release()
+
+		apiServices.NewTemplateVersionService(database.DBtechWrite).ActivateScheduledVersions()
+	})
+	if err != nil {
+		utils.Error(fmt.Errorf("failed to schedule template activation: %v", err))
+	}
+	c.Start()
+}
+
+// StartVendorAuditRetentionCron purges vendor audit rows older than VENDOR_AUDIT_RETENTION_DAYS every night.
+// Every pod schedules the purge, and the pod taking the lock runs it.
+func StartVendorAuditRetentionCron() {
+	utils.Debug("Starting vendor audit retention cron job...")
+	c := cron.New(cron.WithSeconds())
+	_, err := c.AddFunc("0 30 2 * * *", func() {
+		release, leader, err := redis.TryLock(context.Background(), redis.CronLockKey("vendor-audit-purge"), time.Hour)
+		if err != nil {
+			utils.Error(fmt.Errorf("cron vendor audit purge skipped, lock unavailable: %v", err))
+			return
+		}
+		if !leader {
+			utils.Debug("Cron: vendor audit purge is running on another pod")
+			return
+		}
+		defer release()
+
+		purged, err := vendorAudit.Purge(database.DBtechWrite)
+		if err != nil {
+			utils.Error(fmt.Errorf("cron vendor audit purge failed: %v", err))
+			return
+		}
+		utils.Info(fmt.Sprintf("Cron: purged %d vendor audit rows", purged))
 	})
 	if err != nil {
-		utils.Error(fmt.Errorf("failed to schedule midnight reset: %v", err))
+		utils.Error(fmt.Errorf("failed to schedule vendor audit purge: %v", err))
 	}
 	c.Start()
 }
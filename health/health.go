@@ -22,14 +22,16 @@ const (
 )
 
 type HealthCheckResponse struct {
-	Status         string `json:"status"`
-	TechReadDB     string `json:"tech_read_db"`
-	TechWriteDB    string `json:"tech_write_db"`
-	CacheStatus    string `json:"cache_status,omitempty"`
-	RedisStatus    string `json:"redis_status,omitempty"`
-	AWSQueueClient string `json:"aws_queue_client"`
-	ClientIP       string `json:"client_ip"`
-	ServerPort     string `json:"server_port"`
+	Status         string                            `json:"status"`
+	TechReadDB     string                            `json:"tech_read_db"`
+	TechWriteDB    string                            `json:"tech_write_db"`
+	CacheStatus    string                            `json:"cache_status,omitempty"`
+	CacheVersions  map[string]int64                  `json:"cache_versions,omitempty"`
+	CacheFreshness map[string]cache.DatasetFreshness `json:"cache_freshness,omitempty"`
+	RedisStatus    string                            `json:"redis_status,omitempty"`
+	AWSQueueClient string                            `json:"aws_queue_client"`
+	ClientIP       string                            `json:"client_ip"`
+	ServerPort     string                            `json:"server_port"`
 }
 
 // healthCheckResult represents the result of a single health check
@@ -89,14 +91,22 @@ func checkAWSQueueHealth() healthCheckResult {
 	}
 }
 
-// checkCacheHealth checks if cache is initialized
-func checkCacheHealth() healthCheckResult {
-	if cache.GetCache() == nil {
+// checkCacheHealth checks if the config snapshot has been loaded and is being kept fresh
+func checkCacheHealth(freshness map[string]cache.DatasetFreshness) healthCheckResult {
+	if !cache.Current().Loaded(cache.AuthDetails) {
 		return healthCheckResult{
-			status:    fmt.Sprintf("%s: Cache not initialized", StatusUnhealthy),
+			status:    fmt.Sprintf("%s: Cache not loaded", StatusUnhealthy),
 			isHealthy: false,
 		}
 	}
+	for key, dataset := range freshness {
+		if dataset.Stale {
+			return healthCheckResult{
+				status:    fmt.Sprintf("%s: Cache for %s is stale", StatusUnhealthy, key),
+				isHealthy: false,
+			}
+		}
+	}
 	return healthCheckResult{
 		status:    StatusHealthy,
 		isHealthy: true,
@@ -107,7 +117,7 @@ func checkCacheHealth() healthCheckResult {
 func HealthCheckHandler(port string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		resp := HealthCheckResponse{
-			Status:     StatusOK,
+			Status: StatusOK,
 		}
 
 		// Perform all health checks
@@ -123,8 +133,10 @@ func HealthCheckHandler(port string) gin.HandlerFunc {
 		awsQueueResult := checkAWSQueueHealth()
 		resp.AWSQueueClient = awsQueueResult.status
 
-		cacheResult := checkCacheHealth()
+		resp.CacheFreshness = cache.Freshness()
+		cacheResult := checkCacheHealth(resp.CacheFreshness)
 		resp.CacheStatus = cacheResult.status
+		resp.CacheVersions = cache.Versions()
 
 		// Determine overall status
 		if !techReadResult.isHealthy || !techWriteResult.isHealthy ||
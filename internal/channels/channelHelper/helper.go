@@ -2,8 +2,9 @@ package channelHelper
 
 import (
 	"fmt"
-	"strings"
 
+	"github.com/wecredit/communication-sdk/internal/metrics"
+	"github.com/wecredit/communication-sdk/internal/models/apiModels"
 	extapimodels "github.com/wecredit/communication-sdk/internal/models/extApiModels"
 	"github.com/wecredit/communication-sdk/pkg/cache"
 	"github.com/wecredit/communication-sdk/sdk/models/sdkModels"
@@ -12,171 +13,74 @@ import (
 )
 
 func ConstructTemplateKey(msg sdkModels.CommApiRequestBody) string {
-	return fmt.Sprintf("Process:%s|Stage:%.2f|Client:%s|Channel:%s|Vendor:%s",
-		msg.ProcessName, msg.Stage, msg.Client, msg.Channel, msg.Vendor)
+	return cache.TemplateCacheKey(msg.ProcessName, msg.Stage, msg.Client, msg.Channel, msg.Vendor)
 }
 
-// FetchTemplateData attempts exact key match, falls back to wildcard vendor if needed.
-func FetchTemplateData(msg sdkModels.CommApiRequestBody, templateDetails map[string]map[string]interface{}) (map[string]interface{}, string, error) {
-	key := ConstructTemplateKey(msg)
-	if data, ok := templateDetails[key]; ok && data["IsActive"] == variables.Active { // Check if template is active  //TODO: Check if any stage has an active and inactive template then active gets sent.
-		return data, msg.Vendor, nil
-	}
-
-	// 2. Stage fallback (same Process, same Client, same Channel, same Vendor, but any sub-stage in same integer part)
-	stageInt := int(msg.Stage)
-	stagePrefix := fmt.Sprintf("Process:%s|Stage:%d.", msg.ProcessName, stageInt)
-
-	utils.Debug(fmt.Sprintf(
-		"No exact template found for Stage %.2f; Trying stage fallback within stage %d for vendor %s, channel %s...",
-		msg.Stage, stageInt, msg.Vendor, msg.Channel))
-
-	for otherKey, val := range templateDetails {
-		if strings.HasPrefix(otherKey, stagePrefix) && val["IsActive"] == variables.Active {
-			parts := strings.Split(otherKey, "|")
-			if len(parts) == 5 &&
-				parts[2] == "Client:"+msg.Client &&
-				parts[3] == "Channel:"+msg.Channel &&
-				parts[4] == "Vendor:"+msg.Vendor {
-				return val, msg.Vendor, nil
-			}
-		}
-	}
-
-	if msg.Client == variables.CreditSea {
-		return nil, msg.Vendor, fmt.Errorf("no template (and fallback template) found for Process: %s, Stage: %.2f, Client: %s, Channel: %s, Vendor: %s",
-			msg.ProcessName, msg.Stage, msg.Client, msg.Channel, msg.Vendor)
-	}
-
-	// No template found for the given Process: %s, Stage: %.2f, Client: %s, Channel: %s, Vendor: %s; Fetching fallback template
-	utils.Debug(fmt.Sprintf("No template found for the given Process: %s, Stage: %.2f, Client: %s, Channel: %s, Vendor: %s; Fetching fallback template",
-		msg.ProcessName, msg.Stage, msg.Client, msg.Channel, msg.Vendor))
-	prefix := fmt.Sprintf("Process:%s|Stage:%.2f|Client:%s|Channel:%s|Vendor:",
-		msg.ProcessName, msg.Stage, msg.Client, msg.Channel)
-	for otherKey, val := range templateDetails {
-		if strings.HasPrefix(otherKey, prefix) && val["IsActive"] == variables.Active {
-			parts := strings.Split(otherKey, "|")
-			if len(parts) == 5 {
-				vendor := strings.TrimPrefix(parts[4], "Vendor:")
-				if IsVendorActive(msg.Client, vendor, msg.Channel) {
-					return val, vendor, nil
-				}
-			}
-		}
-	}
-
-	return nil, "", fmt.Errorf("no fallback template found for Process: %s, Stage: %.2f, Client: %s, Channel: %s",
-		msg.ProcessName, msg.Stage, msg.Client, msg.Channel)
+func IsVendorActive(client, vendor, channel string) bool {
+	return isVendorActive(cache.Current(), client, vendor, channel)
 }
 
-func IsVendorActive(client, vendor, channel string) bool {
-	vendors, found := cache.GetCache().GetMappedData(cache.VendorsData)
-	if !found {
+func isVendorActive(snapshot *cache.ConfigSnapshot, client, vendor, channel string) bool {
+	if !snapshot.Loaded(cache.VendorsData) {
 		utils.Error(fmt.Errorf("vendor data not found in cache"))
 		return false
 	}
-
-	clientKey := strings.ToLower(strings.TrimSpace(client))
-	channelKey := strings.ToUpper(strings.TrimSpace(channel))
-	baseKey := fmt.Sprintf("Name:%s|Channel:%s", vendor, channelKey)
-
-	keysToTry := []string{
-		fmt.Sprintf("%s|Client:%s", baseKey, clientKey),
-	}
-	if clientKey != "" {
-		keysToTry = append(keysToTry, fmt.Sprintf("%s|Client:", baseKey))
-	}
-
-	for _, key := range keysToTry {
-		if vendorData, ok := vendors[key]; ok {
-			if status, ok := vendorData["Status"].(int64); ok && status == variables.Active {
-				return true
-			}
-		}
-	}
-	return false
+	vendorData, ok := snapshot.VendorForClient(vendor, channel, client)
+	return ok && int64(vendorData.Status) == variables.Active
 }
 
 func ShouldHitVendor(client, channel string) bool {
-	clientDetails, found := cache.GetCache().GetMappedData(cache.ClientsData)
-	if !found {
+	snapshot := cache.Current()
+	if !snapshot.Loaded(cache.ClientsData) {
 		utils.Error(fmt.Errorf("client details not found in cache"))
 	}
-	key := fmt.Sprintf("Name:%s|Channel:%s", client, channel)
-	if clientData, ok := clientDetails[key]; ok {
-		if status, ok := clientData["ShouldHitVendor"].(int64); ok && status == variables.Active {
-			return true
-		}
-	}
-	return false
+	clientData, ok := snapshot.Client(client, channel)
+	return ok && int64(clientData.ShouldHitVendor) == variables.Active
 }
 
 func LogTemplateNotFound(msg sdkModels.CommApiRequestBody, err error) {
+	metrics.TemplatesNotFound.WithLabelValues(msg.Client, msg.Channel).Inc()
 	utils.Error(fmt.Errorf("template missing for CommId %s: %v", msg.CommId, err))
 }
 
-func PopulateWhatsappFields(req *extapimodels.WhatsappRequestBody, data map[string]interface{}) {
-	if val, ok := data["TemplateName"].(string); ok {
-		req.TemplateName = val
-	}
-	if val, ok := data["ImageUrl"].(string); ok {
-		req.ImageUrl = val
-	}
-	if val, ok := data["ImageId"].(string); ok {
-		req.ImageID = val
-	}
-	if val, ok := data["Link"].(string); ok {
-		req.ButtonLink = val
-	}
-	if val, ok := data["TemplateVariables"].(string); ok {
-		req.TemplateVariables = val
-	}
-	if val, ok := data["TemplateCategory"].(int64); ok {
-		req.TemplateCategory = fmt.Sprintf("%d", val)
-	}
+func PopulateWhatsappFields(req *extapimodels.WhatsappRequestBody, data apiModels.Templatedetails) {
+	req.TemplateName = data.TemplateName
+	req.ImageUrl = data.ImageUrl
+	req.ImageID = data.ImageId
+	req.ButtonLink = data.Link
+	req.TemplateVariables = data.TemplateVariables
+	req.TemplateCategory = templateCategory(data)
 }
 
-func PopulateSmsFields(req *extapimodels.SmsRequestBody, data map[string]interface{}) {
-	if val, ok := data["TemplateText"].(string); ok {
-		req.TemplateText = val
-	}
-	if val, ok := data["TemplateVariables"].(string); ok {
-		req.TemplateVariables = val
-	}
-	if val, ok := data["DltTemplateId"].(int64); ok {
-		req.DltTemplateId = val
-	}
-	if val, ok := data["TemplateCategory"].(int64); ok {
-		req.TemplateCategory = fmt.Sprintf("%d", val)
-	}
+func PopulateSmsFields(req *extapimodels.SmsRequestBody, data apiModels.Templatedetails) {
+	req.TemplateText = data.TemplateText
+	req.TemplateVariables = data.TemplateVariables
+	req.DltTemplateId = data.DltTemplateId
+	req.TemplateCategory = templateCategory(data)
 }
 
-func PopulateRcsFields(req *extapimodels.RcsRequestBody, data map[string]interface{}) {
-	if val, ok := data["TemplateName"].(string); ok {
-		req.TemplateName = val
-	}
-	if val, ok := data["ImageId"].(string); ok {
-		req.AppId = val
-	}
+func PopulateRcsFields(req *extapimodels.RcsRequestBody, data apiModels.Templatedetails) {
+	req.TemplateName = data.TemplateName
+	req.AppId = data.ImageId
 }
 
-func PopulateEmailFields(req *extapimodels.EmailRequestBody, data map[string]interface{}) {
-	if val, ok := data["TemplateName"].(string); ok {
-		req.TemplateId = val
-	}
-	if val, ok := data["Subject"].(string); ok {
-		req.EmailSubject = val
-	}
-	if val, ok := data["TemplateVariables"].(string); ok {
-		req.TemplateVariables = val
-	}
-	if val, ok := data["FromEmail"].(string); ok {
-		req.FromEmail = val
+func PopulateEmailFields(req *extapimodels.EmailRequestBody, data apiModels.Templatedetails) {
+	req.TemplateId = data.TemplateName
+	req.EmailSubject = data.Subject
+	req.TemplateVariables = data.TemplateVariables
+	req.FromEmail = data.FromEmail
+}
+
+// templateCategory returns the category as sent to vendors; templates without one send none.
+func templateCategory(data apiModels.Templatedetails) string {
+	if data.TemplateCategory == 0 {
+		return ""
 	}
+	return fmt.Sprintf("%d", data.TemplateCategory)
 }
 
 // HandleTemplateNotFoundError handles the common template not found error pattern
-func HandleTemplateNotFoundError(msg sdkModels.CommApiRequestBody, err error) (bool, map[string]interface{}, error) {
+func HandleTemplateNotFoundError(msg sdkModels.CommApiRequestBody, trace TemplateResolutionTrace, err error) (bool, map[string]interface{}, error) {
 	LogTemplateNotFound(msg, err)
 	errorMessage := "template not found for mobile: " + msg.Mobile + " for stage: " + fmt.Sprintf("%.2f", msg.Stage)
 	if updateErr := UpdateRedisErrorMessage(msg.Mobile, msg.Channel, msg.Stage, errorMessage); updateErr != nil {
@@ -184,15 +88,40 @@ func HandleTemplateNotFoundError(msg sdkModels.CommApiRequestBody, err error) (b
 	}
 
 	dbResponse := map[string]interface{}{
-		"CommId":          msg.CommId,
-		"Vendor":          msg.Vendor,
-		"MobileNumber":    msg.Mobile,
-		"IsSent":          false,
-		"ResponseMessage": fmt.Sprintf("No template found for the given Process: %s, Stage: %.2f, Client: %s, Channel: %s and Vendor: %s", msg.ProcessName, msg.Stage, msg.Client, msg.Channel, msg.Vendor),
+		"CommId":             msg.CommId,
+		"Vendor":             msg.Vendor,
+		"MobileNumber":       msg.Mobile,
+		"IsSent":             false,
+		"ResponseMessage":    fmt.Sprintf("No template found for the given Process: %s, Stage: %.2f, Client: %s, Channel: %s and Vendor: %s", msg.ProcessName, msg.Stage, msg.Client, msg.Channel, msg.Vendor),
+		"TemplateResolution": trace.String(),
 	}
 	return true, dbResponse, nil // message processed but not sent as Template not found
 }
 
+// HandleTemplateRenderError records a template that could not be rendered from the request variables.
+// The message is treated as processed so it is not retried, since the same variables will fail again.
+// Any other error is returned so the message is retried.
+func HandleTemplateRenderError(msg sdkModels.CommApiRequestBody, trace TemplateResolutionTrace, err error) (bool, map[string]interface{}, error) {
+	if !IsTemplateRenderError(err) {
+		return false, nil, fmt.Errorf("error rendering template for CommId %s: %v", msg.CommId, err)
+	}
+	utils.Error(fmt.Errorf("template render failed for CommId %s: %v", msg.CommId, err))
+	errorMessage := fmt.Sprintf("template render failed for mobile: %s for stage: %.2f: %v", msg.Mobile, msg.Stage, err)
+	if updateErr := UpdateRedisErrorMessage(msg.Mobile, msg.Channel, msg.Stage, errorMessage); updateErr != nil {
+		utils.Error(fmt.Errorf("failed to update Redis for template render error: %v", updateErr))
+	}
+
+	dbResponse := map[string]interface{}{
+		"CommId":             msg.CommId,
+		"Vendor":             msg.Vendor,
+		"MobileNumber":       msg.Mobile,
+		"IsSent":             false,
+		"ResponseMessage":    fmt.Sprintf("Template render failed for Process: %s, Stage: %.2f, Client: %s, Channel: %s: %v", msg.ProcessName, msg.Stage, msg.Client, msg.Channel, err),
+		"TemplateResolution": trace.String(),
+	}
+	return true, dbResponse, nil // message processed but not sent as the template could not be rendered
+}
+
 // HandleShouldHitVendorOffError handles the common shouldHitVendor is off error pattern
 func HandleShouldHitVendorOffError(mobile, channel string, stage float64) error {
 	errorMessage := fmt.Sprintf("shouldHitVendor is off for mobile: %s and channel: %s", mobile, channel)
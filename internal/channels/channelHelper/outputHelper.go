@@ -0,0 +1,20 @@
+package channelHelper
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	"github.com/wecredit/communication-sdk/internal/database"
+	"github.com/wecredit/communication-sdk/internal/pii"
+)
+
+// InsertOutput stores the output row of the message with its personal data protected by pii.ProtectRow.
+// The row passed in keeps the plaintext, as it still describes the message to the webhook.
+func InsertOutput(ctx context.Context, tableName, commId string, row map[string]interface{}) error {
+	stored := maps.Clone(row)
+	if err := pii.ProtectRow(commId, stored); err != nil {
+		return fmt.Errorf("failed to protect output row of CommId %s: %v", commId, err)
+	}
+	return database.InsertDataWithContext(ctx, tableName, database.DBtechWrite, stored)
+}
@@ -5,13 +5,17 @@ import (
 	"strings"
 
 	"github.com/wecredit/communication-sdk/config"
+	"github.com/wecredit/communication-sdk/internal/pii"
+	"github.com/wecredit/communication-sdk/internal/redact"
 	"github.com/wecredit/communication-sdk/internal/redis"
+	"github.com/wecredit/communication-sdk/pkg/cache"
 	"github.com/wecredit/communication-sdk/sdk/utils"
 )
 
-// GenerateRedisKey creates a standardized Redis key for mobile_channel_stage
+// GenerateRedisKey creates a standardized Redis key for mobile_channel_stage; the mobile number is replaced by its
+// keyed hash once PII_HASH_KEY is set.
 func GenerateRedisKey(mobile, channel string, stage float64) string {
-	return fmt.Sprintf("%s_%s_%s", mobile, strings.ToUpper(channel), fmt.Sprintf("%.0f", stage))
+	return fmt.Sprintf("%s_%s_%s", pii.Hash(mobile), strings.ToUpper(channel), fmt.Sprintf("%.0f", stage))
 }
 
 // UpdateRedisTransactionId updates the transactionId in Redis with standardized error handling
@@ -28,6 +32,7 @@ func UpdateRedisTransactionId(mobile, channel string, stage float64, transaction
 // UpdateRedisErrorMessage updates the errorMessage in Redis with standardized error handling
 func UpdateRedisErrorMessage(mobile, channel string, stage float64, errorMessage string) error {
 	redisKey := GenerateRedisKey(mobile, channel, stage)
+	errorMessage = redact.Text(errorMessage)
 	err := redis.UpdateErrorMessage(redis.RDB, config.Configs.CommIdempotentKey, redisKey, errorMessage)
 	if err != nil {
 		utils.Error(fmt.Errorf("redis update for redisKey: %s errorMessage: %s failed: %v", redisKey, errorMessage, err))
@@ -35,3 +40,12 @@ func UpdateRedisErrorMessage(mobile, channel string, stage float64, errorMessage
 	}
 	return nil
 }
+
+// DailyLimit returns how many messages the client may send on the channel per IST day, 0 when it is unlimited.
+func DailyLimit(client, channel string) int {
+	clientData, ok := cache.Current().Client(strings.ToLower(client), strings.ToUpper(channel))
+	if !ok || clientData.DailyLimit < 0 {
+		return 0
+	}
+	return clientData.DailyLimit
+}
@@ -0,0 +1,395 @@
+package channelHelper
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	extapimodels "github.com/wecredit/communication-sdk/internal/models/extApiModels"
+	"github.com/wecredit/communication-sdk/sdk/variables"
+)
+
+// Template variables are declared on the template as a comma-separated list of specs:
+//
+//	CustomerName|default:Dear Customer,DueDate|date,EmiAmount|currency|required,LoanId|mask
+//
+// Supported options are required, default:<value>, date[:<layout>], currency[:<prefix>] and mask[:<visible>];
+// currency amounts are prefixed with ₹ unless another prefix is given.
+// A bare name without options falls back to the legacy behaviour for the well known CreditSea variables.
+
+// dltPlaceholder is the positional placeholder used by DLT approved SMS templates.
+var dltPlaceholder = regexp.MustCompile(`\{#var#\}`)
+
+// dueDateLayouts are the date formats accepted from callers.
+var dueDateLayouts = []string{
+	time.RFC3339,                    // "2025-06-08T00:00:00Z"
+	"2006-01-02 15:04:05 -0700 MST", // Go's full time format with timezone
+	"2006-01-02 15:04:05",           // Datetime without timezone
+	"2006-01-02",                    // Date-only
+}
+
+const (
+	defaultDateLayout   = "2006-01-02"
+	defaultCurrency     = "₹" // amounts are in INR unless the spec names another prefix
+	defaultMaskVisible  = 4
+	variableFormatDate  = "date"
+	variableFormatMoney = "currency"
+	variableFormatMask  = "mask"
+)
+
+// variableAliases maps legacy attribute names used by older templates to request variables.
+var variableAliases = map[string]string{
+	"first_name": "CustomerName",
+	"due_date":   "DueDate",
+	"loan_id":    "LoanId",
+}
+
+// VariableSpec describes how a single template variable is filled.
+type VariableSpec struct {
+	Name       string
+	Required   bool
+	Default    string
+	HasDefault bool
+	Format     string
+	FormatArg  string
+
+	// passThrough keeps a value the format cannot parse as it is, as legacy templates did
+	passThrough bool
+}
+
+// MissingVariableError is returned when a required template variable has no value.
+type MissingVariableError struct {
+	Name string
+}
+
+func (e *MissingVariableError) Error() string {
+	return fmt.Sprintf("missing value for required variable: %s", e.Name)
+}
+
+// InvalidVariableError is returned when a template variable cannot be formatted or the spec is malformed.
+type InvalidVariableError struct {
+	Name   string
+	Value  string
+	Reason string
+}
+
+func (e *InvalidVariableError) Error() string {
+	return fmt.Sprintf("invalid value %q for variable %s: %s", e.Value, e.Name, e.Reason)
+}
+
+// IsTemplateRenderError reports whether err was caused by template variables, which retrying will not fix.
+func IsTemplateRenderError(err error) bool {
+	var missing *MissingVariableError
+	var invalid *InvalidVariableError
+	return errors.As(err, &missing) || errors.As(err, &invalid)
+}
+
+// ParseTemplateVariables splits the TemplateVariables column into variable names, dropping any options.
+func ParseTemplateVariables(templateVariables string) []string {
+	var names []string
+	for _, entry := range strings.Split(templateVariables, ",") {
+		name := strings.TrimSpace(strings.SplitN(entry, "|", 2)[0])
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// ParseVariableSpecs parses the TemplateVariables column into specs for the given channel.
+func ParseVariableSpecs(templateVariables, channel string) ([]VariableSpec, error) {
+	var specs []VariableSpec
+	for _, entry := range strings.Split(templateVariables, ",") {
+		parts := strings.Split(entry, "|")
+		name := strings.TrimSpace(parts[0])
+		if name == "" {
+			continue
+		}
+
+		if len(parts) == 1 {
+			specs = append(specs, legacyVariableSpec(name, channel))
+			continue
+		}
+
+		spec := VariableSpec{Name: name}
+		for _, option := range parts[1:] {
+			key, arg, _ := strings.Cut(strings.TrimSpace(option), ":")
+			switch key {
+			case "required":
+				spec.Required = true
+			case "default":
+				spec.Default = arg
+				spec.HasDefault = true
+			case variableFormatDate, variableFormatMoney, variableFormatMask:
+				spec.Format = key
+				spec.FormatArg = arg
+			default:
+				return nil, &InvalidVariableError{Name: name, Value: option, Reason: "unknown option"}
+			}
+		}
+		specs = append(specs, spec)
+	}
+	return specs, nil
+}
+
+// legacyVariableSpec keeps the behaviour templates relied on before specs could be declared.
+func legacyVariableSpec(name, channel string) VariableSpec {
+	spec := VariableSpec{Name: name}
+	switch name {
+	case "CustomerName":
+		spec.HasDefault = true
+		spec.Default = "Customer"
+		if channel == variables.SMS {
+			spec.Default = "Dear Customer"
+		}
+	case "DueDate":
+		spec.Format = variableFormatDate
+		spec.Required = channel == variables.SMS
+		// An SMS is sent on a registered DLT template, which a date in another format would not match
+		spec.passThrough = channel != variables.SMS
+	case "EmiAmount":
+		spec.Required = channel == variables.SMS
+	}
+	return spec
+}
+
+// LookupVariable returns the value for name, matching keys case-insensitively and through legacy aliases.
+func LookupVariable(name string, vars map[string]string) (string, bool) {
+	if value, ok := vars[name]; ok {
+		return value, true
+	}
+	for key, value := range vars {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+	if alias, ok := variableAliases[name]; ok {
+		return LookupVariable(alias, vars)
+	}
+	return "", false
+}
+
+// ResolveVariable returns the formatted value for spec, applying its default and required checks.
+func ResolveVariable(spec VariableSpec, vars map[string]string) (string, error) {
+	value, _ := LookupVariable(spec.Name, vars)
+	value = strings.TrimSpace(value)
+
+	if value == "" && spec.HasDefault {
+		value = spec.Default
+	}
+	if spec.Required && isEmptyVariable(spec, value) {
+		return "", &MissingVariableError{Name: spec.Name}
+	}
+	if value == "" {
+		return "", nil
+	}
+
+	switch spec.Format {
+	case variableFormatDate:
+		return formatDate(spec, value)
+	case variableFormatMoney:
+		return formatCurrency(spec, value)
+	case variableFormatMask:
+		return formatMask(spec, value)
+	}
+	return value, nil
+}
+
+// ResolveVariables resolves every variable declared in templateVariables, in declaration order.
+func ResolveVariables(templateVariables string, vars map[string]string, channel string) ([]VariableSpec, []string, error) {
+	specs, err := ParseVariableSpecs(templateVariables, channel)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	values := make([]string, 0, len(specs))
+	for _, spec := range specs {
+		value, err := ResolveVariable(spec, vars)
+		if err != nil {
+			return nil, nil, err
+		}
+		values = append(values, value)
+	}
+	return specs, values, nil
+}
+
+// RenderDltText replaces each {#var#} in text with the next variable declared in templateVariables.
+func RenderDltText(text, templateVariables string, vars map[string]string, channel string) (string, error) {
+	if !dltPlaceholder.MatchString(text) {
+		return text, nil
+	}
+
+	_, values, err := ResolveVariables(templateVariables, vars, channel)
+	if err != nil {
+		return "", err
+	}
+
+	index := 0
+	return dltPlaceholder.ReplaceAllStringFunc(text, func(_ string) string {
+		if index >= len(values) {
+			return ""
+		}
+		value := values[index]
+		index++
+		return value
+	}), nil
+}
+
+// RenderSmsRequest fills the DLT placeholders of the SMS template text in place.
+func RenderSmsRequest(req *extapimodels.SmsRequestBody) error {
+	text, err := RenderDltText(req.TemplateText, req.TemplateVariables, req.Variables, variables.SMS)
+	if err != nil {
+		return err
+	}
+	req.TemplateText = text
+	return nil
+}
+
+// RenderWhatsappRequest resolves the positional body parameters of the WhatsApp template.
+func RenderWhatsappRequest(req *extapimodels.WhatsappRequestBody) error {
+	_, values, err := ResolveVariables(req.TemplateVariables, req.Variables, variables.WhatsApp)
+	if err != nil {
+		return err
+	}
+	req.BodyParameters = values
+	return nil
+}
+
+// RenderEmailRequest resolves the named attributes of the email template.
+func RenderEmailRequest(req *extapimodels.EmailRequestBody) error {
+	specs, values, err := ResolveVariables(req.TemplateVariables, req.Variables, variables.Email)
+	if err != nil {
+		return err
+	}
+	req.Attributes = make(map[string]string, len(specs))
+	for i, spec := range specs {
+		req.Attributes[spec.Name] = values[i]
+	}
+	return nil
+}
+
+// WithWhatsappBody inserts a body component carrying the rendered parameters ahead of any button components.
+// Empty parameters are skipped, matching how WhatsApp templates were filled historically.
+func WithWhatsappBody(components []map[string]interface{}, bodyParameters []string) []map[string]interface{} {
+	var params []map[string]interface{}
+	for _, value := range bodyParameters {
+		if value != "" {
+			params = append(params, map[string]interface{}{
+				"type": "text",
+				"text": value,
+			})
+		}
+	}
+	if len(params) == 0 {
+		return components
+	}
+
+	body := map[string]interface{}{
+		"type":       "body",
+		"parameters": params,
+	}
+
+	result := make([]map[string]interface{}, 0, len(components)+1)
+	inserted := false
+	for _, component := range components {
+		if !inserted && component["type"] == "button" {
+			result = append(result, body)
+			inserted = true
+		}
+		result = append(result, component)
+	}
+	if !inserted {
+		result = append(result, body)
+	}
+	return result
+}
+
+func isEmptyVariable(spec VariableSpec, value string) bool {
+	if value == "" {
+		return true
+	}
+	if spec.Format == variableFormatMoney || spec.Name == "EmiAmount" {
+		// An amount of zero is never a meaningful value for a required amount
+		if amount, err := strconv.ParseFloat(value, 64); err == nil && amount == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func formatDate(spec VariableSpec, value string) (string, error) {
+	layout := spec.FormatArg
+	if layout == "" {
+		layout = defaultDateLayout
+	}
+	for _, inputLayout := range dueDateLayouts {
+		if t, err := time.Parse(inputLayout, value); err == nil {
+			return t.Format(layout), nil
+		}
+	}
+	if spec.passThrough {
+		return value, nil
+	}
+	return "", &InvalidVariableError{Name: spec.Name, Value: value, Reason: "unrecognised date format"}
+}
+
+// formatCurrency formats an amount with Indian digit grouping, e.g. 125000.5 becomes ₹1,25,000.50.
+func formatCurrency(spec VariableSpec, value string) (string, error) {
+	amount, err := strconv.ParseFloat(strings.ReplaceAll(value, ",", ""), 64)
+	if err != nil {
+		return "", &InvalidVariableError{Name: spec.Name, Value: value, Reason: "not a number"}
+	}
+
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	formatted := strconv.FormatFloat(amount, 'f', 2, 64)
+	whole, fraction, _ := strings.Cut(formatted, ".")
+
+	grouped := whole
+	if len(whole) > 3 {
+		head, tail := whole[:len(whole)-3], whole[len(whole)-3:]
+		var groups []string
+		for len(head) > 2 {
+			groups = append([]string{head[len(head)-2:]}, groups...)
+			head = head[:len(head)-2]
+		}
+		if head != "" {
+			groups = append([]string{head}, groups...)
+		}
+		grouped = strings.Join(append(groups, tail), ",")
+	}
+
+	if fraction != "00" {
+		grouped += "." + fraction
+	}
+	prefix := spec.FormatArg
+	if prefix == "" {
+		prefix = defaultCurrency
+	}
+	return prefix + sign + grouped, nil
+}
+
+// formatMask hides all but the last few characters of value, e.g. loan IDs.
+func formatMask(spec VariableSpec, value string) (string, error) {
+	visible := defaultMaskVisible
+	if spec.FormatArg != "" {
+		n, err := strconv.Atoi(spec.FormatArg)
+		if err != nil || n < 0 {
+			return "", &InvalidVariableError{Name: spec.Name, Value: spec.FormatArg, Reason: "mask length must be a non-negative number"}
+		}
+		visible = n
+	}
+
+	runes := []rune(value)
+	if len(runes) <= visible {
+		return value, nil
+	}
+	return strings.Repeat("X", len(runes)-visible) + string(runes[len(runes)-visible:]), nil
+}
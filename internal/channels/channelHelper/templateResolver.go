@@ -0,0 +1,231 @@
+package channelHelper
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/wecredit/communication-sdk/internal/models/apiModels"
+	"github.com/wecredit/communication-sdk/internal/tracing"
+	"github.com/wecredit/communication-sdk/pkg/cache"
+	"github.com/wecredit/communication-sdk/sdk/models/sdkModels"
+	"github.com/wecredit/communication-sdk/sdk/utils"
+	"github.com/wecredit/communication-sdk/sdk/variables"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// defaultFallbackPolicy is used for clients that have not configured a TemplateFallbackPolicy.
+var defaultFallbackPolicy = []string{
+	variables.TemplateMatchExact,
+	variables.TemplateMatchSiblingStage,
+	variables.TemplateMatchOtherVendor,
+}
+
+// TemplateResolutionTrace explains how a template was chosen for a message.
+type TemplateResolutionTrace struct {
+	Policy     []string
+	Steps      []string
+	MatchedKey string
+}
+
+func (t *TemplateResolutionTrace) record(format string, args ...interface{}) {
+	t.Steps = append(t.Steps, fmt.Sprintf(format, args...))
+}
+
+// String renders the trace in the single line form stored with the output row.
+func (t TemplateResolutionTrace) String() string {
+	return fmt.Sprintf("policy=%s; %s", strings.Join(t.Policy, ">"), strings.Join(t.Steps, "; "))
+}
+
+// ParseFallbackPolicy validates a comma-separated TemplateFallbackPolicy, e.g. "EXACT,SIBLING_STAGE".
+func ParseFallbackPolicy(policy string) ([]string, error) {
+	var steps []string
+	seen := make(map[string]bool)
+	for _, step := range strings.Split(policy, ",") {
+		step = strings.ToUpper(strings.TrimSpace(step))
+		if step == "" {
+			continue
+		}
+		switch step {
+		case variables.TemplateMatchExact, variables.TemplateMatchSiblingStage, variables.TemplateMatchOtherVendor:
+		default:
+			return nil, fmt.Errorf("unknown template fallback step: %s", step)
+		}
+		if seen[step] {
+			return nil, fmt.Errorf("duplicate template fallback step: %s", step)
+		}
+		seen[step] = true
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// FallbackPolicyForClient returns the ordered resolution steps configured for the client and channel.
+func FallbackPolicyForClient(client, channel string) []string {
+	return fallbackPolicy(cache.Current(), client, channel)
+}
+
+func fallbackPolicy(snapshot *cache.ConfigSnapshot, client, channel string) []string {
+	if clientData, ok := snapshot.Client(client, channel); ok && strings.TrimSpace(clientData.TemplateFallbackPolicy) != "" {
+		raw := clientData.TemplateFallbackPolicy
+		policy, err := ParseFallbackPolicy(raw)
+		if err == nil && len(policy) > 0 {
+			return policy
+		}
+		utils.Error(fmt.Errorf("ignoring invalid TemplateFallbackPolicy %q for client %s: %v", raw, client, err))
+	}
+	return defaultFallbackPolicy
+}
+
+// ResolveTemplate picks the template for msg by trying each step of the client's fallback policy in order.
+// Candidates within a step are ordered deterministically, so the same message always resolves to the same template.
+// Every lookup goes through the one snapshot, so a reload in between cannot mix old and new configuration.
+func ResolveTemplate(ctx context.Context, msg sdkModels.CommApiRequestBody, snapshot *cache.ConfigSnapshot) (apiModels.Templatedetails, string, TemplateResolutionTrace, error) {
+	_, span := tracing.Start(ctx, "template.resolve", trace.SpanKindInternal, tracing.AttrCommId.String(msg.CommId))
+	template, vendor, resolution, err := resolveTemplate(msg, snapshot)
+	span.SetAttributes(
+		attribute.String("template.match", resolution.MatchedKey),
+		attribute.String("template.resolution", resolution.String()),
+		tracing.AttrVendor.String(vendor),
+	)
+	tracing.End(span, err)
+	return template, vendor, resolution, err
+}
+
+func resolveTemplate(msg sdkModels.CommApiRequestBody, snapshot *cache.ConfigSnapshot) (apiModels.Templatedetails, string, TemplateResolutionTrace, error) {
+	trace := TemplateResolutionTrace{Policy: fallbackPolicy(snapshot, msg.Client, msg.Channel)}
+
+	for _, step := range trace.Policy {
+		var match *templateCandidate
+
+		switch step {
+		case variables.TemplateMatchExact:
+			match = resolveExact(msg, snapshot, &trace)
+		case variables.TemplateMatchSiblingStage:
+			match = resolveSiblingStage(msg, snapshot, &trace)
+		case variables.TemplateMatchOtherVendor:
+			match = resolveOtherVendor(msg, snapshot, &trace)
+		}
+
+		if match != nil {
+			trace.MatchedKey = match.key
+			utils.Debug(fmt.Sprintf("Template resolved for CommId %s: %s", msg.CommId, trace.String()))
+			return match.template, match.vendor, trace, nil
+		}
+	}
+
+	utils.Debug(fmt.Sprintf("Template not resolved for CommId %s: %s", msg.CommId, trace.String()))
+	return apiModels.Templatedetails{}, msg.Vendor, trace, fmt.Errorf("no template found for Process: %s, Stage: %.2f, Client: %s, Channel: %s, Vendor: %s (%s)",
+		msg.ProcessName, msg.Stage, msg.Client, msg.Channel, msg.Vendor, trace.String())
+}
+
+func resolveExact(msg sdkModels.CommApiRequestBody, snapshot *cache.ConfigSnapshot, trace *TemplateResolutionTrace) *templateCandidate {
+	key := ConstructTemplateKey(msg)
+	template, ok := snapshot.Templates[key]
+	switch {
+	case !ok:
+		trace.record("%s: no template for %s", variables.TemplateMatchExact, key)
+		return nil
+	case !template.IsActive:
+		trace.record("%s: template Id %d for %s is inactive", variables.TemplateMatchExact, template.Id, key)
+		return nil
+	}
+	trace.record("%s: matched template Id %d", variables.TemplateMatchExact, template.Id)
+	return &templateCandidate{key: key, template: template, vendor: msg.Vendor}
+}
+
+// resolveSiblingStage looks for an active template on another sub-stage of the same stage, nearest stage first.
+func resolveSiblingStage(msg sdkModels.CommApiRequestBody, snapshot *cache.ConfigSnapshot, trace *TemplateResolutionTrace) *templateCandidate {
+	stageInt := int(msg.Stage)
+
+	var candidates []templateCandidate
+	for key, template := range snapshot.Templates {
+		if int(template.Stage) != stageInt || fmt.Sprintf("%.2f", template.Stage) == fmt.Sprintf("%.2f", msg.Stage) {
+			continue
+		}
+		if !matchesTemplate(template, msg.ProcessName, msg.Client, msg.Channel) || !strings.EqualFold(template.Vendor, msg.Vendor) {
+			continue
+		}
+		candidates = append(candidates, templateCandidate{key: key, template: template, vendor: msg.Vendor})
+	}
+
+	if len(candidates) == 0 {
+		trace.record("%s: no active template on another sub-stage of stage %d for vendor %s", variables.TemplateMatchSiblingStage, stageInt, msg.Vendor)
+		return nil
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		si, sj := candidates[i].template.Stage, candidates[j].template.Stage
+		di, dj := math.Abs(si-msg.Stage), math.Abs(sj-msg.Stage)
+		if di != dj {
+			return di < dj
+		}
+		if si != sj {
+			return si < sj
+		}
+		return candidates[i].template.Id < candidates[j].template.Id
+	})
+
+	best := candidates[0]
+	trace.record("%s: matched template Id %d on stage %.2f, nearest of %d candidate(s)", variables.TemplateMatchSiblingStage, best.template.Id, best.template.Stage, len(candidates))
+	return &best
+}
+
+// resolveOtherVendor looks for an active template on the same stage served by another active vendor, highest routing weight first.
+func resolveOtherVendor(msg sdkModels.CommApiRequestBody, snapshot *cache.ConfigSnapshot, trace *TemplateResolutionTrace) *templateCandidate {
+	var candidates []templateCandidate
+	for key, template := range snapshot.Templates {
+		if fmt.Sprintf("%.2f", template.Stage) != fmt.Sprintf("%.2f", msg.Stage) {
+			continue
+		}
+		vendor := strings.ToUpper(template.Vendor)
+		if vendor == strings.ToUpper(msg.Vendor) || !matchesTemplate(template, msg.ProcessName, msg.Client, msg.Channel) {
+			continue
+		}
+		if !isVendorActive(snapshot, msg.Client, vendor, msg.Channel) {
+			trace.record("%s: skipped vendor %s as it is inactive", variables.TemplateMatchOtherVendor, vendor)
+			continue
+		}
+		var weight int
+		if vendorData, ok := snapshot.VendorForClient(vendor, msg.Channel, msg.Client); ok {
+			weight = vendorData.Weight
+		}
+		candidates = append(candidates, templateCandidate{key: key, template: template, vendor: vendor, weight: weight})
+	}
+
+	if len(candidates) == 0 {
+		trace.record("%s: no active template from another active vendor", variables.TemplateMatchOtherVendor)
+		return nil
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].weight != candidates[j].weight {
+			return candidates[i].weight > candidates[j].weight
+		}
+		if candidates[i].vendor != candidates[j].vendor {
+			return candidates[i].vendor < candidates[j].vendor
+		}
+		return candidates[i].template.Id < candidates[j].template.Id
+	})
+
+	best := candidates[0]
+	trace.record("%s: matched template Id %d from vendor %s (weight %d) of %d candidate(s)", variables.TemplateMatchOtherVendor, best.template.Id, best.vendor, best.weight, len(candidates))
+	return &best
+}
+
+type templateCandidate struct {
+	key      string
+	template apiModels.Templatedetails
+	vendor   string
+	weight   int
+}
+
+func matchesTemplate(template apiModels.Templatedetails, process, client, channel string) bool {
+	return template.IsActive &&
+		template.Process == process &&
+		template.Client == client &&
+		template.Channel == channel
+}
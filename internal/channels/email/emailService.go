@@ -1,6 +1,7 @@
 package email
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -15,33 +16,34 @@ import (
 	"github.com/wecredit/communication-sdk/sdk/variables"
 )
 
-func SendEmailByProcess(msg sdkModels.CommApiRequestBody) (bool, map[string]interface{}, error) {
+func SendEmailByProcess(ctx context.Context, msg sdkModels.CommApiRequestBody) (bool, map[string]interface{}, error) {
 
 	requestBody := extapimodels.EmailRequestBody{
-		ToEmail:           msg.Email,
-		Process:           msg.ProcessName,
-		Client:            msg.Client,
-		EmiAmount:         msg.EmiAmount,
-		CustomerName:      msg.CustomerName,
-		LoanId:            msg.LoanId,
-		ApplicationNumber: msg.ApplicationNumber,
-		DueDate:           msg.DueDate,
-		Description:       msg.Description,
+		CommId:      msg.CommId,
+		ToEmail:     msg.Email,
+		Process:     msg.ProcessName,
+		Client:      msg.Client,
+		Variables:   msg.TemplateVariables(),
+		Description: msg.Description,
 	}
 
-	utils.Debug("Fetching Email process data from cache")
-	templateDetails, found := cache.GetCache().GetMappedData(cache.TemplateDetailsData)
-	if !found {
-		utils.Error(fmt.Errorf("template data not found in cache"))
+	utils.DebugCtx(ctx, "Fetching Email process data from cache")
+	snapshot := cache.Current()
+	if !snapshot.Loaded(cache.TemplateDetailsData) {
+		utils.ErrorCtx(ctx, fmt.Errorf("template data not found in cache"))
 		return false, nil, errors.New("template data not found in cache")
 	}
-	data, matchedVendor, err := channelHelper.FetchTemplateData(msg, templateDetails)
+	data, matchedVendor, resolution, err := channelHelper.ResolveTemplate(ctx, msg, snapshot)
 	if err != nil {
-		return channelHelper.HandleTemplateNotFoundError(msg, err)
+		return channelHelper.HandleTemplateNotFoundError(msg, resolution, err)
 	}
 	msg.Vendor = matchedVendor
+	ctx = utils.WithVendor(ctx, msg.Vendor)
 
 	channelHelper.PopulateEmailFields(&requestBody, data)
+	if err := channelHelper.RenderEmailRequest(&requestBody); err != nil {
+		return channelHelper.HandleTemplateRenderError(msg, resolution, err)
+	}
 
 	var response extapimodels.EmailResponse
 	// Check if the vendor should be hit
@@ -53,23 +55,24 @@ func SendEmailByProcess(msg sdkModels.CommApiRequestBody) (bool, map[string]inte
 		case variables.TIMES:
 			return false, nil, errors.New("times email is not supported yet")
 		case variables.SINCH:
-			response = sinchEmail.HitSinchEmailApi(requestBody)
+			response = sinchEmail.HitSinchEmailApi(ctx, requestBody)
 		}
 	}
 
 	// Step 2: Once you have responseId, update the value of transactionId in redis
 	if err := channelHelper.UpdateRedisTransactionId(msg.Mobile, msg.Channel, msg.Stage, response.TransactionId); err != nil {
-		utils.Error(fmt.Errorf("failed to update Redis transactionId: %v", err))
+		utils.ErrorCtx(ctx, fmt.Errorf("failed to update Redis transactionId: %v", err))
 	}
 
 	response.TemplateName = requestBody.TemplateId
 	response.CommId = msg.CommId
+	response.TemplateResolution = resolution.String()
 	response.Vendor = msg.Vendor
 	response.Email = msg.Email
 
 	dbMappedData, err := services.MapIntoDbModel(response)
 	if err != nil {
-		utils.Error(fmt.Errorf("error in mapping data into dbModel: %v", err))
+		utils.ErrorCtx(ctx, fmt.Errorf("error in mapping data into dbModel: %v", err))
 	}
 
 	// if err := database.InsertData(config.Configs.EmailOutputTable, database.DBtech, dbMappedData); err != nil {
@@ -77,9 +80,9 @@ func SendEmailByProcess(msg sdkModels.CommApiRequestBody) (bool, map[string]inte
 	// }
 
 	jsonBytes, _ := json.Marshal(response)
-	utils.Debug(fmt.Sprintf("EmailResponse: %s", string(jsonBytes)))
+	utils.DebugCtx(ctx, fmt.Sprintf("EmailResponse: %s", string(jsonBytes)))
 	if shouldHitVendor && response.IsSent {
-		utils.Info(fmt.Sprintf("Email sent successfully for Process: %s on %s through %s", msg.ProcessName, msg.Email, msg.Vendor))
+		utils.InfoCtx(ctx, fmt.Sprintf("Email sent successfully for Process: %s on %s through %s", msg.ProcessName, msg.Email, msg.Vendor))
 		return true, dbMappedData, nil
 	}
 
@@ -87,7 +90,7 @@ func SendEmailByProcess(msg sdkModels.CommApiRequestBody) (bool, map[string]inte
 		// Step 2: Once you have error message, update the error message in redis
 		dbMappedData["ResponseMessage"] = "shouldHitVendor is off for email " + msg.Email
 		if err := channelHelper.HandleShouldHitVendorOffError(msg.Mobile, msg.Channel, msg.Stage); err != nil {
-			utils.Error(fmt.Errorf("failed to handle shouldHitVendor off error: %v", err))
+			utils.ErrorCtx(ctx, fmt.Errorf("failed to handle shouldHitVendor off error: %v", err))
 		}
 	}
 
@@ -1,65 +1,89 @@
 package sinchEmail
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
 
 	"github.com/wecredit/communication-sdk/config"
 	sinchpayloads "github.com/wecredit/communication-sdk/internal/channels/email/sinch/sinchPayloads"
+	"github.com/wecredit/communication-sdk/internal/channels/vendorClient"
+	"github.com/wecredit/communication-sdk/internal/models/awsModels"
 	extapimodels "github.com/wecredit/communication-sdk/internal/models/extApiModels"
+	"github.com/wecredit/communication-sdk/internal/vendorAccounts"
 	"github.com/wecredit/communication-sdk/sdk/queue"
 	"github.com/wecredit/communication-sdk/sdk/utils"
 	"github.com/wecredit/communication-sdk/sdk/variables"
 )
 
-func HitSinchEmailApi(data extapimodels.EmailRequestBody) extapimodels.EmailResponse {
+func HitSinchEmailApi(ctx context.Context, data extapimodels.EmailRequestBody) extapimodels.EmailResponse {
 	var sinchEmailResponse extapimodels.EmailResponse
 	sinchEmailResponse.IsSent = false
 
 	// Getting the API URL
 	apiUrl := config.Configs.SinchEmailApiUrl
 
+	account, err := vendorAccounts.Resolve(data.Client, variables.Email, variables.SINCH)
+	if err != nil {
+		utils.ErrorCtx(ctx, err)
+		sinchEmailResponse.ResponseMessage = fmt.Sprintf("error occured in resolving Sinch Email account: %v", err)
+		return sinchEmailResponse
+	}
+
 	// Setting the API header
 	apiHeader := map[string]string{
 		"Cache-Control": "no-cache",
-		"Authorization": fmt.Sprintf("Bearer %s", config.Configs.SinchEmailApiToken),
+		"Authorization": fmt.Sprintf("Bearer %s", account.Token),
 		"Content-Type":  "application/json",
 	}
 
 	// Get api payload
-	apiPayload, err := sinchpayloads.GetTemplatePayload(data, config.Configs)
+	apiPayload, err := sinchpayloads.GetTemplatePayload(data, account)
 	if err != nil {
-		utils.Error(fmt.Errorf("error occured while getting Email payload: %v", err))
+		utils.ErrorCtx(ctx, fmt.Errorf("error occured while getting Email payload: %v", err))
 		sinchEmailResponse.ResponseMessage = fmt.Sprintf("error occured in Sinch Email payload: %v for %s", err, data.Client)
 		return sinchEmailResponse
 	}
 
-	apiResponse, err := utils.ApiHit(variables.PostMethod, apiUrl, apiHeader, "", "", apiPayload, variables.ContentTypeJSON)
+	response, err := vendorClient.For(variables.SINCH).Do(ctx, vendorClient.Request{
+		CommId:      data.CommId,
+		Method:      variables.PostMethod,
+		URL:         apiUrl,
+		Headers:     apiHeader,
+		Body:        apiPayload,
+		ContentType: variables.ContentTypeJSON,
+	})
 	if err != nil {
-		utils.Error(fmt.Errorf("error occured while hitting into Sinch Email API: %v", err))
-		if queueErr := queue.SendMessageWithSubject(queue.SQSClient, data, config.Configs.AwsErrorQueueUrl, variables.ApiHitsFails, err.Error()); queueErr != nil {
-			utils.Error(fmt.Errorf("error sending message to error queue: %v", queueErr))
+		utils.ErrorCtx(ctx, fmt.Errorf("error occured while hitting into Sinch Email API: %v", err))
+		if queueErr := queue.SendMessageWithSubject(queue.SQSClient, awsModels.ApiHitFailure{CommId: data.CommId, Client: data.Client, Channel: variables.Email, Vendor: variables.SINCH}, config.Configs.AwsErrorQueueUrl, variables.ApiHitsFails, err.Error()); queueErr != nil {
+			utils.ErrorCtx(ctx, fmt.Errorf("error sending message to error queue: %v", queueErr))
 		}
 		sinchEmailResponse.ResponseMessage = fmt.Sprintf("error occured while hitting Sinch Email payload: %v", err)
 		return sinchEmailResponse
 	}
 
-	status, ok := apiResponse["ApistatusCode"].(int)
-	if !ok {
-		sinchEmailResponse.ResponseMessage = fmt.Sprintf("error occured while hitting Sinch Email payload: %v", err)
+	var apiResponse extapimodels.SinchEmailApiResponse
+	if err := response.Decode(&apiResponse); err != nil {
+		utils.ErrorCtx(ctx, fmt.Errorf("error occured while reading Sinch Email API response: %v", err))
+		sinchEmailResponse.ResponseMessage = fmt.Sprintf("unexpected Sinch Email response: %v", err)
 		return sinchEmailResponse
 	}
 
-	accepted := status == 200
-
-	if accepted {
-		sinchEmailResponse.TransactionId = apiResponse["request_id"].(string)
+	if response.StatusCode == http.StatusOK {
+		sinchEmailResponse.TransactionId = apiResponse.RequestId
 		sinchEmailResponse.IsSent = true
 		sinchEmailResponse.ResponseMessage = "Message Submitted Successfully"
+	} else if len(apiResponse.Errors) > 0 {
+		// errors is a string or a list depending on the failure
+		var message string
+		if json.Unmarshal(apiResponse.Errors, &message) != nil {
+			message = string(apiResponse.Errors)
+		}
+		sinchEmailResponse.ResponseMessage = message
 	} else {
-		sinchEmailResponse.ResponseMessage = apiResponse["errors"].(string)
+		sinchEmailResponse.ResponseMessage = response.Error().Error()
 	}
 
-	fmt.Println("Sinch Email Final response:", sinchEmailResponse)
-
 	return sinchEmailResponse
 }
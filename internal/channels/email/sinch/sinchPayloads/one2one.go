@@ -1,62 +1,45 @@
 package sinchEmailPayload
 
 import (
-	"fmt"
 	"strconv"
-	"strings"
 
 	"github.com/wecredit/communication-sdk/helper"
+	"github.com/wecredit/communication-sdk/internal/channels/channelHelper"
 	extapimodels "github.com/wecredit/communication-sdk/internal/models/extApiModels"
-	"github.com/wecredit/communication-sdk/sdk/models"
+	"github.com/wecredit/communication-sdk/internal/vendorAccounts"
 )
 
-func GetTemplatePayload(data extapimodels.EmailRequestBody, config models.Config) (map[string]interface{}, error) {
-	// Map template variables to data field accessors
-	varMapping := map[string]func() interface{}{
-		"first_name": func() interface{} { return data.CustomerName },
-		"due_date":   func() interface{} { return data.DueDate },
-		"loan_id":    func() interface{} { return data.LoanId },
-		// "amount":     func() interface{} { return data.EmiAmount }, // Added for potential 4th variable
+// GetTemplatePayload builds the Sinch email request. The from address is the template's, else the account's
+// Sender; the display name and reply-to address come from the account.
+func GetTemplatePayload(data extapimodels.EmailRequestBody, account vendorAccounts.Account) (map[string]interface{}, error) {
+	attributes := make(map[string]interface{}, len(data.Attributes))
+	for name, value := range data.Attributes {
+		attributes[name] = value
 	}
 
-	// Count variables for exact map capacity
-	varCount := 0
-	if len(data.TemplateVariables) > 0 {
-		varCount = len(strings.Split(data.TemplateVariables, ",")) // Approximate count
-	}
-
-	// Preallocate attributes map with exact or capped capacity
-	attributes := make(map[string]interface{}, min(varCount, len(varMapping)))
+	recipientName, _ := channelHelper.LookupVariable("CustomerName", data.Variables)
 
-	// Process comma-separated TemplateVariables
-	if varCount > 0 {
-		for _, varName := range strings.Split(data.TemplateVariables, ",") {
-			varName = strings.TrimSpace(varName) // Handle potential whitespace
-			if getter, exists := varMapping[varName]; exists {
-				attributes[varName] = getter()
-			} else {
-				return nil, fmt.Errorf("unrecognized template variable: %s", varName)
-			}
-		}
+	fromEmail := data.FromEmail
+	if fromEmail == "" {
+		fromEmail = account.Sender
+	}
+	from := map[string]interface{}{
+		"email": fromEmail, // from email
+	}
+	if account.SenderName != "" {
+		from["name"] = account.SenderName // name to be shown in email
 	}
 
 	// Construct payload with minimal allocations
 	templatePayload := map[string]interface{}{
 		"subject": data.EmailSubject, // subject of email
-		"from": map[string]interface{}{
-			"email": data.FromEmail, // from email
-			"name":  "CreditSea",    // name to be shown in email
-		},
-		"reply_to": map[string]interface{}{
-			"email": "help@creditsea.com", // reply to email
-			"name":  "CreditSea",          // reply to name
-		},
+		"from":    from,
 		"recipients": []map[string]interface{}{
 			{
 				"to": []map[string]interface{}{
 					{
 						"email": data.ToEmail,
-						"name":  data.CustomerName,
+						"name":  recipientName,
 					},
 				},
 				"attributes": attributes,
@@ -67,14 +50,15 @@ func GetTemplatePayload(data extapimodels.EmailRequestBody, config models.Config
 		},
 		"template_id": data.TemplateId,
 	}
+	if account.ReplyTo != "" {
+		replyTo := map[string]interface{}{
+			"email": account.ReplyTo, // reply to email
+		}
+		if account.SenderName != "" {
+			replyTo["name"] = account.SenderName // reply to name
+		}
+		templatePayload["reply_to"] = replyTo
+	}
 
 	return templatePayload, nil
 }
-
-// min returns the smaller of two integers
-func min(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
-}
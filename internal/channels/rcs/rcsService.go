@@ -1,6 +1,7 @@
 package rcs
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -12,33 +13,37 @@ import (
 	"github.com/wecredit/communication-sdk/internal/database"
 	extapimodels "github.com/wecredit/communication-sdk/internal/models/extApiModels"
 	dbservices "github.com/wecredit/communication-sdk/internal/services/dbService"
+	"github.com/wecredit/communication-sdk/internal/services/webhookService"
 	"github.com/wecredit/communication-sdk/pkg/cache"
 	"github.com/wecredit/communication-sdk/sdk/models/sdkModels"
 	"github.com/wecredit/communication-sdk/sdk/utils"
 	"github.com/wecredit/communication-sdk/sdk/variables"
 )
 
-func SendRcsByProcess(msg sdkModels.CommApiRequestBody) (bool, error) {
-	templateDetails, found := cache.GetCache().GetMappedData(cache.TemplateDetailsData)
-	if !found {
+func SendRcsByProcess(ctx context.Context, msg sdkModels.CommApiRequestBody) (bool, error) {
+	snapshot := cache.Current()
+	if !snapshot.Loaded(cache.TemplateDetailsData) {
 		return false, errors.New("template data not found in cache")
 	}
-	templateData, matchedVendor, err := channelHelper.FetchTemplateData(msg, templateDetails)
+	templateData, matchedVendor, resolution, err := channelHelper.ResolveTemplate(ctx, msg, snapshot)
 	if err != nil {
 		channelHelper.LogTemplateNotFound(msg, err)
 		return true, nil // message processed but not sent as Template not found
 	}
 	msg.Vendor = matchedVendor
+	ctx = utils.WithVendor(ctx, msg.Vendor)
 
 	req := extapimodels.RcsRequestBody{
+		CommId:  msg.CommId,
 		Mobile:  msg.Mobile,
+		Client:  msg.Client,
 		Process: msg.ProcessName,
 	}
 	channelHelper.PopulateRcsFields(&req, templateData)
 
 	rcsAppIdData, err := database.GetRcsAppId(database.DBtechRead, req.AppId)
 	if err != nil {
-		utils.Error(fmt.Errorf("failed to fetch RCS AppId data: %v", err))
+		utils.ErrorCtx(ctx, fmt.Errorf("failed to fetch RCS AppId data: %v", err))
 		return false, fmt.Errorf("failed to fetch RCS AppId data: %v", err)
 	}
 
@@ -57,27 +62,31 @@ func SendRcsByProcess(msg sdkModels.CommApiRequestBody) (bool, error) {
 	if shouldHitVendor {
 		switch msg.Vendor {
 		case variables.TIMES:
-			response = timesRcs.HitTimesRcsApi(req)
+			response = timesRcs.HitTimesRcsApi(ctx, req)
 		case variables.SINCH:
-			response = sinchRcs.HitSinchRcsApi(req)
+			response = sinchRcs.HitSinchRcsApi(ctx, req)
 		}
 	}
 
 	response.CommId = msg.CommId
+	response.TemplateResolution = resolution.String()
 	response.TemplateName = req.TemplateName
 	response.Vendor = msg.Vendor
 
 	dbMappedData, err := dbservices.MapIntoDbModel(response)
 	if err != nil {
-		utils.Error(fmt.Errorf("mapping error: %v", err))
+		utils.ErrorCtx(ctx, fmt.Errorf("mapping error: %v", err))
 	}
-	database.InsertData(config.Configs.RcsOutputTable, database.DBtechWrite, dbMappedData)
+	if err := channelHelper.InsertOutput(ctx, config.Configs.RcsOutputTable, msg.CommId, dbMappedData); err != nil {
+		utils.ErrorCtx(ctx, fmt.Errorf("error inserting data into rcs output table: %v", err))
+	}
+	webhookService.Emit(msg, dbMappedData)
 
 	jsonBytes, _ := json.Marshal(response)
-	utils.Debug(fmt.Sprintf("RCS Response: %s", string(jsonBytes)))
+	utils.DebugCtx(ctx, fmt.Sprintf("RCS Response: %s", string(jsonBytes)))
 
 	if response.IsSent {
-		utils.Info(fmt.Sprintf("RCS sent successfully for Process: %s on %s via %s", msg.ProcessName, msg.Mobile, msg.Vendor))
+		utils.InfoCtx(ctx, fmt.Sprintf("RCS sent successfully for Process: %s on %s via %s", msg.ProcessName, msg.Mobile, msg.Vendor))
 		return true, nil
 	}
 	return true, nil // message processed but not sent as response.IsSent is false
@@ -1,37 +1,32 @@
 package sinchRcs
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"strings"
 
 	"github.com/wecredit/communication-sdk/config"
-	"github.com/wecredit/communication-sdk/helper"
+	"github.com/wecredit/communication-sdk/internal/channels/sinchAuth"
+	"github.com/wecredit/communication-sdk/internal/channels/vendorClient"
+	"github.com/wecredit/communication-sdk/internal/models/awsModels"
 	extapimodels "github.com/wecredit/communication-sdk/internal/models/extApiModels"
-	"github.com/wecredit/communication-sdk/pkg/cache"
+	"github.com/wecredit/communication-sdk/internal/vendorAccounts"
 	"github.com/wecredit/communication-sdk/sdk/queue"
 	"github.com/wecredit/communication-sdk/sdk/utils"
 	"github.com/wecredit/communication-sdk/sdk/variables"
 )
 
-func HitSinchRcsApi(data extapimodels.RcsRequestBody) extapimodels.RcsResponse {
+func HitSinchRcsApi(ctx context.Context, data extapimodels.RcsRequestBody) extapimodels.RcsResponse {
 	var responseBody extapimodels.RcsResponse
 	responseBody.IsSent = false
 	// rcsApiUrl := config.Configs.SinchRcsApiUrl
 	rcsApiUrl := fmt.Sprintf("%s%s%s", config.Configs.SinchRcsApiUrl, data.ProjectId, "/messages:send")
-	accessToken, ok := cache.GetAccessToken()
-	if !ok {
-		token, err := helper.GetNewToken()
-		if err != nil {
-			responseBody.ResponseMessage = fmt.Sprintf("%v", err)
-			return responseBody
-		}
-		cache.SetToken(token)
-		accessToken = token.AccessToken
-	}
-
-	apiHeaders := map[string]string{
-		"Content-Type":  "application/json",
-		"Authorization": "Bearer " + accessToken,
+	account, err := vendorAccounts.Resolve(data.Client, variables.RCS, variables.SINCH)
+	if err != nil {
+		utils.ErrorCtx(ctx, err)
+		responseBody.ResponseMessage = fmt.Sprintf("error occured in resolving Sinch RCS account: %v", err)
+		return responseBody
 	}
 
 	payload := extapimodels.SinchRcsPayload{
@@ -46,88 +41,96 @@ func HitSinchRcsApi(data extapimodels.RcsRequestBody) extapimodels.RcsResponse {
 	payload.Message.TemplateMessage.ChannelTemplate.RCS.TemplateId = data.TemplateName
 	payload.Message.TemplateMessage.ChannelTemplate.RCS.LanguageCode = "en"
 
-	apiResponse, err := utils.ApiHit(variables.PostMethod, rcsApiUrl, apiHeaders, "", "", payload, variables.ContentTypeJSON)
+	response, err := sinchAuth.Call(ctx, account, func(accessToken string) (*vendorClient.Response, error) {
+		return vendorClient.For(variables.SINCH).Do(ctx, vendorClient.Request{
+			CommId: data.CommId,
+			Method: variables.PostMethod,
+			URL:    rcsApiUrl,
+			Headers: map[string]string{
+				"Content-Type":  "application/json",
+				"Authorization": "Bearer " + accessToken,
+			},
+			Body:        payload,
+			ContentType: variables.ContentTypeJSON,
+		})
+	})
 	if err != nil {
-		utils.Error(fmt.Errorf("error occured while hitting into Sinch RCS API: %v", err))
-		if queueErr := queue.SendMessageWithSubject(queue.SQSClient, data, config.Configs.AwsErrorQueueUrl, variables.ApiHitsFails, err.Error()); queueErr != nil {
-			utils.Error(fmt.Errorf("error sending message to error queue: %v", queueErr))
+		utils.ErrorCtx(ctx, fmt.Errorf("error occured while hitting into Sinch RCS API: %v", err))
+		if queueErr := queue.SendMessageWithSubject(queue.SQSClient, awsModels.ApiHitFailure{CommId: data.CommId, Client: data.Client, Channel: variables.RCS, Vendor: variables.SINCH}, config.Configs.AwsErrorQueueUrl, variables.ApiHitsFails, err.Error()); queueErr != nil {
+			utils.ErrorCtx(ctx, fmt.Errorf("error sending message to error queue: %v", queueErr))
 		}
 		responseBody.ResponseMessage = fmt.Sprintf("error occured while hitting Sinch RCS payload: %v", err)
 		return responseBody
 	}
 
-	if apiResponse["ApistatusCode"].(int) == 200 {
-		utils.Info("RCS message sent successfully")
+	var apiResponse extapimodels.SinchRcsApiResponse
+	if err := response.Decode(&apiResponse); err != nil {
+		utils.ErrorCtx(ctx, fmt.Errorf("error occured while reading Sinch RCS API response: %v", err))
+		responseBody.ResponseMessage = fmt.Sprintf("unexpected Sinch RCS response: %v", err)
+		return responseBody
+	}
+
+	if response.StatusCode == http.StatusOK {
+		utils.InfoCtx(ctx, "RCS message sent successfully")
 		responseBody.IsSent = true
-		responseBody.TransactionId = apiResponse["message_id"].(string)
+		responseBody.TransactionId = apiResponse.MessageId
 		responseBody.ResponseMessage = "Message Submitted Successfully"
 		return responseBody
 	}
 
-	if apiResponse["ApistatusCode"].(int) != 200 {
-		errMap, ok := apiResponse["error"].(map[string]interface{})
-		if !ok {
-			utils.Error(fmt.Errorf("unexpected error format: %v", apiResponse["error"]))
-			responseBody.ResponseMessage = "Unknown error format"
-			return responseBody
-		}
-
-		var errorMsgs []string
-
-		// Step 1: Add the top-level message if present
-		if msg, ok := errMap["message"].(string); ok && msg != "" {
-			errorMsgs = append(errorMsgs, msg)
-		}
+	errMap := apiResponse.Error
+	if errMap == nil {
+		utils.ErrorCtx(ctx, fmt.Errorf("unexpected error format: %v", response.Error()))
+		responseBody.ResponseMessage = response.Error().Error()
+		return responseBody
+	}
 
-		// Step 2: Dynamically parse all details, regardless of type
-		if details, ok := errMap["details"].([]interface{}); ok {
-			for _, d := range details {
-				detail, ok := d.(map[string]interface{})
-				if !ok {
-					continue
-				}
+	var errorMsgs []string
 
-				// Extract generic description if available
-				if desc, ok := detail["description"].(string); ok && desc != "" {
-					errorMsgs = append(errorMsgs, desc)
-				}
+	// Step 1: Add the top-level message if present
+	if errMap.Message != "" {
+		errorMsgs = append(errorMsgs, errMap.Message)
+	}
 
-				// Extract ResourceInfo info
-				if resType, ok := detail["resource_type"].(string); ok {
-					resourceName := detail["resource_name"]
-					errorMsgs = append(errorMsgs, fmt.Sprintf("Missing resource: %v (%v)", resourceName, resType))
-				}
+	// Step 2: Dynamically parse all details, regardless of type
+	for _, detail := range errMap.Details {
+		// Extract generic description if available
+		if desc, ok := detail["description"].(string); ok && desc != "" {
+			errorMsgs = append(errorMsgs, desc)
+		}
 
-				// Extract BadRequest field_violations
-				if violations, ok := detail["field_violations"].([]interface{}); ok {
-					for _, v := range violations {
-						if violation, ok := v.(map[string]interface{}); ok {
-							field := violation["field"]
-							desc := violation["description"]
-							errorMsgs = append(errorMsgs, fmt.Sprintf("%v: %v", field, desc))
-						}
-					}
-				}
+		// Extract ResourceInfo info
+		if resType, ok := detail["resource_type"].(string); ok {
+			resourceName := detail["resource_name"]
+			errorMsgs = append(errorMsgs, fmt.Sprintf("Missing resource: %v (%v)", resourceName, resType))
+		}
 
-				// Catch any other unexpected structures
-				for k, v := range detail {
-					if k != "@type" && k != "field_violations" && k != "description" && k != "resource_type" && k != "resource_name" {
-						errorMsgs = append(errorMsgs, fmt.Sprintf("%v: %v", k, v))
-					}
+		// Extract BadRequest field_violations
+		if violations, ok := detail["field_violations"].([]interface{}); ok {
+			for _, v := range violations {
+				if violation, ok := v.(map[string]interface{}); ok {
+					field := violation["field"]
+					desc := violation["description"]
+					errorMsgs = append(errorMsgs, fmt.Sprintf("%v: %v", field, desc))
 				}
 			}
 		}
 
-		// Step 3: Fallback if still empty
-		finalErrMsg := strings.Join(errorMsgs, " | ")
-		if finalErrMsg == "" {
-			finalErrMsg = fmt.Sprintf("Error Code: %v, Status: %v", errMap["code"], errMap["status"])
+		// Catch any other unexpected structures
+		for k, v := range detail {
+			if k != "@type" && k != "field_violations" && k != "description" && k != "resource_type" && k != "resource_name" {
+				errorMsgs = append(errorMsgs, fmt.Sprintf("%v: %v", k, v))
+			}
 		}
+	}
 
-		utils.Error(fmt.Errorf("response failed with status: %v", finalErrMsg))
-		responseBody.ResponseMessage = finalErrMsg
-		return responseBody
+	// Step 3: Fallback if still empty
+	finalErrMsg := strings.Join(errorMsgs, " | ")
+	if finalErrMsg == "" {
+		finalErrMsg = fmt.Sprintf("Error Code: %v, Status: %v", errMap.Code, errMap.Status)
 	}
 
+	utils.ErrorCtx(ctx, fmt.Errorf("response failed with status: %v", finalErrMsg))
+	responseBody.ResponseMessage = finalErrMsg
 	return responseBody
 }
@@ -1,8 +1,12 @@
 package timesRcs
 
-import extapimodels "github.com/wecredit/communication-sdk/internal/models/extApiModels"
+import (
+	"context"
 
-func HitTimesRcsApi(data extapimodels.RcsRequestBody) extapimodels.RcsResponse {
+	extapimodels "github.com/wecredit/communication-sdk/internal/models/extApiModels"
+)
+
+func HitTimesRcsApi(ctx context.Context, data extapimodels.RcsRequestBody) extapimodels.RcsResponse {
 	var responseBody extapimodels.RcsResponse
 	return responseBody
 }
@@ -0,0 +1,161 @@
+// Package sinchAuth issues the OAuth access tokens of the Sinch APIs, caching one token per vendor account.
+package sinchAuth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/wecredit/communication-sdk/config"
+	"github.com/wecredit/communication-sdk/internal/channels/vendorClient"
+	extapimodels "github.com/wecredit/communication-sdk/internal/models/extApiModels"
+	"github.com/wecredit/communication-sdk/internal/vendorAccounts"
+	"github.com/wecredit/communication-sdk/sdk/utils"
+	"github.com/wecredit/communication-sdk/sdk/variables"
+	"golang.org/x/sync/singleflight"
+)
+
+type token struct {
+	accessToken string
+	refreshAt   time.Time // a request after this refreshes the token in the background
+	expiresAt   time.Time // a request after this waits for a new token
+}
+
+var (
+	mu     sync.Mutex
+	tokens = map[string]token{} // accountKey -> token
+	group  singleflight.Group
+)
+
+// accountKey identifies the account a token belongs to; accounts configured through environment variables have Id 0.
+func accountKey(account vendorAccounts.Account) string {
+	return fmt.Sprintf("%d|%s", account.Id, account.Username)
+}
+
+// AccessToken returns a valid access token of the account. A token close to expiry is refreshed in the background
+// while it is still handed out, and concurrent refreshes of one account share a single token request. The refresh
+// keeps the trace of ctx but not its cancellation, since other callers may be waiting for the same token.
+func AccessToken(ctx context.Context, account vendorAccounts.Account) (string, error) {
+	refreshCtx := context.WithoutCancel(ctx)
+	key := accountKey(account)
+	now := time.Now()
+
+	mu.Lock()
+	cached, ok := tokens[key]
+	mu.Unlock()
+
+	if ok && now.Before(cached.expiresAt) {
+		if !now.Before(cached.refreshAt) {
+			group.DoChan(key, func() (interface{}, error) { return refresh(refreshCtx, key, account) })
+		}
+		return cached.accessToken, nil
+	}
+
+	result := group.DoChan(key, func() (interface{}, error) { return refresh(refreshCtx, key, account) })
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case fresh := <-result:
+		if fresh.Err != nil {
+			return "", fresh.Err
+		}
+		return fresh.Val.(string), nil
+	}
+}
+
+// Invalidate drops the token of the account unless it has already been replaced by a newer one.
+func Invalidate(account vendorAccounts.Account, accessToken string) {
+	key := accountKey(account)
+	mu.Lock()
+	defer mu.Unlock()
+	if tokens[key].accessToken == accessToken {
+		delete(tokens, key)
+	}
+}
+
+// Call runs call with an access token of the account. When the vendor answers 401 the token is invalidated and
+// call is retried once with a new one.
+func Call(ctx context.Context, account vendorAccounts.Account, call func(accessToken string) (*vendorClient.Response, error)) (*vendorClient.Response, error) {
+	accessToken, err := AccessToken(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+	response, err := call(accessToken)
+	if err != nil || response.StatusCode != http.StatusUnauthorized {
+		return response, err
+	}
+
+	utils.Warn(fmt.Sprintf("Sinch rejected the access token of vendor account %d, retrying with a new token", account.Id))
+	Invalidate(account, accessToken)
+	if accessToken, err = AccessToken(ctx, account); err != nil {
+		return nil, err
+	}
+	return call(accessToken)
+}
+
+func refresh(ctx context.Context, key string, account vendorAccounts.Account) (string, error) {
+	tokenURL := config.Configs.SinchWhatsappTokenApiUrl
+	if tokenURL == "" {
+		return "", errors.New("SINCH_GENERATE_TOKEN_API_URL is not set")
+	}
+
+	requestedAt := time.Now()
+	response, err := vendorClient.For(variables.SINCH).Do(ctx, vendorClient.Request{
+		Method: variables.PostMethod,
+		URL:    tokenURL,
+		Headers: map[string]string{
+			"Cache-Control": "no-cache",
+		},
+		Body: map[string]string{
+			"grant_type": config.Configs.SinchWhatsappGrantType,
+			"client_id":  config.Configs.SinchWhatsappClientId,
+			"username":   account.Username,
+			"password":   account.Password,
+		},
+		ContentType: variables.ContentTypeFormEncoded,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error occured while hitting into Sinch Generate Token API: %v", err)
+	}
+	if !response.OK() {
+		return "", fmt.Errorf("sinch generate token API failed for vendor account %d: %w", account.Id, response.Error())
+	}
+	var tokenResponse extapimodels.SinchTokenResponse
+	if err := response.Decode(&tokenResponse); err != nil {
+		return "", fmt.Errorf("sinch generate token API failed for vendor account %d: %w", account.Id, err)
+	}
+	if tokenResponse.AccessToken == "" {
+		return "", fmt.Errorf("sinch generate token API returned no access token for vendor account %d", account.Id)
+	}
+
+	// Lifetimes are measured from the request, so network time only makes the token refresh early
+	lifetime := time.Hour
+	if tokenResponse.ExpiresIn > 0 {
+		lifetime = time.Duration(tokenResponse.ExpiresIn) * time.Second
+	}
+	margin := refreshMargin()
+	if margin > lifetime/2 {
+		margin = lifetime / 2
+	}
+
+	mu.Lock()
+	tokens[key] = token{
+		accessToken: tokenResponse.AccessToken,
+		refreshAt:   requestedAt.Add(lifetime - margin),
+		expiresAt:   requestedAt.Add(lifetime),
+	}
+	mu.Unlock()
+	return tokenResponse.AccessToken, nil
+}
+
+func refreshMargin() time.Duration {
+	seconds, err := strconv.Atoi(config.Configs.SinchTokenRefreshMarginSeconds)
+	if err != nil || seconds < 0 {
+		seconds = 60
+	}
+	return time.Duration(seconds) * time.Second
+}
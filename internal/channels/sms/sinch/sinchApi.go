@@ -1,17 +1,21 @@
 package sinchSms
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/wecredit/communication-sdk/config"
 	sinchpayloads "github.com/wecredit/communication-sdk/internal/channels/sms/sinch/sinchPayloads"
+	"github.com/wecredit/communication-sdk/internal/channels/vendorClient"
+	"github.com/wecredit/communication-sdk/internal/models/awsModels"
 	extapimodels "github.com/wecredit/communication-sdk/internal/models/extApiModels"
+	"github.com/wecredit/communication-sdk/internal/vendorAccounts"
 	"github.com/wecredit/communication-sdk/sdk/queue"
 	"github.com/wecredit/communication-sdk/sdk/utils"
 	"github.com/wecredit/communication-sdk/sdk/variables"
 )
 
-func HitSinchSmsApi(data extapimodels.SmsRequestBody) extapimodels.SmsResponse {
+func HitSinchSmsApi(ctx context.Context, data extapimodels.SmsRequestBody) extapimodels.SmsResponse {
 	var sinchSmsResponse extapimodels.SmsResponse
 	sinchSmsResponse.IsSent = false
 
@@ -23,45 +27,55 @@ func HitSinchSmsApi(data extapimodels.SmsRequestBody) extapimodels.SmsResponse {
 		"Content-Type": "application/json",
 	}
 
+	account, err := vendorAccounts.Resolve(data.Client, variables.SMS, variables.SINCH)
+	if err != nil {
+		utils.ErrorCtx(ctx, err)
+		sinchSmsResponse.ResponseMessage = fmt.Sprintf("error occured in resolving Sinch SMS account: %v", err)
+		return sinchSmsResponse
+	}
+
 	// Get api payload
-	apiPayload, err := sinchpayloads.GetTemplatePayload(data, config.Configs)
+	apiPayload, err := sinchpayloads.GetTemplatePayload(data, account)
 	if err != nil {
-		utils.Error(fmt.Errorf("error occured while getting SMS payload: %v", err))
+		utils.ErrorCtx(ctx, fmt.Errorf("error occured while getting SMS payload: %v", err))
 		sinchSmsResponse.ResponseMessage = fmt.Sprintf("error occured in Sinch SMS payload: %v for %s", err, data.Client)
 		return sinchSmsResponse
 	}
 
-	apiResponse, err := utils.ApiHit(variables.PostMethod, apiUrl, apiHeader, "", "", apiPayload, variables.ContentTypeJSON)
+	response, err := vendorClient.For(variables.SINCH).Do(ctx, vendorClient.Request{
+		CommId:      data.CommId,
+		Method:      variables.PostMethod,
+		URL:         apiUrl,
+		Headers:     apiHeader,
+		Body:        apiPayload,
+		ContentType: variables.ContentTypeJSON,
+	})
 	if err != nil {
-		utils.Error(fmt.Errorf("error occured while hitting into Sinch SMS API: %v", err))
-		if queueErr := queue.SendMessageWithSubject(queue.SQSClient, data, config.Configs.AwsErrorQueueUrl, variables.ApiHitsFails, err.Error()); queueErr != nil {
-			utils.Error(fmt.Errorf("error sending message to error queue: %v", queueErr))
+		utils.ErrorCtx(ctx, fmt.Errorf("error occured while hitting into Sinch SMS API: %v", err))
+		if queueErr := queue.SendMessageWithSubject(queue.SQSClient, awsModels.ApiHitFailure{CommId: data.CommId, Client: data.Client, Channel: variables.SMS, Vendor: variables.SINCH}, config.Configs.AwsErrorQueueUrl, variables.ApiHitsFails, err.Error()); queueErr != nil {
+			utils.ErrorCtx(ctx, fmt.Errorf("error sending message to error queue: %v", queueErr))
 		}
 		sinchSmsResponse.ResponseMessage = fmt.Sprintf("error occured while hitting Sinch SMS payload: %v", err)
 		return sinchSmsResponse
 	}
 
-	accepted := apiResponse["accepted"].(bool)
+	var apiResponse extapimodels.SinchSmsApiResponse
+	if err := response.Decode(&apiResponse); err != nil {
+		utils.ErrorCtx(ctx, fmt.Errorf("error occured while reading Sinch SMS API response: %v", err))
+		sinchSmsResponse.ResponseMessage = fmt.Sprintf("unexpected Sinch SMS response: %v", err)
+		return sinchSmsResponse
+	}
 
-	if accepted {
-		sinchSmsResponse.TransactionId = apiResponse["respid"].(string)
+	if apiResponse.Accepted {
+		sinchSmsResponse.TransactionId = apiResponse.RespId.String()
 		sinchSmsResponse.IsSent = true
 		sinchSmsResponse.ResponseMessage = "Message Submitted Successfully"
+	} else if apiResponse.Error != "" {
+		sinchSmsResponse.ResponseMessage = GetRejectionReason(apiResponse.Error.String())
 	} else {
-		sinchSmsResponse.ResponseMessage = GetRejectionReason(apiResponse["error"].(string))
+		sinchSmsResponse.ResponseMessage = response.Error().Error()
 	}
 
-	// TODO Handling For Api Responses
-
-	// if code, ok := apiResponse["code"].(float64); ok {
-	// 	response.StatusCode = code
-	// } else {
-	// 	return response, fmt.Errorf("unexpected type for code: %T", apiResponse["code"])
-	// }
-	// response.Message = apiResponse["message"].(string)
-	// response.Status = apiResponse["status"].(bool)
-	fmt.Println("Sinch SMS Final response:", sinchSmsResponse)
-
 	return sinchSmsResponse
 }
 
@@ -1,6 +1,7 @@
 package sms
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -16,65 +17,67 @@ import (
 	"github.com/wecredit/communication-sdk/sdk/variables"
 )
 
-func SendSmsByProcess(msg sdkModels.CommApiRequestBody) (bool, map[string]interface{}, error) {
-	templateDetails, found := cache.GetCache().GetMappedData(cache.TemplateDetailsData)
-	if !found {
+func SendSmsByProcess(ctx context.Context, msg sdkModels.CommApiRequestBody) (bool, map[string]interface{}, error) {
+	snapshot := cache.Current()
+	if !snapshot.Loaded(cache.TemplateDetailsData) {
 		return false, nil, errors.New("template data not found in cache")
 	}
-	templateData, matchedVendor, err := channelHelper.FetchTemplateData(msg, templateDetails)
+	templateData, matchedVendor, resolution, err := channelHelper.ResolveTemplate(ctx, msg, snapshot)
 	if err != nil {
-		return channelHelper.HandleTemplateNotFoundError(msg, err)
+		return channelHelper.HandleTemplateNotFoundError(msg, resolution, err)
 	}
 
 	msg.Vendor = matchedVendor
+	ctx = utils.WithVendor(ctx, msg.Vendor)
 
 	req := extapimodels.SmsRequestBody{
-		Mobile:            msg.Mobile,
-		Process:           msg.ProcessName,
-		Client:            msg.Client,
-		EmiAmount:         msg.EmiAmount,
-		CustomerName:      msg.CustomerName,
-		LoanId:            msg.LoanId,
-		ApplicationNumber: msg.ApplicationNumber,
-		DueDate:           msg.DueDate,
-		Description:       msg.Description,
+		CommId:      msg.CommId,
+		Mobile:      msg.Mobile,
+		Process:     msg.ProcessName,
+		Client:      msg.Client,
+		Variables:   msg.TemplateVariables(),
+		Description: msg.Description,
 	}
 	channelHelper.PopulateSmsFields(&req, templateData)
+	if err := channelHelper.RenderSmsRequest(&req); err != nil {
+		return channelHelper.HandleTemplateRenderError(msg, resolution, err)
+	}
 
 	var response extapimodels.SmsResponse
 
 	// Check if the vendor should be hit
 	shouldHitVendor := channelHelper.ShouldHitVendor(msg.Client, msg.Channel)
-	utils.Debug(fmt.Sprintf("Channel: %s Mobile: %s, Should hit vendor: %v\n", msg.Channel, msg.Mobile, shouldHitVendor))
+	utils.DebugCtx(ctx, fmt.Sprintf("Channel: %s Mobile: %s, Should hit vendor: %v\n", msg.Channel, msg.Mobile, shouldHitVendor))
 	if shouldHitVendor {
 		switch msg.Vendor {
 		case variables.TIMES:
-			response = timesSms.HitTimesSmsApi(req)
+			response = timesSms.HitTimesSmsApi(ctx, req)
 		case variables.SINCH:
-			response = sinchSms.HitSinchSmsApi(req)
+			response = sinchSms.HitSinchSmsApi(ctx, req)
 		}
 	}
 
 	// Step 2: Once you have responseId, update the value of transactionId in redis
 	if err := channelHelper.UpdateRedisTransactionId(msg.Mobile, msg.Channel, msg.Stage, response.TransactionId); err != nil {
-		utils.Error(fmt.Errorf("failed to update Redis transactionId: %v", err))
+		utils.ErrorCtx(ctx, fmt.Errorf("failed to update Redis transactionId: %v", err))
 	}
 
 	response.DltTemplateId = req.DltTemplateId
 	response.CommId = msg.CommId
+	response.TemplateResolution = resolution.String()
 	response.Vendor = msg.Vendor
 	response.MobileNumber = msg.Mobile
 
 	dbMappedData, err := services.MapIntoDbModel(response)
 	if err != nil {
-		utils.Error(fmt.Errorf("mapping error: %v", err))
+		utils.ErrorCtx(ctx, fmt.Errorf("mapping error: %v", err))
 	}
 
 	jsonBytes, _ := json.Marshal(response)
-	utils.Debug(fmt.Sprintf("SMS Response: %s", string(jsonBytes)))
+	utils.DebugCtx(ctx, fmt.Sprintf("SMS Response: %s", string(jsonBytes)))
 
 	if shouldHitVendor && response.IsSent {
-		utils.Info(fmt.Sprintf("SMS sent successfully for Process: %s on %s via %s", msg.ProcessName, msg.Mobile, msg.Vendor))
+		utils.InfoCtx(ctx, fmt.Sprintf("SMS sent successfully for Process: %s on %s via %s", msg.ProcessName, msg.Mobile, msg.Vendor))
 		return true, dbMappedData, nil
 	}
 
@@ -82,11 +85,11 @@ func SendSmsByProcess(msg sdkModels.CommApiRequestBody) (bool, map[string]interf
 		// Step 2: Once you have error message, update the error message in redis
 		dbMappedData["ResponseMessage"] = "shouldHitVendor is off for mobile " + msg.Mobile
 		if err := channelHelper.HandleShouldHitVendorOffError(msg.Mobile, msg.Channel, msg.Stage); err != nil {
-			utils.Error(fmt.Errorf("failed to handle shouldHitVendor off error: %v", err))
+			utils.ErrorCtx(ctx, fmt.Errorf("failed to handle shouldHitVendor off error: %v", err))
 		}
 	}
 
-	utils.Info(fmt.Sprintf("SMS sent successfully for Process: %s on %s via %s", msg.ProcessName, msg.Mobile, msg.Vendor))
+	utils.InfoCtx(ctx, fmt.Sprintf("SMS sent successfully for Process: %s on %s via %s", msg.ProcessName, msg.Mobile, msg.Vendor))
 	return true, dbMappedData, nil
 	// if err := database.InsertData(config.Configs.SmsOutputTable, database.DBtech, dbMappedData); err != nil {
 	// 	utils.Error(fmt.Errorf("error inserting data into table: %v", err))
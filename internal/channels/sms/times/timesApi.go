@@ -1,17 +1,21 @@
 package timesSms
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/wecredit/communication-sdk/config"
 	timespayloads "github.com/wecredit/communication-sdk/internal/channels/sms/times/timesPayloads"
+	"github.com/wecredit/communication-sdk/internal/channels/vendorClient"
+	"github.com/wecredit/communication-sdk/internal/models/awsModels"
 	extapimodels "github.com/wecredit/communication-sdk/internal/models/extApiModels"
+	"github.com/wecredit/communication-sdk/internal/vendorAccounts"
 	"github.com/wecredit/communication-sdk/sdk/queue"
 	"github.com/wecredit/communication-sdk/sdk/utils"
 	"github.com/wecredit/communication-sdk/sdk/variables"
 )
 
-func HitTimesSmsApi(data extapimodels.SmsRequestBody) extapimodels.SmsResponse {
+func HitTimesSmsApi(ctx context.Context, data extapimodels.SmsRequestBody) extapimodels.SmsResponse {
 	var timesSmsResponse extapimodels.SmsResponse
 	timesSmsResponse.IsSent = false
 
@@ -30,34 +34,56 @@ func HitTimesSmsApi(data extapimodels.SmsRequestBody) extapimodels.SmsResponse {
 		"Content-Type": "application/json",
 	}
 
+	account, err := vendorAccounts.Resolve(data.Client, variables.SMS, variables.TIMES)
+	if err != nil {
+		utils.ErrorCtx(ctx, err)
+		timesSmsResponse.ResponseMessage = fmt.Sprintf("Error in resolving Times SMS account: %v", err)
+		return timesSmsResponse
+	}
+
 	// Get api payload
-	apiPayload, err := timespayloads.GetTemplatePayload(data, config.Configs)
+	apiPayload, err := timespayloads.GetTemplatePayload(data, account)
 	if err != nil {
-		utils.Error(fmt.Errorf("error occured while getting SMS payload: %v", err))
+		utils.ErrorCtx(ctx, fmt.Errorf("error occured while getting SMS payload: %v", err))
 		timesSmsResponse.ResponseMessage = fmt.Sprintf("Error in getting Times SMS Payload: %v", err)
 	}
 
-	apiResponse, err := utils.ApiHit(variables.PostMethod, apiUrl, apiHeader, config.Configs.TimesSmsApiUserName, config.Configs.TimesSmsApiPassword, apiPayload, variables.ContentTypeJSON)
+	response, err := vendorClient.For(variables.TIMES).Do(ctx, vendorClient.Request{
+		CommId:      data.CommId,
+		Method:      variables.PostMethod,
+		URL:         apiUrl,
+		Headers:     apiHeader,
+		Username:    account.Username,
+		Password:    account.Password,
+		Body:        apiPayload,
+		ContentType: variables.ContentTypeJSON,
+	})
 	if err != nil {
-		utils.Error(fmt.Errorf("error occured while hitting into Times Sms API: %v", err))
-		if queueErr := queue.SendMessageWithSubject(queue.SQSClient, data, config.Configs.AwsErrorQueueUrl, variables.ApiHitsFails, err.Error()); queueErr != nil {
-			utils.Error(fmt.Errorf("error sending message to error queue: %v", queueErr))
+		utils.ErrorCtx(ctx, fmt.Errorf("error occured while hitting into Times Sms API: %v", err))
+		if queueErr := queue.SendMessageWithSubject(queue.SQSClient, awsModels.ApiHitFailure{CommId: data.CommId, Client: data.Client, Channel: variables.SMS, Vendor: variables.TIMES}, config.Configs.AwsErrorQueueUrl, variables.ApiHitsFails, err.Error()); queueErr != nil {
+			utils.ErrorCtx(ctx, fmt.Errorf("error sending message to error queue: %v", queueErr))
 		}
 		timesSmsResponse.ResponseMessage = fmt.Sprintf("Error in hitting Times SMS API: %v", err)
 		return timesSmsResponse
 	}
 
-	status := apiResponse["state"].(string)
-	description := apiResponse["description"].(string)
+	var apiResponse extapimodels.TimesSmsApiResponse
+	if err := response.Decode(&apiResponse); err != nil {
+		utils.ErrorCtx(ctx, fmt.Errorf("error occured while reading Times Sms API response: %v", err))
+		timesSmsResponse.ResponseMessage = fmt.Sprintf("Unexpected Times SMS response: %v", err)
+		return timesSmsResponse
+	}
+	if apiResponse.State == "" && !response.OK() {
+		timesSmsResponse.ResponseMessage = response.Error().Error()
+		return timesSmsResponse
+	}
 
-	timesSmsResponse.ResponseMessage = fmt.Sprintf("%s:%s", status, description)
-	timesSmsResponse.TransactionId = fmt.Sprintf("%d", int(apiResponse["transactionId"].(float64)))
+	timesSmsResponse.ResponseMessage = fmt.Sprintf("%s:%s", apiResponse.State, apiResponse.Description)
+	timesSmsResponse.TransactionId = apiResponse.TransactionId.String()
 
-	if status == "SUBMIT_ACCEPTED" {
+	if apiResponse.State == "SUBMIT_ACCEPTED" {
 		timesSmsResponse.IsSent = true
 	}
 
-	fmt.Println("TimesSMSResponseFinal:", timesSmsResponse)
-
 	return timesSmsResponse
 }
@@ -4,7 +4,7 @@ import (
 	"fmt"
 
 	extapimodels "github.com/wecredit/communication-sdk/internal/models/extApiModels"
-	models "github.com/wecredit/communication-sdk/sdk/models"
+	"github.com/wecredit/communication-sdk/internal/vendorAccounts"
 )
 
 func verifyMobile(mobile string) string {
@@ -14,7 +14,7 @@ func verifyMobile(mobile string) string {
 	return ""
 }
 
-func GetTemplatePayload(data extapimodels.SmsRequestBody, config models.Config) (map[string]interface{}, error) {
+func GetTemplatePayload(data extapimodels.SmsRequestBody, account vendorAccounts.Account) (map[string]interface{}, error) {
 	templatePayload := map[string]interface{}{
 		"extra": map[string]string{
 			"dltContentId": fmt.Sprintf("%d", data.DltTemplateId),
@@ -23,7 +23,7 @@ func GetTemplatePayload(data extapimodels.SmsRequestBody, config models.Config)
 			"recipient": fmt.Sprintf("91%s", verifyMobile(data.Mobile)),
 			"text":      data.TemplateText,
 		},
-		"sender":  config.TimesSmsApiSender,
+		"sender":  account.Sender,
 		"unicode": "False",
 	}
 	return templatePayload, nil
@@ -0,0 +1,303 @@
+// Package vendorClient is the HTTP layer of the vendor adapters. Every vendor shares one pooled transport and has its
+// own timeout and retry policy; responses keep their raw status code and body so failures can be reported as sent.
+package vendorClient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/wecredit/communication-sdk/config"
+	"github.com/wecredit/communication-sdk/internal/metrics"
+	"github.com/wecredit/communication-sdk/internal/tracing"
+	"github.com/wecredit/communication-sdk/internal/vendorAudit"
+	"github.com/wecredit/communication-sdk/sdk/utils"
+	"github.com/wecredit/communication-sdk/sdk/variables"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxLoggedBody caps how much of a body is kept in error messages.
+const maxLoggedBody = 2048
+
+// transport is shared by every vendor so connections to the same host are reused across messages.
+var transport = &http.Transport{
+	Proxy:                 http.ProxyFromEnvironment,
+	DialContext:           (&net.Dialer{Timeout: 5 * time.Second, KeepAlive: 30 * time.Second}).DialContext,
+	ForceAttemptHTTP2:     true,
+	MaxIdleConns:          200,
+	MaxIdleConnsPerHost:   50,
+	IdleConnTimeout:       90 * time.Second,
+	TLSHandshakeTimeout:   5 * time.Second,
+	ExpectContinueTimeout: 1 * time.Second,
+}
+
+// Policy is the timeout and retry behaviour of the requests to one vendor.
+type Policy struct {
+	Timeout      time.Duration // per attempt
+	RetryMax     int           // retries after the first attempt, on connection errors and 5xx/429 answers
+	RetryWaitMin time.Duration
+	RetryWaitMax time.Duration
+}
+
+// Client sends requests to one vendor.
+type Client struct {
+	vendor string
+	policy Policy
+	http   *retryablehttp.Client
+}
+
+var (
+	clientsMu sync.Mutex
+	clients   = map[string]*Client{}
+)
+
+// For returns the client of the vendor, created with its configured policy on first use.
+func For(vendor string) *Client {
+	vendor = strings.ToUpper(strings.TrimSpace(vendor))
+
+	clientsMu.Lock()
+	defer clientsMu.Unlock()
+	if client, ok := clients[vendor]; ok {
+		return client
+	}
+	client := New(vendor, PolicyFor(vendor))
+	clients[vendor] = client
+	return client
+}
+
+// New returns a client for the vendor with the given policy on the shared transport.
+func New(vendor string, policy Policy) *Client {
+	httpClient := retryablehttp.NewClient()
+	httpClient.HTTPClient = &http.Client{Transport: transport, Timeout: policy.Timeout}
+	httpClient.RetryMax = policy.RetryMax
+	httpClient.RetryWaitMin = policy.RetryWaitMin
+	httpClient.RetryWaitMax = policy.RetryWaitMax
+	httpClient.Logger = nil
+	httpClient.RequestLogHook = func(_ retryablehttp.Logger, req *http.Request, attempt int) {
+		if attempt > 0 {
+			utils.WarnCtx(req.Context(), fmt.Sprintf("retrying %s %s at %s, attempt %d", req.Method, req.URL.Path, vendor, attempt+1))
+		}
+	}
+	// The last response is returned instead of an error, so its status code and body are not lost
+	httpClient.ErrorHandler = retryablehttp.PassthroughErrorHandler
+
+	return &Client{vendor: vendor, policy: policy, http: httpClient}
+}
+
+// PolicyFor returns the configured policy of the vendor; <VENDOR>_HTTP_* settings override the VENDOR_HTTP_* defaults.
+func PolicyFor(vendor string) Policy {
+	c := config.Configs
+	timeout := secondsOrDefault(c.VendorHttpTimeoutSeconds, 10*time.Second)
+	retryMax := intOrDefault(c.VendorHttpRetryMax, 0)
+
+	switch vendor {
+	case variables.SINCH:
+		timeout = secondsOrDefault(c.SinchHttpTimeoutSeconds, timeout)
+		retryMax = intOrDefault(c.SinchHttpRetryMax, retryMax)
+	case variables.TIMES:
+		timeout = secondsOrDefault(c.TimesHttpTimeoutSeconds, timeout)
+		retryMax = intOrDefault(c.TimesHttpRetryMax, retryMax)
+	}
+
+	return Policy{
+		Timeout:      timeout,
+		RetryMax:     retryMax,
+		RetryWaitMin: 200 * time.Millisecond,
+		RetryWaitMax: 2 * time.Second,
+	}
+}
+
+// Request is one call to a vendor API.
+type Request struct {
+	CommId      string // of the message the request is sent for, kept in the vendor audit log
+	Method      string
+	URL         string
+	Headers     map[string]string
+	Username    string // basic auth, when set together with Password
+	Password    string
+	Body        interface{} // map[string]string for form encoding, string for text, anything else is sent as JSON
+	ContentType int         // variables.ContentType*
+}
+
+// Response is the raw answer of a vendor.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// OK reports whether the vendor answered with a 2xx status.
+func (r *Response) OK() bool {
+	return r.StatusCode >= 200 && r.StatusCode < 300
+}
+
+// Decode unmarshals the JSON body into v. A body that is not JSON is reported with its status code and content.
+func (r *Response) Decode(v interface{}) error {
+	if err := json.Unmarshal(r.Body, v); err != nil {
+		return &StatusError{StatusCode: r.StatusCode, Body: truncate(r.Body), Err: fmt.Errorf("response is not valid JSON: %w", err)}
+	}
+	return nil
+}
+
+// Error returns a StatusError describing the response.
+func (r *Response) Error() *StatusError {
+	return &StatusError{StatusCode: r.StatusCode, Body: truncate(r.Body)}
+}
+
+// StatusError is a vendor answer that could not be used, with its raw status code and body.
+type StatusError struct {
+	StatusCode int
+	Body       string
+	Err        error
+}
+
+func (e *StatusError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("status %d: %v: %s", e.StatusCode, e.Err, e.Body)
+	}
+	return fmt.Sprintf("status %d: %s", e.StatusCode, e.Body)
+}
+
+func (e *StatusError) Unwrap() error {
+	return e.Err
+}
+
+// Do sends the request and records it in the vendor audit log. Only transport failures are errors; every answer of
+// the vendor, whatever its status, is a Response.
+func (c *Client) Do(ctx context.Context, req Request) (response *Response, err error) {
+	ctx, span := tracing.Start(ctx, "vendor.request", trace.SpanKindClient,
+		tracing.AttrVendor.String(c.vendor),
+		tracing.AttrCommId.String(req.CommId),
+		attribute.String("http.request.method", strings.ToUpper(req.Method)),
+	)
+	defer func() {
+		spanErr := err
+		if response != nil {
+			span.SetAttributes(attribute.Int("http.response.status_code", response.StatusCode))
+			if spanErr == nil && response.StatusCode >= http.StatusBadRequest {
+				spanErr = fmt.Errorf("%s answered with status %d", c.vendor, response.StatusCode)
+			}
+		}
+		tracing.End(span, spanErr)
+	}()
+
+	body, err := encodeBody(req.Body, req.ContentType)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := retryablehttp.NewRequestWithContext(ctx, strings.ToUpper(req.Method), req.URL, body)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %v", err)
+	}
+	if req.Username != "" && req.Password != "" {
+		httpReq.SetBasicAuth(req.Username, req.Password)
+	}
+	for key, value := range req.Headers {
+		httpReq.Header.Set(key, value)
+	}
+	span.SetAttributes(attribute.String("server.address", httpReq.URL.Host), attribute.String("url.path", httpReq.URL.Path))
+	switch req.ContentType {
+	case variables.ContentTypeFormEncoded:
+		httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	case variables.ContentTypeJSON:
+		if httpReq.Header.Get("Content-Type") == "" {
+			httpReq.Header.Set("Content-Type", "application/json")
+		}
+	}
+
+	start := time.Now()
+	response, err = c.send(httpReq)
+	call := vendorAudit.Call{
+		CommId:      req.CommId,
+		Vendor:      c.vendor,
+		Method:      httpReq.Method,
+		URL:         req.URL,
+		Latency:     time.Since(start),
+		Err:         err,
+		RequestBody: body,
+	}
+	if response != nil {
+		call.StatusCode = response.StatusCode
+		call.ResponseBody = response.Body
+	}
+	vendorAudit.Record(call)
+	metrics.ObserveVendorCall(c.vendor, call.StatusCode, call.Latency, err)
+	return response, err
+}
+
+func (c *Client) send(httpReq *retryablehttp.Request) (*Response, error) {
+	resp, err := c.http.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("error sending request to %s: %v", c.vendor, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body of %s: %v", c.vendor, err)
+	}
+	return &Response{StatusCode: resp.StatusCode, Header: resp.Header, Body: respBody}, nil
+}
+
+func encodeBody(data interface{}, contentType int) ([]byte, error) {
+	if data == nil {
+		return nil, nil
+	}
+	switch contentType {
+	case variables.ContentTypeFormEncoded:
+		formData, ok := data.(map[string]string)
+		if !ok {
+			return nil, fmt.Errorf("data must be of type map[string]string for form encoding")
+		}
+		formValues := url.Values{}
+		for key, value := range formData {
+			formValues.Set(key, value)
+		}
+		return []byte(formValues.Encode()), nil
+	case variables.ContentTypeText:
+		rawData, ok := data.(string)
+		if !ok {
+			return nil, fmt.Errorf("data must be a string for Content-Type text/plain")
+		}
+		return []byte(rawData), nil
+	}
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling data: %v", err)
+	}
+	return jsonData, nil
+}
+
+func truncate(body []byte) string {
+	if len(body) > maxLoggedBody {
+		return string(body[:maxLoggedBody]) + "..."
+	}
+	return string(body)
+}
+
+func secondsOrDefault(value string, defaultValue time.Duration) time.Duration {
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds <= 0 {
+		return defaultValue
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func intOrDefault(value string, defaultValue int) int {
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 0 {
+		return defaultValue
+	}
+	return parsed
+}
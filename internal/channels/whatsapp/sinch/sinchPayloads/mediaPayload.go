@@ -6,6 +6,7 @@ import (
 
 	"github.com/wecredit/communication-sdk/config"
 	"github.com/wecredit/communication-sdk/helper"
+	"github.com/wecredit/communication-sdk/internal/channels/channelHelper"
 	extapimodels "github.com/wecredit/communication-sdk/internal/models/extApiModels"
 )
 
@@ -29,7 +30,7 @@ func GetSinchMediaPayload(sinchApiModel extapimodels.WhatsappRequestBody) map[st
 				"policy": "deterministic",
 				"code":   "en_US",
 			},
-			"components": []map[string]interface{}{
+			"components": channelHelper.WithWhatsappBody([]map[string]interface{}{
 				{
 					"type": "header",
 					"parameters": []map[string]interface{}{
@@ -52,7 +53,7 @@ func GetSinchMediaPayload(sinchApiModel extapimodels.WhatsappRequestBody) map[st
 						},
 					},
 				},
-			},
+			}, sinchApiModel.BodyParameters),
 		},
 		"metadata": map[string]interface{}{
 			"messageId": strconv.Itoa(helper.GenerateRandomID(100000, 999999)),
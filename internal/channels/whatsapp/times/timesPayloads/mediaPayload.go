@@ -3,6 +3,7 @@ package timesWhatsappPayload
 import (
 	"strings"
 
+	"github.com/wecredit/communication-sdk/internal/channels/channelHelper"
 	extapimodels "github.com/wecredit/communication-sdk/internal/models/extApiModels"
 )
 
@@ -31,7 +32,7 @@ func GetTimesMediaPayload(timesApiModel extapimodels.WhatsappRequestBody) (map[s
 					"language": map[string]interface{}{
 						"code": "en_us",
 					},
-					"components": []map[string]interface{}{
+					"components": channelHelper.WithWhatsappBody([]map[string]interface{}{
 						{
 							"type": "header",
 							"parameters": []map[string]interface{}{
@@ -65,7 +66,7 @@ func GetTimesMediaPayload(timesApiModel extapimodels.WhatsappRequestBody) (map[s
 								},
 							},
 						},
-					},
+					}, timesApiModel.BodyParameters),
 				},
 			}, nil
 		} else {
@@ -79,7 +80,7 @@ func GetTimesMediaPayload(timesApiModel extapimodels.WhatsappRequestBody) (map[s
 					"language": map[string]interface{}{
 						"code": "en_us",
 					},
-					"components": []map[string]interface{}{
+					"components": channelHelper.WithWhatsappBody([]map[string]interface{}{
 						{
 							"type": "header",
 							"parameters": []map[string]interface{}{
@@ -102,7 +103,7 @@ func GetTimesMediaPayload(timesApiModel extapimodels.WhatsappRequestBody) (map[s
 								},
 							},
 						},
-					},
+					}, timesApiModel.BodyParameters),
 				},
 			}, nil
 		}
@@ -118,7 +119,7 @@ func GetTimesMediaPayload(timesApiModel extapimodels.WhatsappRequestBody) (map[s
 				"language": map[string]interface{}{
 					"code": "en_us",
 				},
-				"components": []map[string]interface{}{
+				"components": channelHelper.WithWhatsappBody([]map[string]interface{}{
 					{
 						"type": "header",
 						"parameters": []map[string]interface{}{
@@ -130,7 +131,7 @@ func GetTimesMediaPayload(timesApiModel extapimodels.WhatsappRequestBody) (map[s
 							},
 						},
 					},
-				},
+				}, timesApiModel.BodyParameters),
 			},
 		}, nil
 	}
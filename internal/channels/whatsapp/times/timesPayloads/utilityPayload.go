@@ -4,14 +4,13 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/wecredit/communication-sdk/internal/channels/channelHelper"
 	extapimodels "github.com/wecredit/communication-sdk/internal/models/extApiModels"
 )
 
 func GetTimesUtilityPayload(timesApiModel extapimodels.WhatsappRequestBody) (map[string]interface{}, error) {
 	buttonURL := timesApiModel.ButtonLink
 
-	fmt.Println("Process: ", timesApiModel.Process)
-
 	// Handling For Dynamic Link
 	if strings.Contains(buttonURL, "<mobile>") {
 		// Handling For Poonawalla
@@ -32,7 +31,7 @@ func GetTimesUtilityPayload(timesApiModel extapimodels.WhatsappRequestBody) (map
 				"language": map[string]interface{}{
 					"code": "en_us",
 				},
-				"components": []map[string]interface{}{
+				"components": channelHelper.WithWhatsappBody([]map[string]interface{}{
 					{
 						"type":     "button",
 						"index":    "0",
@@ -44,7 +43,7 @@ func GetTimesUtilityPayload(timesApiModel extapimodels.WhatsappRequestBody) (map
 							},
 						},
 					},
-				},
+				}, timesApiModel.BodyParameters),
 			},
 		}, nil
 
@@ -59,7 +58,7 @@ func GetTimesUtilityPayload(timesApiModel extapimodels.WhatsappRequestBody) (map
 				"language": map[string]interface{}{
 					"code": "en_us",
 				},
-				"components": []map[string]interface{}{}, // Empty components
+				"components": channelHelper.WithWhatsappBody([]map[string]interface{}{}, timesApiModel.BodyParameters), // Empty components
 			},
 		}, nil
 	}
@@ -1,72 +1,91 @@
 package timesWhatsapp
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 
 	"github.com/wecredit/communication-sdk/sdk/queue"
 	"github.com/wecredit/communication-sdk/config"
+	"github.com/wecredit/communication-sdk/internal/channels/vendorClient"
 	timespayloads "github.com/wecredit/communication-sdk/internal/channels/whatsapp/times/timesPayloads"
+	"github.com/wecredit/communication-sdk/internal/models/awsModels"
 	extapimodels "github.com/wecredit/communication-sdk/internal/models/extApiModels"
+	"github.com/wecredit/communication-sdk/internal/vendorAccounts"
 	"github.com/wecredit/communication-sdk/sdk/utils"
 	"github.com/wecredit/communication-sdk/sdk/variables"
 )
 
-func HitTimesWhatsappApi(timesApiModel extapimodels.WhatsappRequestBody) extapimodels.WhatsappResponse {
+func HitTimesWhatsappApi(ctx context.Context, timesApiModel extapimodels.WhatsappRequestBody) extapimodels.WhatsappResponse {
 	var responseBody extapimodels.WhatsappResponse
 	responseBody.IsSent = false
 	// Getting the API URL
 	apiUrl := config.Configs.TimesWpApiUrl
 
-	// Getting the WhatsApp Authorization token
-	apiAuthorization := config.Configs.TimesWpAPIToken
+	// Getting the WhatsApp Authorization token of the client's account
+	account, err := vendorAccounts.Resolve(timesApiModel.Client, variables.WhatsApp, variables.TIMES)
+	if err != nil {
+		utils.ErrorCtx(ctx, err)
+		responseBody.ResponseMessage = fmt.Sprintf("error occured while resolving Times Whatsapp account: %v", err)
+		return responseBody
+	}
 
 	// Setting the API header
 	apiHeader := map[string]string{
-		"Authorization": apiAuthorization,
+		"Authorization": account.Token,
 		"Content-Type":  "application/json",
 	}
 
 	// Get api payload
-	apiPayload, err := getPayload(timesApiModel)
+	apiPayload, err := GetPayload(timesApiModel)
 	if err != nil {
-		utils.Error(fmt.Errorf("error occured while getting WP payload: %v", err))
+		utils.ErrorCtx(ctx, fmt.Errorf("error occured while getting WP payload: %v", err))
 		responseBody.ResponseMessage = fmt.Sprintf("error occured while getting Times Whatsapp payload: %v", err)
 		return responseBody
 	}
 
-	fmt.Println("Times Whatsapp payload:", apiPayload)
-
-	apiResponse, err := utils.ApiHit("POST", apiUrl, apiHeader, "", "", apiPayload, variables.ContentTypeJSON)
+	response, err := vendorClient.For(variables.TIMES).Do(ctx, vendorClient.Request{
+		CommId:      timesApiModel.CommId,
+		Method:      variables.PostMethod,
+		URL:         apiUrl,
+		Headers:     apiHeader,
+		Body:        apiPayload,
+		ContentType: variables.ContentTypeJSON,
+	})
 	if err != nil {
-		utils.Error(fmt.Errorf("error occured while hitting into Times Wp API: %v", err))
-		if queueErr := queue.SendMessageWithSubject(queue.SQSClient, timesApiModel, config.Configs.AwsErrorQueueUrl, variables.ApiHitsFails, err.Error()); queueErr != nil {
-			utils.Error(fmt.Errorf("error sending message to error queue: %v", queueErr))
+		utils.ErrorCtx(ctx, fmt.Errorf("error occured while hitting into Times Wp API: %v", err))
+		if queueErr := queue.SendMessageWithSubject(queue.SQSClient, awsModels.ApiHitFailure{CommId: timesApiModel.CommId, Client: timesApiModel.Client, Channel: variables.WhatsApp, Vendor: variables.TIMES}, config.Configs.AwsErrorQueueUrl, variables.ApiHitsFails, err.Error()); queueErr != nil {
+			utils.ErrorCtx(ctx, fmt.Errorf("error sending message to error queue: %v", queueErr))
 		}
 		responseBody.ResponseMessage = fmt.Sprintf("error occured while hitting into Times Wp API: %v", err)
 		return responseBody
 	}
 
-	fmt.Println("ApiResponse Times:", apiResponse)
-	status := apiResponse["status"].(bool)
-	if status {
+	var apiResponse extapimodels.TimesWhatsappApiResponse
+	if err := response.Decode(&apiResponse); err != nil {
+		utils.ErrorCtx(ctx, fmt.Errorf("error occured while reading Times Wp API response: %v", err))
+		responseBody.ResponseMessage = fmt.Sprintf("unexpected Times Whatsapp response: %v", err)
+		return responseBody
+	}
+
+	messageID := apiResponse.MessageId.String()
+	if messageID == "" {
+		messageID = "null"
+	}
+
+	if apiResponse.Status {
 		responseBody.IsSent = true
-		// Extract `message_id`
-		var messageID string
-		if apiResponse["message_id"] != nil {
-			messageID, _ = apiResponse["message_id"].(string)
-		} else {
-			messageID = "null"
-		}
 
 		// Extract `messages[0].id` from `res_json`
+		var resJson struct {
+			Messages []struct {
+				Id string `json:"id"`
+			} `json:"messages"`
+		}
 		var messageWamID string
-		if resJson, ok := apiResponse["res_json"].(map[string]interface{}); ok {
-			if messages, ok := resJson["messages"].([]interface{}); ok && len(messages) > 0 {
-				if firstMessage, ok := messages[0].(map[string]interface{}); ok {
-					messageWamID, _ = firstMessage["id"].(string)
-				}
-			}
+		if json.Unmarshal(apiResponse.ResJson, &resJson) == nil && len(resJson.Messages) > 0 {
+			messageWamID = resJson.Messages[0].Id
 		}
 
 		// Build the final response message
@@ -81,37 +100,30 @@ func HitTimesWhatsappApi(timesApiModel extapimodels.WhatsappRequestBody) extapim
 		responseBody.TransactionId = messageWamID
 		responseBody.ResponseMessage = strings.Join(parts, " | ")
 	} else { // Handle error case
-		// Extract message
-		message, _ := apiResponse["message"].(string)
-
-		// Extract message_id (handle nil case)
-		var messageID string
-		if apiResponse["message_id"] != nil {
-			messageID, _ = apiResponse["message_id"].(string)
-		} else {
-			messageID = "null"
+		if apiResponse.Message == "" && !response.OK() {
+			responseBody.ResponseMessage = response.Error().Error()
+			return responseBody
 		}
 
 		// Extract res_json errors
 		var errorMsgs []string
-		if resJSON, ok := apiResponse["res_json"].([]interface{}); ok {
-			for _, item := range resJSON {
-				if m, ok := item.(map[string]interface{}); ok {
-					for field, msg := range m {
-						errorMsgs = append(errorMsgs, fmt.Sprintf("%s: %s", field, msg))
-					}
+		var resJson []map[string]interface{}
+		if json.Unmarshal(apiResponse.ResJson, &resJson) == nil {
+			for _, m := range resJson {
+				for field, msg := range m {
+					errorMsgs = append(errorMsgs, fmt.Sprintf("%s: %s", field, msg))
 				}
 			}
 		}
 
 		if len(errorMsgs) > 0 {
 			responseBody.ResponseMessage = fmt.Sprintf("Message: %s | MessageID: %s | Errors: %s",
-				message,
+				apiResponse.Message,
 				messageID,
 				strings.Join(errorMsgs, " | "),
 			)
 		} else {
-			responseBody.ResponseMessage = fmt.Sprintf("Message: %s | MessageID: %s", message, messageID)
+			responseBody.ResponseMessage = fmt.Sprintf("Message: %s | MessageID: %s", apiResponse.Message, messageID)
 		}
 
 	}
@@ -119,7 +131,8 @@ func HitTimesWhatsappApi(timesApiModel extapimodels.WhatsappRequestBody) extapim
 	return responseBody
 }
 
-func getPayload(timesApiModel extapimodels.WhatsappRequestBody) (map[string]interface{}, error) {
+// GetPayload builds the Times WhatsApp message payload for the template.
+func GetPayload(timesApiModel extapimodels.WhatsappRequestBody) (map[string]interface{}, error) {
 	if strings.Contains(timesApiModel.TemplateName, "utility") {
 		// For Utility Payload
 		return timespayloads.GetTimesUtilityPayload(timesApiModel)
@@ -29,55 +29,51 @@ var paymentLinkStages = map[int]bool{
 	// 8: true,
 }
 
-func SendWpByProcess(msg sdkModels.CommApiRequestBody) (bool, map[string]interface{}, error) {
+func SendWpByProcess(ctx context.Context, msg sdkModels.CommApiRequestBody) (bool, map[string]interface{}, error) {
 	requestBody := extapimodels.WhatsappRequestBody{
-		Mobile:            msg.Mobile,
-		Process:           msg.ProcessName,
-		Client:            msg.Client,
-		EmiAmount:         msg.EmiAmount,
-		CustomerName:      msg.CustomerName,
-		LoanId:            msg.LoanId,
-		ApplicationNumber: msg.ApplicationNumber,
-		DueDate:           msg.DueDate,
+		CommId:    msg.CommId,
+		Mobile:    msg.Mobile,
+		Process:   msg.ProcessName,
+		Client:    msg.Client,
+		Variables: msg.TemplateVariables(),
 	}
 
-	utils.Debug("Fetching WHATSAPP process data from cache")
-	templateDetails, found := cache.GetCache().GetMappedData(cache.TemplateDetailsData)
-	if !found {
-		utils.Error(fmt.Errorf("template data not found in cache"))
+	utils.DebugCtx(ctx, "Fetching WHATSAPP process data from cache")
+	snapshot := cache.Current()
+	if !snapshot.Loaded(cache.TemplateDetailsData) {
+		utils.ErrorCtx(ctx, fmt.Errorf("template data not found in cache"))
 		return false, nil, errors.New("template data not found in cache")
 	}
 
-	data, matchedVendor, err := channelHelper.FetchTemplateData(msg, templateDetails)
+	data, matchedVendor, resolution, err := channelHelper.ResolveTemplate(ctx, msg, snapshot)
 	if err != nil {
-		return channelHelper.HandleTemplateNotFoundError(msg, err)
+		return channelHelper.HandleTemplateNotFoundError(msg, resolution, err)
 	}
 
 	msg.Vendor = matchedVendor
+	ctx = utils.WithVendor(ctx, msg.Vendor)
 
 	channelHelper.PopulateWhatsappFields(&requestBody, data)
+	if err := channelHelper.RenderWhatsappRequest(&requestBody); err != nil {
+		return channelHelper.HandleTemplateRenderError(msg, resolution, err)
+	}
 
 	// Handling For Payment Link
-	// Check if current stage should use payment link instead of button url
-	stageInt := int(msg.Stage)
-	if paymentLinkStages[stageInt] && msg.PaymentLink != "" {
-		requestBody.ButtonLink = msg.PaymentLink
-		utils.Debug(fmt.Sprintf("Updated button link with payment link for stage %d and mobile: %s: %s", stageInt, msg.Mobile, msg.PaymentLink))
-	}
+	paymentLink := ApplyPaymentLink(&requestBody, msg.Stage)
 
 	var response extapimodels.WhatsappResponse
 
 	// Check if the vendor should be hit
 	shouldHitVendor := channelHelper.ShouldHitVendor(msg.Client, msg.Channel)
-	utils.Debug(fmt.Sprintf("Channel: %s Mobile: %s, Should hit vendor: %v\n", msg.Channel, msg.Mobile, shouldHitVendor))
+	utils.DebugCtx(ctx, fmt.Sprintf("Channel: %s Mobile: %s, Should hit vendor: %v\n", msg.Channel, msg.Mobile, shouldHitVendor))
 
 	if shouldHitVendor {
 		// Hit Into WP
 		switch msg.Vendor {
 		case variables.TIMES:
-			response = timesWhatsapp.HitTimesWhatsappApi(requestBody)
+			response = timesWhatsapp.HitTimesWhatsappApi(ctx, requestBody)
 		case variables.SINCH:
-			response = sinchWhatsapp.HitSinchWhatsappApi(requestBody)
+			response = sinchWhatsapp.HitSinchWhatsappApi(ctx, requestBody)
 		}
 	}
 
@@ -89,42 +85,58 @@ func SendWpByProcess(msg sdkModels.CommApiRequestBody) (bool, map[string]interfa
 
 	// Step 2: Once you have responseId, update the value of transactionId in redis
 	if err := channelHelper.UpdateRedisTransactionId(msg.Mobile, msg.Channel, msg.Stage, response.TransactionId); err != nil {
-		utils.Error(fmt.Errorf("failed to update Redis transactionId: %v", err))
+		utils.ErrorCtx(ctx, fmt.Errorf("failed to update Redis transactionId: %v", err))
 	}
 
 	response.CommId = msg.CommId
+	response.TemplateResolution = resolution.String()
 	response.TemplateName = requestBody.TemplateName
 	response.Vendor = msg.Vendor
 	response.MobileNumber = msg.Mobile
-	response.PaymentLink = msg.PaymentLink
+	response.PaymentLink = paymentLink
 
 	dbMappedData, err := services.MapIntoDbModel(response)
 	if err != nil {
-		utils.Error(fmt.Errorf("error in mapping data into dbModel: %v", err))
+		utils.ErrorCtx(ctx, fmt.Errorf("error in mapping data into dbModel: %v", err))
 	}
-	
+
 	jsonBytes, _ := json.Marshal(response)
-	utils.Debug(fmt.Sprintf("Whatsapp Response: %s", string(jsonBytes)))
+	utils.DebugCtx(ctx, fmt.Sprintf("Whatsapp Response: %s", string(jsonBytes)))
 	if shouldHitVendor && response.IsSent {
-		utils.Info(fmt.Sprintf("WhatsApp sent successfully for Process: %s on %s through %s", msg.ProcessName, msg.Mobile, msg.Vendor))
-		if msg.Client == variables.CreditSea {
-			redis.IncrementCreditSeaCounter(context.Background(), redis.RDB, redis.CreditSeaWhatsappCount)
+		utils.InfoCtx(ctx, fmt.Sprintf("WhatsApp sent successfully for Process: %s on %s through %s", msg.ProcessName, msg.Mobile, msg.Vendor))
+		if channelHelper.DailyLimit(msg.Client, msg.Channel) > 0 {
+			if err := redis.IncrementDailyCount(ctx, redis.RDB, msg.Client, msg.Channel); err != nil {
+				utils.ErrorCtx(ctx, fmt.Errorf("failed to count the whatsapp of client %s: %v", msg.Client, err))
+			}
 		}
 		return true, dbMappedData, nil
 	}
-	
+
 	if !shouldHitVendor {
 		// Step 2: Once you have error message, update the error message in redis
 		dbMappedData["ResponseMessage"] = "shouldHitVendor is off for mobile " + msg.Mobile
 		if err := channelHelper.HandleShouldHitVendorOffError(msg.Mobile, msg.Channel, msg.Stage); err != nil {
-			utils.Error(fmt.Errorf("failed to handle shouldHitVendor off error: %v", err))
+			utils.ErrorCtx(ctx, fmt.Errorf("failed to handle shouldHitVendor off error: %v", err))
 		}
 	}
 
-	utils.Info(fmt.Sprintf("WhatsApp not sent for Process: %s on %s through %s as shouldHitVendor is false or response.IsSent is false", msg.ProcessName, msg.Mobile, msg.Vendor))
+	utils.InfoCtx(ctx, fmt.Sprintf("WhatsApp not sent for Process: %s on %s through %s as shouldHitVendor is false or response.IsSent is false", msg.ProcessName, msg.Mobile, msg.Vendor))
 	return true, dbMappedData, nil // message processed but not sent as shouldHitVendor is false or response.IsSent is false
 
 	// if err := database.InsertData(config.Configs.WhatsappOutputTable, database.DBtech, dbMappedData); err != nil {
 	// 	utils.Error(fmt.Errorf("error inserting data into table: %v", err))
 	// }
 }
+
+// ApplyPaymentLink swaps the template button link for the request's payment link on stages that use one.
+// It returns the payment link supplied with the request, if any.
+func ApplyPaymentLink(requestBody *extapimodels.WhatsappRequestBody, stage float64) string {
+	// Check if current stage should use payment link instead of button url
+	stageInt := int(stage)
+	paymentLink, _ := channelHelper.LookupVariable("PaymentLink", requestBody.Variables)
+	if paymentLinkStages[stageInt] && paymentLink != "" {
+		requestBody.ButtonLink = paymentLink
+		utils.Debug(fmt.Sprintf("Updated button link with payment link for stage %d and mobile: %s: %s", stageInt, requestBody.Mobile, paymentLink))
+	}
+	return paymentLink
+}
@@ -0,0 +1,170 @@
+package credentials
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wecredit/communication-sdk/config"
+	"github.com/wecredit/communication-sdk/internal/models/apiModels"
+	"github.com/wecredit/communication-sdk/internal/redis"
+	"github.com/wecredit/communication-sdk/pkg/cache"
+	"github.com/wecredit/communication-sdk/sdk/utils"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// verified remembers secrets bcrypt has already accepted, so the hot send path pays for bcrypt once per secret
+// rather than once per request. Entries are keyed by the stored hash, so a rotated or revoked secret is never consulted.
+var verified sync.Map
+
+// hashing bounds the bcrypt comparisons running at once, so a burst of wrong passwords cannot take every CPU
+// from the send path.
+var hashing = make(chan struct{}, runtime.NumCPU())
+
+// NewSecret returns a random 256 bit secret, hex encoded.
+func NewSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate secret: %w", err)
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+// Hash returns the bcrypt hash a secret is stored as.
+func Hash(secret string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(secret), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// Active reports whether the secret is neither revoked nor past its rotation grace period.
+func Active(row apiModels.Userbasicauth, now time.Time) bool {
+	return row.RevokedOn == nil && (row.ExpiresOn == nil || row.ExpiresOn.After(now))
+}
+
+// Verify reports whether password matches an active secret of username in the cached auth table.
+func Verify(username, password string) bool {
+	return verify(cache.Current().Auth, username, password)
+}
+
+func verify(rows []apiModels.Userbasicauth, username, password string) bool {
+	now := utils.IstNow()
+	for _, row := range rows {
+		if row.Username == username && Active(row, now) && matches(row, password) {
+			return true
+		}
+	}
+	return false
+}
+
+func matches(row apiModels.Userbasicauth, password string) bool {
+	// Rows that have not been migrated yet still hold the plaintext
+	if row.PasswordHash == "" {
+		return row.Password != "" && subtle.ConstantTimeCompare([]byte(row.Password), []byte(password)) == 1
+	}
+
+	sum := sha256.Sum256([]byte(row.PasswordHash + "\x00" + password))
+	memo := hex.EncodeToString(sum[:])
+	if _, ok := verified.Load(memo); ok {
+		return true
+	}
+	hashing <- struct{}{}
+	err := bcrypt.CompareHashAndPassword([]byte(row.PasswordHash), []byte(password))
+	<-hashing
+	if err != nil {
+		return false
+	}
+	verified.Store(memo, struct{}{})
+	return true
+}
+
+// ErrLocked is returned while a username is locked out from a source after repeated failed credential checks.
+var ErrLocked = errors.New("too many failed attempts, try again later")
+
+// ErrInvalid is returned when the password matches no active secret of the username.
+var ErrInvalid = errors.New("invalid username or password")
+
+// Authenticate verifies the credentials a request from source, its client IP, presented. Failures are counted per
+// username and source, so a source guessing passwords is locked out, and stops costing bcrypt work, without locking
+// the client out of its other sources. Usernames are stored in lowercase, so any casing of a username matches.
+func Authenticate(ctx context.Context, username, password, source string) error {
+	return authenticate(ctx, cache.Current().Auth, username, password, source)
+}
+
+func authenticate(ctx context.Context, rows []apiModels.Userbasicauth, username, password, source string) error {
+	username = strings.ToLower(username)
+	failures := failureCount(ctx, username, source)
+	if failures >= atoiOrDefault(config.Configs.AuthMaxFailures, 5) {
+		return ErrLocked
+	}
+	if !verify(rows, username, password) {
+		recordFailure(ctx, username, source)
+		return ErrInvalid
+	}
+	if failures > 0 {
+		resetFailures(ctx, username, source)
+	}
+	return nil
+}
+
+func failureCount(ctx context.Context, username, source string) int {
+	if redis.RDB == nil {
+		return 0
+	}
+	failures, err := redis.RDB.Get(ctx, redis.AuthFailuresKey(username, source)).Int()
+	if err != nil {
+		return 0 // no failures recorded
+	}
+	return failures
+}
+
+// recordFailure counts a failed credential check; the count expires one lockout window after the first failure.
+func recordFailure(ctx context.Context, username, source string) {
+	if redis.RDB == nil {
+		return
+	}
+	key := redis.AuthFailuresKey(username, source)
+	failures, err := redis.RDB.Incr(ctx, key).Result()
+	if err != nil {
+		utils.Error(fmt.Errorf("failed to record credential failure for %s from %s: %v", username, source, err))
+		return
+	}
+	if failures == 1 {
+		lockout := time.Duration(atoiOrDefault(config.Configs.AuthLockoutSeconds, 900)) * time.Second
+		if err := redis.RDB.Expire(ctx, key, lockout).Err(); err != nil {
+			utils.Error(fmt.Errorf("failed to set credential failure window for %s from %s: %v", username, source, err))
+		}
+	}
+	if failures == int64(atoiOrDefault(config.Configs.AuthMaxFailures, 5)) {
+		utils.Warn(fmt.Sprintf("credentials of %s locked for %s after %d failed attempts", username, source, failures))
+	}
+}
+
+// resetFailures clears the failure count of username from source after a successful check.
+func resetFailures(ctx context.Context, username, source string) {
+	if redis.RDB == nil {
+		return
+	}
+	if err := redis.RDB.Del(ctx, redis.AuthFailuresKey(username, source)).Err(); err != nil {
+		utils.Error(fmt.Errorf("failed to reset credential failures for %s from %s: %v", username, source, err))
+	}
+}
+
+func atoiOrDefault(value string, defaultValue int) int {
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed <= 0 {
+		return defaultValue
+	}
+	return parsed
+}
@@ -0,0 +1,153 @@
+package credentials
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/wecredit/communication-sdk/config"
+	"github.com/wecredit/communication-sdk/internal/models/apiModels"
+	"github.com/wecredit/communication-sdk/internal/redis"
+	"github.com/wecredit/communication-sdk/sdk/utils"
+)
+
+func hashed(t *testing.T, secret string) string {
+	t.Helper()
+	hash, err := Hash(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hash
+}
+
+func TestVerify(t *testing.T) {
+	past := utils.IstNow().Add(-time.Hour)
+	future := utils.IstNow().Add(time.Hour)
+	newHash := hashed(t, "new-secret")
+
+	tests := []struct {
+		name     string
+		row      apiModels.Userbasicauth
+		password string
+		want     bool
+	}{
+		{
+			name:     "plaintext row not migrated yet",
+			row:      apiModels.Userbasicauth{Username: "acme", Password: "old-secret"},
+			password: "old-secret",
+			want:     true,
+		},
+		{
+			name:     "plaintext row with a wrong password",
+			row:      apiModels.Userbasicauth{Username: "acme", Password: "old-secret"},
+			password: "wrong",
+		},
+		{
+			name:     "empty plaintext never matches",
+			row:      apiModels.Userbasicauth{Username: "acme"},
+			password: "",
+		},
+		{
+			name:     "migrated row keeping its plaintext",
+			row:      apiModels.Userbasicauth{Username: "acme", Password: "new-secret", PasswordHash: newHash},
+			password: "new-secret",
+			want:     true,
+		},
+		{
+			name:     "hashed row ignores its plaintext",
+			row:      apiModels.Userbasicauth{Username: "acme", Password: "old-secret", PasswordHash: newHash},
+			password: "old-secret",
+		},
+		{
+			name:     "hashed row with a wrong password",
+			row:      apiModels.Userbasicauth{Username: "acme", PasswordHash: newHash},
+			password: "wrong",
+		},
+		{
+			name:     "secret in its rotation grace period",
+			row:      apiModels.Userbasicauth{Username: "acme", PasswordHash: newHash, ExpiresOn: &future},
+			password: "new-secret",
+			want:     true,
+		},
+		{
+			name:     "secret past its rotation grace period",
+			row:      apiModels.Userbasicauth{Username: "acme", PasswordHash: newHash, ExpiresOn: &past},
+			password: "new-secret",
+		},
+		{
+			name:     "revoked secret",
+			row:      apiModels.Userbasicauth{Username: "acme", PasswordHash: newHash, RevokedOn: &past},
+			password: "new-secret",
+		},
+		{
+			name:     "another username",
+			row:      apiModels.Userbasicauth{Username: "other", PasswordHash: newHash},
+			password: "new-secret",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Twice, so the remembered bcrypt result answers the same as bcrypt
+			for i := 0; i < 2; i++ {
+				if got := verify([]apiModels.Userbasicauth{tt.row}, "acme", tt.password); got != tt.want {
+					t.Fatalf("verify() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestAuthenticateLockout(t *testing.T) {
+	server := miniredis.RunT(t)
+	previous := redis.RDB
+	redis.RDB = goredis.NewClient(&goredis.Options{Addr: server.Addr()})
+	t.Cleanup(func() {
+		redis.RDB.Close()
+		redis.RDB = previous
+	})
+	config.Configs.AuthMaxFailures = "3"
+	config.Configs.AuthLockoutSeconds = "60"
+
+	ctx := context.Background()
+	rows := []apiModels.Userbasicauth{{Username: "acme", PasswordHash: hashed(t, "secret")}}
+
+	for i := 0; i < 3; i++ {
+		if err := authenticate(ctx, rows, "acme", "wrong", "10.0.0.1"); !errors.Is(err, ErrInvalid) {
+			t.Fatalf("attempt %d: expected ErrInvalid, got %v", i+1, err)
+		}
+	}
+	if err := authenticate(ctx, rows, "acme", "secret", "10.0.0.1"); !errors.Is(err, ErrLocked) {
+		t.Fatalf("expected the source to be locked out, got %v", err)
+	}
+	if err := authenticate(ctx, rows, "acme", "secret", "10.0.0.2"); err != nil {
+		t.Fatalf("expected another source of the client to authenticate, got %v", err)
+	}
+	if err := authenticate(ctx, rows, "ACME", "secret", "10.0.0.1"); !errors.Is(err, ErrLocked) {
+		t.Fatalf("expected the lockout to hold for any casing of the username, got %v", err)
+	}
+
+	server.FastForward(61 * time.Second)
+	if err := authenticate(ctx, rows, "acme", "secret", "10.0.0.1"); err != nil {
+		t.Fatalf("expected the lockout to expire, got %v", err)
+	}
+
+	// A success clears the failures counted so far
+	for i := 0; i < 2; i++ {
+		authenticate(ctx, rows, "acme", "wrong", "10.0.0.1")
+	}
+	if err := authenticate(ctx, rows, "acme", "secret", "10.0.0.1"); err != nil {
+		t.Fatal(err)
+	}
+	if server.Exists(redis.AuthFailuresKey("acme", "10.0.0.1")) {
+		t.Fatal("expected the failure count to be reset after a success")
+	}
+
+	// The SDK sends the client name as it was configured, e.g. "Acme"
+	if err := authenticate(ctx, rows, "Acme", "secret", "10.0.0.3"); err != nil {
+		t.Fatalf("expected the username to match in any casing, got %v", err)
+	}
+}
@@ -1,11 +1,15 @@
 package database
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
 
+	"github.com/wecredit/communication-sdk/internal/tracing"
 	"github.com/wecredit/communication-sdk/sdk/utils"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"gorm.io/gorm"
 )
 
@@ -189,6 +193,43 @@ func InsertData(tableName string, db *gorm.DB, data map[string]interface{}) erro
 		}
 	}()
 
+	if err := InsertDataTx(tableName, tx, data); err != nil {
+		tx.Rollback() // Explicit rollback on error
+		return err
+	}
+
+	// Explicitly commit the transaction
+	if err := tx.Commit().Error; err != nil {
+		tx.Rollback() // Rollback if commit fails
+		return fmt.Errorf("failed to commit transaction into table %s: %w", tableName, err)
+	}
+
+	// Log success
+	utils.Info(fmt.Sprintf("Successfully inserted data into table '%s'", tableName))
+	return nil
+}
+
+// InsertDataWithContext is InsertData in a span that is a child of the span in ctx
+func InsertDataWithContext(ctx context.Context, tableName string, db *gorm.DB, data map[string]interface{}) error {
+	ctx, span := tracing.Start(ctx, "db.insert", trace.SpanKindClient,
+		attribute.String("db.system", "mysql"),
+		attribute.String("db.collection.name", tableName),
+	)
+	err := InsertData(tableName, db.WithContext(ctx), data)
+	tracing.End(span, err)
+	return err
+}
+
+// InsertDataTx inserts data into the given table inside a transaction owned by the caller
+func InsertDataTx(tableName string, tx *gorm.DB, data map[string]interface{}) error {
+	if tableName == "" {
+		return fmt.Errorf("table name cannot be empty")
+	}
+
+	if len(data) == 0 {
+		return fmt.Errorf("data cannot be empty")
+	}
+
 	// Construct the columns and values part of the SQL query
 	var columns []string
 	var placeholders []string
@@ -200,7 +241,6 @@ func InsertData(tableName string, db *gorm.DB, data map[string]interface{}) erro
 		values = append(values, value)
 	}
 
-	// Add ROWLOCK hint to enforce row-level locking
 	query := fmt.Sprintf(
 		"INSERT INTO %s (%s) VALUES (%s)",
 		tableName,
@@ -209,20 +249,9 @@ func InsertData(tableName string, db *gorm.DB, data map[string]interface{}) erro
 	)
 
 	// Execute the query with the values
-	result := tx.Exec(query, values...)
-	if result.Error != nil {
-		tx.Rollback() // Explicit rollback on error
-		return fmt.Errorf("failed to insert data into table %s for query: %s : %w", tableName, query, result.Error)
+	if err := tx.Exec(query, values...).Error; err != nil {
+		return fmt.Errorf("failed to insert data into table %s for query: %s : %w", tableName, query, err)
 	}
-
-	// Explicitly commit the transaction
-	if err := tx.Commit().Error; err != nil {
-		tx.Rollback() // Rollback if commit fails
-		return fmt.Errorf("failed to commit transaction into table %s for query %s: %w", tableName, query, err)
-	}
-
-	// Log success
-	utils.Info(fmt.Sprintf("Successfully inserted data into table '%s'", tableName))
 	return nil
 }
 
@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/wecredit/communication-sdk/internal/metrics"
 	"github.com/wecredit/communication-sdk/sdk/models"
 	"github.com/wecredit/communication-sdk/sdk/utils"
 	"gorm.io/driver/mysql"
@@ -113,6 +114,9 @@ func connectTechDB(connectionType string, config models.Config) error {
 	if err := applyPoolConfig(db, config, dbName); err != nil {
 		return err
 	}
+	if err := db.Use(metrics.GormPlugin{DB: dbName}); err != nil {
+		return fmt.Errorf("failed to instrument %s: %w", dbName, err)
+	}
 
 	*varDB = db
 	utils.Info(fmt.Sprintf("Database connection established for %s.", dbName))
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wecredit/communication-sdk/internal/models/apiModels"
+	services "github.com/wecredit/communication-sdk/internal/services/apiServices"
+
+	"gorm.io/gorm"
+)
+
+type AdminKeyHandler struct {
+	Service *services.AdminKeyService
+}
+
+func NewAdminKeyHandler(s *services.AdminKeyService) *AdminKeyHandler {
+	return &AdminKeyHandler{Service: s}
+}
+
+func (h *AdminKeyHandler) GetKeys(c *gin.Context) {
+	keys, err := h.Service.GetKeys()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if len(keys) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"message": "No API keys found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, keys)
+}
+
+func (h *AdminKeyHandler) CreateKey(c *gin.Context) {
+	var key apiModels.AdminApiKey
+	if err := c.ShouldBindJSON(&key); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input: " + err.Error()})
+		return
+	}
+
+	apiKey, err := h.Service.CreateKey(&key)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// The key is only ever returned here
+	c.JSON(http.StatusCreated, gin.H{"apiKey": apiKey, "key": key})
+}
+
+func (h *AdminKeyHandler) RevokeKey(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	err = h.Service.RevokeKey(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("API key not found with id: %d", id)})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "API key revoked successfully"})
+}
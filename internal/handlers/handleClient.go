@@ -114,6 +114,24 @@ func (h *ClientHandler) UpdateClientByNameAndChannel(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("Client %s updated successfully for channel %s", name, channel)})
 }
 
+func (h *ClientHandler) UpdateClientWebhook(c *gin.Context) {
+	name := c.Param("name")
+	channel := c.Param("channel")
+
+	var webhook apiModels.ClientWebhook
+	if err := c.ShouldBindJSON(&webhook); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid JSON body: %v", err)})
+		return
+	}
+
+	if err := h.Service.UpdateClientWebhook(name, channel, webhook); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("Webhook for client %s updated successfully for channel %s", name, channel)})
+}
+
 func (h *ClientHandler) DeleteClient(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := strconv.Atoi(idParam)
@@ -148,8 +166,11 @@ func (h *ClientHandler) ValidateClient(c *gin.Context) {
 		return
 	}
 
-	user, channel, topicArn, redisAddress, err := h.Service.ValidateCredentials(userInput.Username, userInput.Password, channel)
-	if err != nil {
+	user, channel, topicArn, redisAddress, err := h.Service.ValidateCredentials(userInput.Username, userInput.Password, channel, c.ClientIP())
+	if errors.Is(err, services.ErrCredentialsLocked) {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid username or password"})
 		return
 	}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	services "github.com/wecredit/communication-sdk/internal/services/apiServices"
+
+	"gorm.io/gorm"
+)
+
+type CredentialHandler struct {
+	Service *services.CredentialService
+}
+
+func NewCredentialHandler(s *services.CredentialService) *CredentialHandler {
+	return &CredentialHandler{Service: s}
+}
+
+type createCredentialRequest struct {
+	Username string `json:"username" binding:"required"`
+}
+
+type rotateCredentialRequest struct {
+	GraceSeconds *int `json:"graceSeconds,omitempty"` // CREDENTIAL_ROTATION_GRACE_SECONDS when empty
+}
+
+func (h *CredentialHandler) GetCredentials(c *gin.Context) {
+	rows, err := h.Service.GetCredentials(c.Query("username"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if len(rows) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"message": "No credentials found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, rows)
+}
+
+func (h *CredentialHandler) CreateCredential(c *gin.Context) {
+	var req createCredentialRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input: " + err.Error()})
+		return
+	}
+
+	secret, row, err := h.Service.CreateCredential(req.Username)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// The secret is only ever returned here
+	c.JSON(http.StatusCreated, gin.H{"password": secret, "credential": row})
+}
+
+func (h *CredentialHandler) RotateCredential(c *gin.Context) {
+	username := c.Param("username")
+
+	// The body is optional; without graceSeconds the configured grace period applies
+	var req rotateCredentialRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input: " + err.Error()})
+			return
+		}
+	}
+	grace := h.Service.RotationGrace()
+	if req.GraceSeconds != nil {
+		grace = time.Duration(*req.GraceSeconds) * time.Second
+	}
+
+	secret, row, err := h.Service.RotateCredential(username, grace)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("No active credential found for %s", username)})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"password": secret, "credential": row, "previousExpireInSeconds": int(grace.Seconds())})
+}
+
+func (h *CredentialHandler) RevokeCredential(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	err = h.Service.RevokeCredential(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Active credential not found with id: %d", id)})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Credential revoked successfully"})
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wecredit/communication-sdk/internal/logLevel"
+	"github.com/wecredit/communication-sdk/internal/middleware"
+	"github.com/wecredit/communication-sdk/sdk/utils"
+)
+
+type logLevelRequest struct {
+	Level string `json:"level" binding:"required"` // DEBUG, INFO, WARN, ERROR or NOLOGS
+}
+
+// GetLogLevel returns the level this pod logs at.
+func GetLogLevel(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"level": utils.LogLevel()})
+}
+
+// SetLogLevel changes the level every pod logs at until it restarts; LOG_LEVEL applies again afterwards, and to
+// pods started later.
+func SetLogLevel(c *gin.Context) {
+	var req logLevelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input: " + err.Error()})
+		return
+	}
+
+	previous := utils.LogLevel()
+	if err := utils.SetLogLevel(req.Level); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	adminKey, _ := middleware.AdminKeyFromContext(c)
+	utils.Warn(fmt.Sprintf("log level changed from %s to %s by API key %s", previous, utils.LogLevel(), adminKey.Name))
+
+	pods, err := logLevel.Publish(c.Request.Context(), utils.LogLevel(), adminKey.Name)
+	if err != nil {
+		utils.Error(err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error(), "level": utils.LogLevel(), "previous": previous})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"level": utils.LogLevel(), "previous": previous, "pods": pods})
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wecredit/communication-sdk/internal/middleware"
+	services "github.com/wecredit/communication-sdk/internal/services/apiServices"
+	"github.com/wecredit/communication-sdk/sdk/utils"
+)
+
+type PiiHandler struct {
+	Service *services.PiiService
+}
+
+func NewPiiHandler(s *services.PiiService) *PiiHandler {
+	return &PiiHandler{Service: s}
+}
+
+// RevealPii returns the decrypted personal data of a message. Every access is logged with the API key that made it.
+func (h *PiiHandler) RevealPii(c *gin.Context) {
+	commId := c.Query("commId")
+	if commId == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "commId is required"})
+		return
+	}
+
+	adminKey, _ := middleware.AdminKeyFromContext(c)
+	utils.Warn(fmt.Sprintf("personal data of CommId %s requested by API key %s", commId, adminKey.Name))
+
+	revealed, err := h.Service.Reveal(commId, c.Query("channel"))
+	if err != nil {
+		if errors.Is(err, services.ErrCommNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, revealed)
+}
+
+type PiiRewrapHandler struct {
+	Service *services.PiiRewrapService
+}
+
+func NewPiiRewrapHandler(s *services.PiiRewrapService) *PiiRewrapHandler {
+	return &PiiRewrapHandler{Service: s}
+}
+
+// StartRewrap re-wraps the stored personal data envelopes with the current master key in the background.
+func (h *PiiRewrapHandler) StartRewrap(c *gin.Context) {
+	if err := h.Service.Start(); err != nil {
+		if errors.Is(err, services.ErrRewrapRunning) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	adminKey, _ := middleware.AdminKeyFromContext(c)
+	utils.Warn(fmt.Sprintf("re-wrap of personal data envelopes started by API key %s", adminKey.Name))
+	c.JSON(http.StatusAccepted, h.Service.Status())
+}
+
+// GetRewrapStatus returns the progress of the running or last re-wrap of this pod.
+func (h *PiiRewrapHandler) GetRewrapStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, h.Service.Status())
+}
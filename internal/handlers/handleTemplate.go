@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/wecredit/communication-sdk/internal/middleware"
 	"github.com/wecredit/communication-sdk/internal/models/apiModels"
 	services "github.com/wecredit/communication-sdk/internal/services/apiServices"
 
@@ -28,6 +30,15 @@ func (h *TemplateHandler) GetTemplates(c *gin.Context) {
 	vendor := c.Query("vendor")
 	client := c.Query("client")
 
+	// A key scoped to a client only ever sees that client's templates
+	if scope := middleware.AdminScope(c); scope != "" {
+		if client != "" && !strings.EqualFold(client, scope) {
+			c.JSON(http.StatusNotFound, gin.H{"message": "No Templates found"})
+			return
+		}
+		client = scope
+	}
+
 	templates, err := h.Service.GetTemplates(process, stage, client, channel, vendor)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -71,6 +82,15 @@ func (h *TemplateHandler) AddTemplate(c *gin.Context) {
 		return
 	}
 
+	if scope := middleware.AdminScope(c); scope != "" {
+		if template.Client == "" {
+			template.Client = scope
+		} else if !strings.EqualFold(template.Client, scope) {
+			c.JSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("API key is limited to templates of client %s", scope)})
+			return
+		}
+	}
+
 	if err := h.Service.AddTemplate(&template); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
@@ -94,11 +114,29 @@ func (h *TemplateHandler) UpdateTemplateById(c *gin.Context) {
 		return
 	}
 
-	if err := h.Service.UpdateTemplateById(id, updates); err != nil {
+	// A scoped key cannot move a template to another client
+	if scope := middleware.AdminScope(c); scope != "" {
+		for field, value := range updates {
+			if client, ok := value.(string); strings.EqualFold(field, "client") && (!ok || !strings.EqualFold(client, scope)) {
+				c.JSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("API key is limited to templates of client %s", scope)})
+				return
+			}
+		}
+	}
+
+	draft, err := h.Service.UpdateTemplateById(id, updates)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	if draft != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"message": fmt.Sprintf("Template %d changes stored as draft version %d; approve and activate it to make them live", id, draft.Version),
+			"draft":   draft,
+		})
+		return
+	}
 	c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("Template %d updated successfully", id)})
 }
 
@@ -121,3 +159,43 @@ func (h *TemplateHandler) DeleteTemplate(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Template deleted successfully"})
 }
+
+func (h *TemplateHandler) PreviewTemplate(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	var req apiModels.TemplatePreviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input: " + err.Error()})
+		return
+	}
+
+	// Render and validation problems are part of the preview; an error means the template could not be read
+	preview, err := h.Service.PreviewTemplate(uint(id), req)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Template not found with id: %d", id)})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+}
+
+// TemplateClient resolves the client of the template addressed by the :id parameter, for middleware.RequireClientScope.
+func (h *TemplateHandler) TemplateClient(c *gin.Context) (string, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return "", false
+	}
+	template, err := h.Service.GetTemplateByID(uint(id))
+	if err != nil {
+		return "", false
+	}
+	return template.Client, true
+}
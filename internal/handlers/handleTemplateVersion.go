@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wecredit/communication-sdk/internal/models/apiModels"
+	services "github.com/wecredit/communication-sdk/internal/services/apiServices"
+
+	"gorm.io/gorm"
+)
+
+type TemplateVersionHandler struct {
+	Service *services.TemplateVersionService
+}
+
+func NewTemplateVersionHandler(s *services.TemplateVersionService) *TemplateVersionHandler {
+	return &TemplateVersionHandler{Service: s}
+}
+
+func (h *TemplateVersionHandler) CreateDraft(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	var draft apiModels.TemplateVersion
+	if err := c.ShouldBindJSON(&draft); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input: " + err.Error()})
+		return
+	}
+
+	err = h.Service.CreateDraft(id, &draft)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Template not found with id: %d", id)})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, draft)
+}
+
+func (h *TemplateVersionHandler) GetVersions(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	versions, err := h.Service.GetVersions(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if len(versions) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"message": "No template versions found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, versions)
+}
+
+func (h *TemplateVersionHandler) ApproveVersion(c *gin.Context) {
+	versionId, err := strconv.Atoi(c.Param("versionId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid version ID"})
+		return
+	}
+
+	version, err := h.Service.ApproveVersion(versionId)
+	if err != nil {
+		respondTemplateVersionError(c, err, versionId)
+		return
+	}
+
+	c.JSON(http.StatusOK, version)
+}
+
+func (h *TemplateVersionHandler) ActivateVersion(c *gin.Context) {
+	versionId, err := strconv.Atoi(c.Param("versionId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid version ID"})
+		return
+	}
+
+	// The body is optional; without activateAt the version goes live immediately
+	var req apiModels.ActivateTemplateVersionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input: " + err.Error()})
+			return
+		}
+	}
+
+	version, err := h.Service.ActivateVersion(versionId, req.ActivateAt)
+	if err != nil {
+		respondTemplateVersionError(c, err, versionId)
+		return
+	}
+
+	c.JSON(http.StatusOK, version)
+}
+
+func (h *TemplateVersionHandler) RollbackTemplate(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	version, err := h.Service.RollbackTemplate(id)
+	if errors.Is(err, services.ErrInvalidVersionState) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, version)
+}
+
+func respondTemplateVersionError(c *gin.Context, err error, versionId int) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Template version not found with id: %d", versionId)})
+	case errors.Is(err, services.ErrInvalidVersionState):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// VersionClient resolves the client of the template version addressed by the :versionId parameter, for middleware.RequireClientScope.
+func (h *TemplateVersionHandler) VersionClient(c *gin.Context) (string, bool) {
+	versionId, err := strconv.Atoi(c.Param("versionId"))
+	if err != nil {
+		return "", false
+	}
+	version, err := h.Service.GetVersion(versionId)
+	if err != nil {
+		return "", false
+	}
+	return version.Client, true
+}
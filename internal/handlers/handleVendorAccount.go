@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wecredit/communication-sdk/internal/models/apiModels"
+	services "github.com/wecredit/communication-sdk/internal/services/apiServices"
+
+	"gorm.io/gorm"
+)
+
+type VendorAccountHandler struct {
+	Service *services.VendorAccountService
+}
+
+func NewVendorAccountHandler(s *services.VendorAccountService) *VendorAccountHandler {
+	return &VendorAccountHandler{Service: s}
+}
+
+func (h *VendorAccountHandler) GetAccounts(c *gin.Context) {
+	accounts, err := h.Service.GetAccounts(c.Query("client"), c.Query("channel"), c.Query("vendor"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if len(accounts) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"message": "No vendor accounts found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, accounts)
+}
+
+func (h *VendorAccountHandler) AddAccount(c *gin.Context) {
+	var account apiModels.VendorAccount
+	if err := c.ShouldBindJSON(&account); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input: " + err.Error()})
+		return
+	}
+
+	if err := h.Service.CreateAccount(&account); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, account)
+}
+
+func (h *VendorAccountHandler) UpdateAccount(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	var input apiModels.VendorAccount
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input: " + err.Error()})
+		return
+	}
+
+	account, err := h.Service.UpdateAccount(id, input)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Vendor account not found with id: %d", id)})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, account)
+}
+
+func (h *VendorAccountHandler) DeactivateAccount(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	err = h.Service.DeactivateAccount(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Vendor account not found with id: %d", id)})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Vendor account deactivated successfully"})
+}
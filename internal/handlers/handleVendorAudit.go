@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	services "github.com/wecredit/communication-sdk/internal/services/apiServices"
+)
+
+type VendorAuditHandler struct {
+	Service *services.VendorAuditService
+}
+
+func NewVendorAuditHandler(s *services.VendorAuditService) *VendorAuditHandler {
+	return &VendorAuditHandler{Service: s}
+}
+
+func (h *VendorAuditHandler) GetCalls(c *gin.Context) {
+	commId := c.Query("commId")
+	if commId == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "commId is required"})
+		return
+	}
+
+	calls, err := h.Service.GetCalls(commId, c.Query("vendor"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if len(calls) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"message": "No vendor calls found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, calls)
+}
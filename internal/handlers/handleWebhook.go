@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wecredit/communication-sdk/internal/services/webhookService"
+
+	"gorm.io/gorm"
+)
+
+type WebhookHandler struct {
+	Service *webhookService.WebhookService
+}
+
+func NewWebhookHandler(s *webhookService.WebhookService) *WebhookHandler {
+	return &WebhookHandler{Service: s}
+}
+
+func (h *WebhookHandler) GetDeliveries(c *gin.Context) {
+	commId := c.Query("commId")
+	client := c.Query("client")
+	status := c.Query("status")
+
+	deliveries, err := h.Service.GetDeliveries(commId, client, status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if len(deliveries) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"message": "No webhook deliveries found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid ID"})
+		return
+	}
+
+	err = h.Service.Redeliver(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("Webhook delivery not found with id: %d", id)})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": fmt.Sprintf("Webhook delivery %d scheduled for redelivery", id)})
+}
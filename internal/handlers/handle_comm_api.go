@@ -1 +1,82 @@
 package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	apiServices "github.com/wecredit/communication-sdk/internal/services/apiServices"
+	"github.com/wecredit/communication-sdk/sdk/models/sdkModels"
+	sdkServices "github.com/wecredit/communication-sdk/sdk/services"
+)
+
+type CommHandler struct {
+	Service *apiServices.CommService
+}
+
+func NewCommHandler(s *apiServices.CommService) *CommHandler {
+	return &CommHandler{Service: s}
+}
+
+// SendCommunication ingests one message for the client authenticated by GinBasicAuth.
+func (h *CommHandler) SendCommunication(c *gin.Context) {
+	var msg sdkModels.CommApiRequestBody
+	if err := c.ShouldBindJSON(&msg); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "invalid input: " + err.Error()})
+		return
+	}
+
+	response, err := h.Service.Ingest(c.Request.Context(), c.GetString("username"), &msg)
+	if err != nil {
+		c.JSON(commStatusCode(err), gin.H{"success": false, "error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// SendCommunicationBatch ingests each message of the batch independently.
+// It answers 200 when every message was accepted and 207 with per message status codes otherwise.
+func (h *CommHandler) SendCommunicationBatch(c *gin.Context) {
+	var batch sdkModels.CommBatchRequestBody
+	if err := c.ShouldBindJSON(&batch); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": "invalid input: " + err.Error()})
+		return
+	}
+	if len(batch.Messages) == 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"success": false, "error": "messages must not be empty"})
+		return
+	}
+
+	results, err := h.Service.IngestBatch(c.Request.Context(), c.GetString("username"), batch.Messages)
+	if err != nil {
+		c.JSON(commStatusCode(err), gin.H{"success": false, "error": err.Error()})
+		return
+	}
+
+	status := http.StatusOK
+	for i := range results {
+		if results[i].Success {
+			results[i].StatusCode = http.StatusOK
+			continue
+		}
+		results[i].StatusCode = commStatusCode(results[i].Err)
+		status = http.StatusMultiStatus
+	}
+
+	c.JSON(status, gin.H{"success": status == http.StatusOK, "results": results})
+}
+
+func commStatusCode(err error) int {
+	switch {
+	case errors.Is(err, sdkServices.ErrInvalidRequest), errors.Is(err, apiServices.ErrBatchTooLarge):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, sdkServices.ErrDuplicateMessage):
+		return http.StatusConflict
+	case errors.Is(err, sdkServices.ErrIdempotencyUnavailable):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, apiServices.ErrClientNotAllowed):
+		return http.StatusForbidden
+	}
+	return http.StatusInternalServerError
+}
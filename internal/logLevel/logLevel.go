@@ -0,0 +1,78 @@
+// Package logLevel fans runtime log level changes out to every pod, so PUT /admin/log-level applies to the whole
+// deployment rather than to the pod the request reached. Pods started after a change log at LOG_LEVEL.
+package logLevel
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/wecredit/communication-sdk/config"
+	"github.com/wecredit/communication-sdk/internal/redis"
+	"github.com/wecredit/communication-sdk/sdk/utils"
+)
+
+// Change is published on the log level channel when an admin changes the level.
+type Change struct {
+	Level     string `json:"level"`
+	ChangedBy string `json:"changedBy"`
+}
+
+// Publish tells every pod to log at level, which the caller has already set in this pod. It returns the number
+// of pods that received the change, this one included.
+func Publish(ctx context.Context, level, changedBy string) (int64, error) {
+	if redis.RDB == nil {
+		return 0, fmt.Errorf("log level changed on this pod only: redis client not initialized")
+	}
+
+	payload, err := json.Marshal(Change{Level: level, ChangedBy: changedBy})
+	if err != nil {
+		return 0, err
+	}
+	received, err := redis.RDB.Publish(ctx, config.Configs.LogLevelChannel, payload).Result()
+	if err != nil {
+		return 0, fmt.Errorf("log level changed on this pod only: %w", err)
+	}
+	return received, nil
+}
+
+// StartSubscriber applies the log level changes published by any pod until ctx is cancelled.
+func StartSubscriber(ctx context.Context) {
+	if redis.RDB == nil {
+		utils.Error(fmt.Errorf("log level subscriber not started: redis client not initialized"))
+		return
+	}
+
+	pubsub := redis.RDB.Subscribe(ctx, config.Configs.LogLevelChannel)
+	defer pubsub.Close()
+	if _, err := pubsub.Receive(ctx); err != nil {
+		utils.Error(fmt.Errorf("failed to subscribe to log level channel: %v", err))
+		return
+	}
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case message, ok := <-messages:
+			if !ok {
+				return
+			}
+			var change Change
+			if err := json.Unmarshal([]byte(message.Payload), &change); err != nil {
+				utils.Error(fmt.Errorf("invalid log level change %q: %v", message.Payload, err))
+				continue
+			}
+			if change.Level == utils.LogLevel() {
+				continue
+			}
+			previous := utils.LogLevel()
+			if err := utils.SetLogLevel(change.Level); err != nil {
+				utils.Error(fmt.Errorf("invalid log level change %q: %v", message.Payload, err))
+				continue
+			}
+			utils.Warn(fmt.Sprintf("log level changed from %s to %s by API key %s", previous, change.Level, change.ChangedBy))
+		}
+	}
+}
@@ -0,0 +1,234 @@
+// Package metrics holds the Prometheus metrics of the consumer. They are only exported once Register is called, so
+// the SDK, which shares some of the instrumented packages, does not add them to the registry of its callers.
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+const namespace = "comm"
+
+// Results of a consumed message
+const (
+	ResultProcessed = "processed"
+	ResultRetry     = "retry"
+)
+
+var (
+	MessagesReceived = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "messages_received_total",
+		Help:      "Messages received from SQS.",
+	}, []string{"client", "channel"})
+
+	MessagesProcessed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "messages_processed_total",
+		Help:      "Messages handled by a worker; result is processed, or retry when the message is left for SQS to redeliver.",
+	}, []string{"client", "channel", "result"})
+
+	MessagesDeleted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "messages_deleted_total",
+		Help:      "Messages deleted from SQS.",
+	}, []string{"client", "channel"})
+
+	MessagesRejected = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "messages_rejected_total",
+		Help:      "Messages moved to the error queue because their SNS or producer signature did not verify.",
+	})
+
+	MessagesSkipped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "messages_skipped_total",
+		Help:      "Redelivered messages not sent again; state is done, or in-progress while another worker holds the CommId.",
+	}, []string{"client", "channel", "state"})
+
+	ReceiveLag = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "sqs_receive_lag_seconds",
+		Help:      "Time from the SNS publish of a message to its receipt by the consumer.",
+		Buckets:   []float64{0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 300, 900, 3600},
+	}, []string{"channel"})
+
+	TemplatesNotFound = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "templates_not_found_total",
+		Help:      "Messages dropped because no template matched them.",
+	}, []string{"client", "channel"})
+
+	WorkersConfigured = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "client_workers",
+		Help:      "Workers started for the client.",
+	}, []string{"client"})
+
+	WorkersBusy = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "client_workers_busy",
+		Help:      "Workers of the client processing a message.",
+	}, []string{"client"})
+
+	WorkerQueueLength = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "client_queue_length",
+		Help:      "Messages waiting for a worker of the client.",
+	}, []string{"client"})
+
+	vendorDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "vendor_request_duration_seconds",
+		Help:      "Latency of the requests to vendor APIs, retries included.",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2, 5, 10, 30},
+	}, []string{"vendor", "code"})
+
+	vendorErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "vendor_request_errors_total",
+		Help:      "Vendor requests that failed; code is the HTTP status, or timeout or transport when no answer was received.",
+	}, []string{"vendor", "code"})
+
+	redisDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "redis_command_duration_seconds",
+		Help:      "Latency of Redis commands and pipelines.",
+		Buckets:   []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.5},
+	}, []string{"command", "result"})
+
+	dbDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Latency of database statements.",
+		Buckets:   []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 5},
+	}, []string{"db", "operation", "result"})
+)
+
+// Register registers every metric of the consumer with reg.
+func Register(reg prometheus.Registerer) error {
+	var errs []error
+	for _, collector := range []prometheus.Collector{
+		MessagesReceived, MessagesProcessed, MessagesDeleted, MessagesRejected, MessagesSkipped, ReceiveLag, TemplatesNotFound,
+		WorkersConfigured, WorkersBusy, WorkerQueueLength,
+		vendorDuration, vendorErrors, redisDuration, dbDuration,
+	} {
+		errs = append(errs, reg.Register(collector))
+	}
+	return errors.Join(errs...)
+}
+
+// ObserveVendorCall records one vendor request; statusCode is 0 when err reports that no answer was received.
+func ObserveVendorCall(vendor string, statusCode int, duration time.Duration, err error) {
+	code := strconv.Itoa(statusCode)
+	if err != nil {
+		code = "transport"
+		var netErr net.Error
+		if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+			code = "timeout"
+		}
+	}
+	vendorDuration.WithLabelValues(vendor, code).Observe(duration.Seconds())
+	if err != nil || statusCode < 200 || statusCode >= 300 {
+		vendorErrors.WithLabelValues(vendor, code).Inc()
+	}
+}
+
+// ObserveReceiveLag records the lag of a message published to SNS at the RFC 3339 timestamp.
+func ObserveReceiveLag(channel, snsTimestamp string) {
+	published, err := time.Parse(time.RFC3339, snsTimestamp)
+	if err != nil {
+		return
+	}
+	ReceiveLag.WithLabelValues(channel).Observe(time.Since(published).Seconds())
+}
+
+func result(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "ok"
+}
+
+// RedisHook times every command of the client it is added to.
+type RedisHook struct{}
+
+func (RedisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (RedisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmd)
+		outcome := result(err)
+		if errors.Is(err, redis.Nil) {
+			outcome = "ok" // a missing key is an answer
+		}
+		redisDuration.WithLabelValues(cmd.Name(), outcome).Observe(time.Since(start).Seconds())
+		return err
+	}
+}
+
+func (RedisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmds)
+		redisDuration.WithLabelValues("pipeline", result(err)).Observe(time.Since(start).Seconds())
+		return err
+	}
+}
+
+const startKey = "metrics:start"
+
+// GormPlugin times every statement of the database it is used on.
+type GormPlugin struct {
+	DB string // label of the database, e.g. Tech Write DB
+}
+
+func (p GormPlugin) Name() string {
+	return "metrics"
+}
+
+func (p GormPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("*").Register("metrics:before_create", markStart),
+		cb.Create().After("*").Register("metrics:after_create", p.observe("create")),
+		cb.Query().Before("*").Register("metrics:before_query", markStart),
+		cb.Query().After("*").Register("metrics:after_query", p.observe("query")),
+		cb.Update().Before("*").Register("metrics:before_update", markStart),
+		cb.Update().After("*").Register("metrics:after_update", p.observe("update")),
+		cb.Delete().Before("*").Register("metrics:before_delete", markStart),
+		cb.Delete().After("*").Register("metrics:after_delete", p.observe("delete")),
+		cb.Row().Before("*").Register("metrics:before_row", markStart),
+		cb.Row().After("*").Register("metrics:after_row", p.observe("row")),
+		cb.Raw().Before("*").Register("metrics:before_raw", markStart),
+		cb.Raw().After("*").Register("metrics:after_raw", p.observe("raw")),
+	)
+}
+
+func markStart(tx *gorm.DB) {
+	tx.InstanceSet(startKey, time.Now())
+}
+
+func (p GormPlugin) observe(operation string) func(tx *gorm.DB) {
+	return func(tx *gorm.DB) {
+		value, ok := tx.InstanceGet(startKey)
+		if !ok {
+			return
+		}
+		outcome := result(tx.Error)
+		if errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+			outcome = "ok"
+		}
+		dbDuration.WithLabelValues(p.DB, operation, outcome).Observe(time.Since(value.(time.Time)).Seconds())
+	}
+}
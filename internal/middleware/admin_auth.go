@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wecredit/communication-sdk/internal/models/apiModels"
+	"github.com/wecredit/communication-sdk/pkg/cache"
+	"github.com/wecredit/communication-sdk/sdk/variables"
+)
+
+// ApiKeyHeader carries an admin API key; "Authorization: Bearer <key>" is accepted as well.
+const ApiKeyHeader = "X-Api-Key"
+
+const adminKeyContextKey = "adminKey"
+
+// Permissions checked by RequirePermission
+const (
+	PermVendorsRead       = "vendors:read"
+	PermVendorsWrite      = "vendors:write"
+	PermClientsRead       = "clients:read"
+	PermClientsWrite      = "clients:write"
+	PermTemplatesRead     = "templates:read"
+	PermTemplatesWrite    = "templates:write"
+	PermWebhooksRead      = "webhooks:read"
+	PermWebhooksWrite     = "webhooks:write"
+	PermApiKeysManage     = "apikeys:manage"
+	PermCredentialsManage = "credentials:manage"
+	PermVendorAuditRead   = "vendor-audit:read"
+	PermLoggingManage     = "logging:manage"
+	PermPiiRead           = "pii:read"
+	PermPiiRewrap         = "pii:rewrap"
+)
+
+// rolePermissions is the permission matrix of the admin roles.
+var rolePermissions = map[string]map[string]bool{
+	variables.RoleViewer: {
+		PermVendorsRead:   true,
+		PermClientsRead:   true,
+		PermTemplatesRead: true,
+		PermWebhooksRead:  true,
+	},
+	variables.RoleTemplateEditor: {
+		PermVendorsRead:    true,
+		PermClientsRead:    true,
+		PermTemplatesRead:  true,
+		PermTemplatesWrite: true,
+		PermWebhooksRead:   true,
+	},
+	variables.RoleOpsAdmin: {
+		PermVendorsRead:       true,
+		PermVendorsWrite:      true,
+		PermClientsRead:       true,
+		PermClientsWrite:      true,
+		PermTemplatesRead:     true,
+		PermTemplatesWrite:    true,
+		PermWebhooksRead:      true,
+		PermWebhooksWrite:     true,
+		PermApiKeysManage:     true,
+		PermCredentialsManage: true,
+		PermVendorAuditRead:   true,
+		PermLoggingManage:     true,
+		PermPiiRead:           true,
+		PermPiiRewrap:         true,
+	},
+}
+
+// scopedPermissions are the only permissions a key scoped to a client keeps; every other route spans clients.
+var scopedPermissions = map[string]bool{
+	PermTemplatesRead:  true,
+	PermTemplatesWrite: true,
+}
+
+// HashApiKey returns the digest admin API keys are stored and looked up by.
+func HashApiKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// AdminAuth authenticates the request with an admin API key and stores the key for RequirePermission.
+func AdminAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(ApiKeyHeader)
+		if key == "" {
+			if bearer := c.GetHeader("Authorization"); strings.HasPrefix(bearer, "Bearer ") {
+				key = strings.TrimPrefix(bearer, "Bearer ")
+			}
+		}
+		if key == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing API key"})
+			return
+		}
+
+		adminKey, ok := cache.Current().AdminKey(HashApiKey(key))
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid API key"})
+			return
+		}
+
+		c.Set(adminKeyContextKey, adminKey)
+		c.Next()
+	}
+}
+
+// RequirePermission rejects the request unless the role of its admin API key grants permission.
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		adminKey, ok := AdminKeyFromContext(c)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "missing API key"})
+			return
+		}
+		if !rolePermissions[adminKey.Role][permission] || (adminKey.Client != "" && !scopedPermissions[permission]) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("API key %s is not allowed to %s", adminKey.Name, permission)})
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireClientScope rejects a client scoped key unless resolve reports that the addressed resource belongs to its client.
+// Resources of other clients answer 404 so their existence is not revealed.
+func RequireClientScope(resolve func(c *gin.Context) (string, bool)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scope := AdminScope(c)
+		if scope == "" {
+			c.Next()
+			return
+		}
+		if client, found := resolve(c); !found || !strings.EqualFold(client, scope) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "not found"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// AdminKeyFromContext returns the admin API key AdminAuth authenticated the request with.
+func AdminKeyFromContext(c *gin.Context) (apiModels.AdminApiKey, bool) {
+	value, ok := c.Get(adminKeyContextKey)
+	if !ok {
+		return apiModels.AdminApiKey{}, false
+	}
+	adminKey, ok := value.(apiModels.AdminApiKey)
+	return adminKey, ok
+}
+
+// AdminScope returns the client the request's admin API key is limited to, or "" when it may reach every client.
+func AdminScope(c *gin.Context) string {
+	adminKey, _ := AdminKeyFromContext(c)
+	return adminKey.Client
+}
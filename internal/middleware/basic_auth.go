@@ -4,17 +4,23 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"net"
 	"net/http"
 	"strings"
 
-	"github.com/wecredit/communication-sdk/pkg/cache"
+	"github.com/gin-gonic/gin"
+	"github.com/wecredit/communication-sdk/internal/credentials"
 	"github.com/wecredit/communication-sdk/sdk/models/sdkModels"
 )
 
 // Define a key type to avoid context key collisions
 type contextKey string
 
-const usernameContextKey contextKey = "username"
+const (
+	usernameContextKey contextKey = "username"
+	sourceContextKey   contextKey = "source"
+)
 
 // BasicAuthMiddleware validates Base64-encoded username and password
 func BasicAuthMiddleware(next http.Handler) http.Handler {
@@ -61,32 +67,18 @@ func BasicAuthMiddleware(next http.Handler) http.Handler {
 		}
 		username, password := parts[0], parts[1]
 
-		// Collecting BasicAuthData
-		authDetails, _ := cache.GetCache().Get(cache.AuthDetails)
-
-		// Validate the credentials
-		isValid := false
-		for _, data := range authDetails {
-			// extract username and password from the map
-			usernameFromData, _ := data["username"].(string)
-			passwordFromData, _ := data["password"].(string)
-
-			// Validate headers username and password
-			if usernameFromData == username && passwordFromData == password {
-				isValid = true
-				break
+		// If username or password doesn't match, return Unauthorized; a source locked out gets Too Many Requests
+		if err := ValidCredentials(r.Context(), username, password, requestSource(r)); err != nil {
+			code, message := http.StatusUnauthorized, "Unauthorized"
+			if errors.Is(err, credentials.ErrLocked) {
+				code, message = http.StatusTooManyRequests, err.Error()
 			}
-
-		}
-
-		// If username or password doesn't match, return Unauthorized
-		if !isValid {
 			response := sdkModels.CommApiErrorResponseBody{
-				StatusCode:    http.StatusUnauthorized,
-				StatusMessage: "Unauthorized",
+				StatusCode:    code,
+				StatusMessage: message,
 			}
 			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusUnauthorized)
+			w.WriteHeader(code)
 			json.NewEncoder(w).Encode(response)
 			return
 		}
@@ -96,3 +88,47 @@ func BasicAuthMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// ValidCredentials checks the password against the active secrets of username in the basic auth table, counting
+// failures per username and source; it returns credentials.ErrLocked while the source is locked out.
+func ValidCredentials(ctx context.Context, username, password, source string) error {
+	return credentials.Authenticate(ctx, username, password, source)
+}
+
+// requestSource returns the client IP of the request: the one Gin resolved through its trusted proxies, else the peer.
+func requestSource(r *http.Request) string {
+	if source, ok := r.Context().Value(sourceContextKey).(string); ok && source != "" {
+		return source
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+// UsernameFromContext returns the username BasicAuthMiddleware authenticated the request with.
+func UsernameFromContext(ctx context.Context) (string, bool) {
+	username, ok := ctx.Value(usernameContextKey).(string)
+	return username, ok
+}
+
+// GinBasicAuth mounts BasicAuthMiddleware on a Gin route; the authenticated username is stored under "username".
+func GinBasicAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authenticated := false
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), sourceContextKey, c.ClientIP()))
+		BasicAuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authenticated = true
+			c.Request = r
+		})).ServeHTTP(c.Writer, c.Request)
+
+		if !authenticated {
+			c.Abort()
+			return
+		}
+
+		username, _ := UsernameFromContext(c.Request.Context())
+		c.Set("username", username)
+		c.Next()
+	}
+}
@@ -22,20 +22,114 @@ type Vendor struct {
 }
 
 type Client struct {
-	Id                 int        `json:"id"`
-	Name               string     `gorm:"column:Name" json:"name"`
-	Channel            string     `gorm:"column:Channel" json:"channel"`
-	Status             int        `gorm:"column:Status" json:"status"` // 1 = active, 0 = inactive
-	RateLimitPerMinute int        `gorm:"column:RateLimitPerMinute" json:"rateLimitPerMinute"`
-	CreatedOn          time.Time  `gorm:"column:CreatedOn" json:"createdOn"`
-	UpdatedOn          *time.Time `gorm:"column:UpdatedOn" json:"updatedOn,omitempty"`
+	Id                      int        `json:"id"`
+	Name                    string     `gorm:"column:Name" json:"name"`
+	Channel                 string     `gorm:"column:Channel" json:"channel"`
+	Status                  int        `gorm:"column:Status" json:"status"` // 1 = active, 0 = inactive
+	RateLimitPerMinute      int        `gorm:"column:RateLimitPerMinute" json:"rateLimitPerMinute"`
+	DailyLimit              int        `gorm:"column:DailyLimit" json:"dailyLimit,omitempty"` // WhatsApp messages sent per IST day; 0 = unlimited
+	WebhookUrl              string     `gorm:"column:WebhookUrl" json:"webhookUrl,omitempty"`
+	WebhookSecret           string     `gorm:"column:WebhookSecret" json:"-"` // Deprecated: plaintext, kept until WEBHOOK_CLEAR_PLAINTEXT_SECRETS is set
+	WebhookSecretEncrypted  string     `gorm:"column:WebhookSecretEncrypted" json:"-"`
+	WebhookSecretWrappedKey string     `gorm:"column:WebhookSecretWrappedKey" json:"-"`
+	WebhookSecretKeyId      string     `gorm:"column:WebhookSecretKeyId" json:"-"`                                    // master key the data key is wrapped with
+	WebhookEvents           string     `gorm:"column:WebhookEvents" json:"webhookEvents,omitempty"`                   // comma separated, e.g. SENT,FAILED
+	TemplateFallbackPolicy  string     `gorm:"column:TemplateFallbackPolicy" json:"templateFallbackPolicy,omitempty"` // ordered, e.g. EXACT,SIBLING_STAGE,OTHER_VENDOR
+	ShouldHitVendor         int        `gorm:"column:ShouldHitVendor;->" json:"shouldHitVendor"`                      // read only; 1 = messages are sent to the vendor
+	CreatedOn               time.Time  `gorm:"column:CreatedOn" json:"createdOn"`
+	UpdatedOn               *time.Time `gorm:"column:UpdatedOn" json:"updatedOn,omitempty"`
 }
 
+type ClientWebhook struct {
+	WebhookUrl    string `json:"webhookUrl"`
+	WebhookSecret string `json:"webhookSecret"`
+	WebhookEvents string `json:"webhookEvents"`
+}
+
+type WebhookDelivery struct {
+	Id             int        `json:"id"`
+	CommId         string     `gorm:"column:CommId" json:"commId"`
+	Client         string     `gorm:"column:Client" json:"client"`
+	Channel        string     `gorm:"column:Channel" json:"channel"`
+	EventType      string     `gorm:"column:EventType" json:"eventType"`
+	Url            string     `gorm:"column:Url" json:"url"`
+	Payload        string     `gorm:"column:Payload" json:"payload"`
+	Status         string     `gorm:"column:Status" json:"status"` // PENDING, IN_FLIGHT, DELIVERED, FAILED
+	Attempts       int        `gorm:"column:Attempts" json:"attempts"`
+	LastStatusCode int        `gorm:"column:LastStatusCode" json:"lastStatusCode,omitempty"`
+	LastError      string     `gorm:"column:LastError" json:"lastError,omitempty"`
+	NextAttemptOn  time.Time  `gorm:"column:NextAttemptOn" json:"nextAttemptOn"`
+	DeliveredOn    *time.Time `gorm:"column:DeliveredOn" json:"deliveredOn,omitempty"`
+	CreatedOn      time.Time  `gorm:"column:CreatedOn" json:"createdOn"`
+	UpdatedOn      *time.Time `gorm:"column:UpdatedOn" json:"updatedOn,omitempty"`
+}
+
+// VendorCallAudit is one request to a vendor API with its answer. Credentials are stripped from the bodies and
+// mobile numbers and email addresses masked.
+type VendorCallAudit struct {
+	Id           int       `json:"id"`
+	CommId       string    `gorm:"column:CommId" json:"commId"` // empty for calls not made for a message, e.g. token refreshes
+	Vendor       string    `gorm:"column:Vendor" json:"vendor"`
+	Method       string    `gorm:"column:Method" json:"method"`
+	Endpoint     string    `gorm:"column:Endpoint" json:"endpoint"`     // without the query string
+	StatusCode   int       `gorm:"column:StatusCode" json:"statusCode"` // 0 when no answer was received
+	LatencyMs    int64     `gorm:"column:LatencyMs" json:"latencyMs"`
+	Error        string    `gorm:"column:Error" json:"error,omitempty"`
+	RequestBody  string    `gorm:"column:RequestBody" json:"requestBody"`
+	ResponseBody string    `gorm:"column:ResponseBody" json:"responseBody"`
+	CreatedOn    time.Time `gorm:"column:CreatedOn" json:"createdOn"`
+}
+
+// Userbasicauth is one secret of a client. A client can have several active secrets while one is being rotated out.
 type Userbasicauth struct {
-	Id        int       `json:"Id"`
-	Username  string    `gorm:"column:username" json:"username" binding:"required"`
-	Password  string    `gorm:"column:password" json:"password" binding:"required"`
-	CreatedOn time.Time `gorm:"column:createdOn" json:"createdOn,omitempty"`
+	Id           int        `json:"Id"`
+	Username     string     `gorm:"column:username" json:"username" binding:"required"`
+	Password     string     `gorm:"column:password" json:"password,omitempty" binding:"required"` // plaintext of rows not migrated yet; request input otherwise
+	PasswordHash string     `gorm:"column:passwordHash" json:"-"`                                 // bcrypt
+	ExpiresOn    *time.Time `gorm:"column:expiresOn" json:"expiresOn,omitempty"`                  // end of the rotation grace period
+	RevokedOn    *time.Time `gorm:"column:revokedOn" json:"revokedOn,omitempty"`
+	CreatedOn    time.Time  `gorm:"column:createdOn" json:"createdOn,omitempty"`
+	UpdatedOn    *time.Time `gorm:"column:updatedOn" json:"updatedOn,omitempty"`
+}
+
+// AdminApiKey authenticates calls to the admin routes. Only the SHA-256 hex digest of the key is stored.
+// A key with a Client can only reach that client's templates.
+type AdminApiKey struct {
+	Id        int        `gorm:"column:Id;primaryKey" json:"id"`
+	Name      string     `gorm:"column:Name" json:"name" binding:"required"`
+	KeyHash   string     `gorm:"column:KeyHash" json:"-"`
+	Role      string     `gorm:"column:Role" json:"role" binding:"required"` // viewer, template-editor or ops-admin
+	Client    string     `gorm:"column:Client" json:"client,omitempty"`
+	Status    int        `gorm:"column:Status" json:"status"` // 1 = active, 0 = revoked
+	CreatedOn time.Time  `gorm:"column:CreatedOn" json:"createdOn"`
+	UpdatedOn *time.Time `gorm:"column:UpdatedOn" json:"updatedOn,omitempty"`
+}
+
+// VendorAccount is the account a client sends through at a vendor; an empty Client is the vendor's shared account.
+// The credentials are stored envelope encrypted (see internal/secrets) and are never serialised.
+type VendorAccount struct {
+	Id                   int                `gorm:"column:Id;primaryKey" json:"id"`
+	Client               string             `gorm:"column:Client" json:"client"`
+	Channel              string             `gorm:"column:Channel" json:"channel" binding:"required"`
+	Vendor               string             `gorm:"column:Vendor" json:"vendor" binding:"required"`
+	AppId                string             `gorm:"column:AppId" json:"appId,omitempty"`
+	Sender               string             `gorm:"column:Sender" json:"sender,omitempty"`         // sender id, or from address of email accounts
+	SenderName           string             `gorm:"column:SenderName" json:"senderName,omitempty"` // display name of the sender of email accounts
+	ReplyTo              string             `gorm:"column:ReplyTo" json:"replyTo,omitempty"`       // reply-to address of email accounts
+	Credentials          *VendorCredentials `gorm:"-" json:"credentials,omitempty"`                // request input only
+	EncryptedCredentials string             `gorm:"column:EncryptedCredentials" json:"-"`
+	WrappedKey           string             `gorm:"column:WrappedKey" json:"-"`
+	KeyId                string             `gorm:"column:KeyId" json:"keyId,omitempty"` // master key the data key is wrapped with
+	Status               int                `gorm:"column:Status" json:"status"`         // 1 = active, 0 = inactive
+	CreatedOn            time.Time          `gorm:"column:CreatedOn" json:"createdOn"`
+	UpdatedOn            *time.Time         `gorm:"column:UpdatedOn" json:"updatedOn,omitempty"`
+}
+
+// VendorCredentials are the secrets of a vendor account; each vendor API uses either Username and Password or Token.
+type VendorCredentials struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Token    string `json:"token,omitempty"`
 }
 
 type Templatedetails struct {
@@ -59,3 +153,53 @@ type Templatedetails struct {
 	Subject           string     `gorm:"column:Subject" json:"subject,omitempty"`
 	FromEmail         string     `gorm:"column:FromEmail" json:"fromEmail,omitempty"`
 }
+
+type TemplateVersion struct {
+	Id                int        `gorm:"column:Id;primaryKey" json:"id"`
+	TemplateId        int        `gorm:"column:TemplateId" json:"templateId"`
+	Version           int        `gorm:"column:Version" json:"version"`
+	State             string     `gorm:"column:State" json:"state"` // DRAFT, APPROVED, ACTIVE, SUPERSEDED
+	Client            string     `gorm:"column:Client" json:"client,omitempty"`
+	Channel           string     `gorm:"column:Channel" json:"channel"`
+	Process           string     `gorm:"column:Process" json:"process"`
+	Stage             float64    `gorm:"column:Stage" json:"stage"`
+	Vendor            string     `gorm:"column:Vendor" json:"vendor"`
+	TemplateName      string     `gorm:"column:TemplateName" json:"templateName"`
+	ImageId           string     `gorm:"column:ImageId" json:"imageId,omitempty"`
+	ImageUrl          string     `gorm:"column:ImageUrl" json:"imageUrl,omitempty"`
+	DltTemplateId     int64      `gorm:"column:DltTemplateId" json:"dltTemplateId,omitempty"`
+	TemplateText      string     `gorm:"column:TemplateText" json:"templateText,omitempty"`
+	Link              string     `gorm:"column:Link" json:"link,omitempty"`
+	TemplateCategory  int64      `gorm:"column:TemplateCategory" json:"templateCategory,omitempty"`
+	TemplateVariables string     `gorm:"column:TemplateVariables" json:"templateVariables,omitempty"`
+	Subject           string     `gorm:"column:Subject" json:"subject,omitempty"`
+	FromEmail         string     `gorm:"column:FromEmail" json:"fromEmail,omitempty"`
+	Notes             string     `gorm:"column:Notes" json:"notes,omitempty"`
+	ActivateOn        *time.Time `gorm:"column:ActivateOn" json:"activateOn,omitempty"` // scheduled activation time for an APPROVED version
+	ApprovedOn        *time.Time `gorm:"column:ApprovedOn" json:"approvedOn,omitempty"`
+	ActivatedOn       *time.Time `gorm:"column:ActivatedOn" json:"activatedOn,omitempty"`
+	CreatedOn         time.Time  `gorm:"column:CreatedOn" json:"createdOn"`
+	UpdatedOn         *time.Time `gorm:"column:UpdatedOn" json:"updatedOn,omitempty"`
+}
+
+type ActivateTemplateVersionRequest struct {
+	ActivateAt *time.Time `json:"activateAt,omitempty"` // activate immediately when empty
+}
+
+type TemplatePreviewRequest struct {
+	Mobile    string            `json:"mobile,omitempty"`
+	Email     string            `json:"email,omitempty"`
+	Variables map[string]string `json:"variables,omitempty"`
+}
+
+type TemplatePreview struct {
+	TemplateId     int                    `json:"templateId"`
+	Channel        string                 `json:"channel"`
+	Vendor         string                 `json:"vendor"`
+	Valid          bool                   `json:"valid"`
+	Errors         []string               `json:"errors,omitempty"`
+	RenderedText   string                 `json:"renderedText,omitempty"`
+	BodyParameters []string               `json:"bodyParameters,omitempty"`
+	Attributes     map[string]string      `json:"attributes,omitempty"`
+	Payload        map[string]interface{} `json:"payload,omitempty"`
+}
@@ -5,9 +5,27 @@ type SnsMessageWrapper struct {
 	MessageId        string `json:"MessageId"`
 	TopicArn         string `json:"TopicArn"`
 	Message          string `json:"Message"` // This is a JSON string
+	Subject          string `json:"Subject,omitempty"`
 	Timestamp        string `json:"Timestamp"`
 	SignatureVersion string `json:"SignatureVersion"`
 	Signature        string `json:"Signature"`
 	SigningCertURL   string `json:"SigningCertURL"`
 	UnsubscribeURL   string `json:"UnsubscribeURL"`
+
+	MessageAttributes map[string]SnsMessageAttribute `json:"MessageAttributes,omitempty"`
+}
+
+// SnsMessageAttribute is a message attribute as SNS delivers it to SQS, e.g. SubjectKey or traceparent.
+type SnsMessageAttribute struct {
+	Type  string `json:"Type"`
+	Value string `json:"Value"`
+}
+
+// ApiHitFailure is what goes to the error queue when a vendor API cannot be reached. It names the message
+// without its recipient or variables, which stay encrypted in the input tables.
+type ApiHitFailure struct {
+	CommId  string `json:"CommId"`
+	Client  string `json:"Client"`
+	Channel string `json:"Channel"`
+	Vendor  string `json:"Vendor"`
 }
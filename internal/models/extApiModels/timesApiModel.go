@@ -14,6 +14,7 @@ type TimesAPIModel struct {
 }
 
 type SmsRequestBody struct {
+	CommId            string
 	Client            string
 	Process           string
 	DltTemplateId     int64
@@ -21,25 +22,23 @@ type SmsRequestBody struct {
 	TemplateCategory  string
 	TemplateVariables string
 	Mobile            string
-	EmiAmount         string
-	CustomerName      string
-	LoanId            string
-	ApplicationNumber string
-	DueDate           string
+	Variables         map[string]string
 	Description       string
 }
 
 type SmsResponse struct {
-	DltTemplateId   int64  `json:"dltTemplateId" gorm:"DltTemplateId"`
-	IsSent          bool   `json:"isSent" gorm:"IsSent"`
-	CommId          string `json:"CommId" gorm:"CommId"`
-	Vendor          string `json:"Vendor" gorm:"Vendor"`
-	TransactionId   string `json:"transactionId" gorm:"TransactionId"`
-	ResponseMessage string `json:"responseMessage" gorm:"ResponseMessage"`
-	MobileNumber    string `json:"mobileNumber" gorm:"MobileNumber"`
+	DltTemplateId      int64  `json:"dltTemplateId" gorm:"DltTemplateId"`
+	IsSent             bool   `json:"isSent" gorm:"IsSent"`
+	CommId             string `json:"CommId" gorm:"CommId"`
+	Vendor             string `json:"Vendor" gorm:"Vendor"`
+	TransactionId      string `json:"transactionId" gorm:"TransactionId"`
+	ResponseMessage    string `json:"responseMessage" gorm:"ResponseMessage"`
+	MobileNumber       string `json:"mobileNumber" gorm:"MobileNumber"`
+	TemplateResolution string `json:"templateResolution" gorm:"TemplateResolution"`
 }
 
 type WhatsappRequestBody struct {
+	CommId            string
 	AppId             string
 	Mobile            string
 	Process           string
@@ -51,26 +50,26 @@ type WhatsappRequestBody struct {
 	TemplateCategory  string
 	AccessToken       string
 	Client            string
-	EmiAmount         string // Variables
-	CustomerName      string // Variables
-	LoanId            string // Variables
-	ApplicationNumber string // Variables
-	DueDate           string // Variables
+	Variables         map[string]string
+	BodyParameters    []string // rendered from Variables by channelHelper.RenderWhatsappRequest
 }
 
 type WhatsappResponse struct {
-	TemplateName    string `json:"templateName" gorm:"TemplateName"`
-	IsSent          bool   `json:"isSent" gorm:"IsSent"`
-	CommId          string `json:"CommId" gorm:"CommId"`
-	Vendor          string `json:"Vendor" gorm:"Vendor"`
-	MobileNumber    string `json:"mobileNumber" gorm:"MobileNumber"`
-	TransactionId   string `json:"transactionId" gorm:"TransactionId"`
-	ResponseMessage string `json:"responseMessage" gorm:"ResponseMessage"`
-	PaymentLink     string `json:"paymentLink" gorm:"PaymentLink"`
+	TemplateName       string `json:"templateName" gorm:"TemplateName"`
+	IsSent             bool   `json:"isSent" gorm:"IsSent"`
+	CommId             string `json:"CommId" gorm:"CommId"`
+	Vendor             string `json:"Vendor" gorm:"Vendor"`
+	MobileNumber       string `json:"mobileNumber" gorm:"MobileNumber"`
+	TransactionId      string `json:"transactionId" gorm:"TransactionId"`
+	ResponseMessage    string `json:"responseMessage" gorm:"ResponseMessage"`
+	PaymentLink        string `json:"paymentLink" gorm:"PaymentLink"`
+	TemplateResolution string `json:"templateResolution" gorm:"TemplateResolution"`
 }
 
 type RcsRequestBody struct {
+	CommId       string
 	Mobile       string
+	Client       string
 	Process      string
 	TemplateName string
 	AppId        string
@@ -80,15 +79,17 @@ type RcsRequestBody struct {
 }
 
 type RcsResponse struct {
-	TemplateName    string `json:"templateName" gorm:"TemplateName"`
-	CommId          string `json:"CommId" gorm:"CommId"`
-	IsSent          bool   `json:"isSent" gorm:"IsSent"`
-	Vendor          string `json:"Vendor" gorm:"Vendor"`
-	TransactionId   string `json:"transactionId" gorm:"TransactionId"`
-	ResponseMessage string `json:"responseMessage" gorm:"ResponseMessage"`
+	TemplateName       string `json:"templateName" gorm:"TemplateName"`
+	CommId             string `json:"CommId" gorm:"CommId"`
+	IsSent             bool   `json:"isSent" gorm:"IsSent"`
+	Vendor             string `json:"Vendor" gorm:"Vendor"`
+	TransactionId      string `json:"transactionId" gorm:"TransactionId"`
+	ResponseMessage    string `json:"responseMessage" gorm:"ResponseMessage"`
+	TemplateResolution string `json:"templateResolution" gorm:"TemplateResolution"`
 }
 
 type EmailRequestBody struct {
+	CommId            string
 	Client            string
 	Process           string
 	TemplateId        string
@@ -96,20 +97,18 @@ type EmailRequestBody struct {
 	TemplateVariables string
 	FromEmail         string
 	ToEmail           string
-	EmiAmount         string
-	CustomerName      string
-	LoanId            string
-	ApplicationNumber string
-	DueDate           string
+	Variables         map[string]string
+	Attributes        map[string]string // rendered from Variables by channelHelper.RenderEmailRequest
 	Description       string
 }
 
 type EmailResponse struct {
-	TemplateName    string `json:"templateName" gorm:"TemplateName"`
-	CommId          string `json:"CommId" gorm:"CommId"`
-	IsSent          bool   `json:"isSent" gorm:"IsSent"`
-	Vendor          string `json:"Vendor" gorm:"Vendor"`
-	TransactionId   string `json:"transactionId" gorm:"TransactionId"`
-	ResponseMessage string `json:"responseMessage" gorm:"ResponseMessage"`
-	Email           string `json:"email" gorm:"email"`
+	TemplateName       string `json:"templateName" gorm:"TemplateName"`
+	CommId             string `json:"CommId" gorm:"CommId"`
+	IsSent             bool   `json:"isSent" gorm:"IsSent"`
+	Vendor             string `json:"Vendor" gorm:"Vendor"`
+	TransactionId      string `json:"transactionId" gorm:"TransactionId"`
+	ResponseMessage    string `json:"responseMessage" gorm:"ResponseMessage"`
+	Email              string `json:"email" gorm:"email"`
+	TemplateResolution string `json:"templateResolution" gorm:"TemplateResolution"`
 }
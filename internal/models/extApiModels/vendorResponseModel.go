@@ -0,0 +1,82 @@
+package extapimodels
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// FlexString decodes a JSON string, number or boolean as its text, as vendors are not consistent about which they send.
+type FlexString string
+
+func (f *FlexString) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*f = ""
+		return nil
+	}
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*f = FlexString(text)
+		return nil
+	}
+	*f = FlexString(strings.Trim(string(data), `"`))
+	return nil
+}
+
+func (f FlexString) String() string {
+	return string(f)
+}
+
+// SinchSmsApiResponse is the answer of the Sinch SMS API.
+type SinchSmsApiResponse struct {
+	Accepted bool       `json:"accepted"`
+	RespId   FlexString `json:"respid"`
+	Error    FlexString `json:"error"` // rejection code, see sinchSms.RejectionCodeMap
+}
+
+// SinchWhatsappApiResponse is the answer of the Sinch WhatsApp message API.
+type SinchWhatsappApiResponse struct {
+	Success     FlexString      `json:"success"`
+	ResponseId  FlexString      `json:"responseId"`
+	Description json.RawMessage `json:"description"` // a list of SinchWhatsappError when the message is rejected
+}
+
+type SinchWhatsappError struct {
+	ErrorCode        FlexString `json:"errorCode"`
+	ErrorDescription string     `json:"errorDescription"`
+}
+
+// SinchRcsApiResponse is the answer of the Sinch conversation API.
+type SinchRcsApiResponse struct {
+	MessageId string         `json:"message_id"`
+	Error     *SinchRcsError `json:"error"`
+}
+
+type SinchRcsError struct {
+	Code    FlexString               `json:"code"`
+	Message string                   `json:"message"`
+	Status  string                   `json:"status"`
+	Details []map[string]interface{} `json:"details"`
+}
+
+// SinchEmailApiResponse is the answer of the Sinch email API.
+type SinchEmailApiResponse struct {
+	RequestId string          `json:"request_id"`
+	Errors    json.RawMessage `json:"errors"`
+}
+
+// TimesSmsApiResponse is the answer of the Times SMS API.
+type TimesSmsApiResponse struct {
+	State         string     `json:"state"`
+	Description   string     `json:"description"`
+	TransactionId FlexString `json:"transactionId"`
+}
+
+// TimesWhatsappApiResponse is the answer of the Times WhatsApp API.
+type TimesWhatsappApiResponse struct {
+	Status    bool            `json:"status"`
+	Message   string          `json:"message"`
+	MessageId FlexString      `json:"message_id"`
+	ResJson   json.RawMessage `json:"res_json"` // the WhatsApp answer on success, a list of field errors otherwise
+}
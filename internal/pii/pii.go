@@ -0,0 +1,292 @@
+// Package pii protects the personal data of a message. With PII_ENCRYPTION_ENABLED, the mobile number, email address,
+// names and loan identifiers are encrypted field by field before the message is published and stored, and the
+// tables keep them masked next to their envelope. Where a value is only compared, as in the Redis idempotency keys,
+// a keyed hash replaces it.
+//
+// An encrypted field is a string of the form pii:v1:keyId:wrappedKey:ciphertext, sealed by internal/secrets and bound
+// to the CommId and the field name, so it cannot be copied into another message. Values without the prefix are
+// plaintext from producers that do not encrypt yet and are passed through unchanged. After the master key is rotated,
+// Rewrap moves stored values to the new key without decrypting them.
+package pii
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/wecredit/communication-sdk/config"
+	"github.com/wecredit/communication-sdk/internal/redact"
+	"github.com/wecredit/communication-sdk/internal/secrets"
+	"github.com/wecredit/communication-sdk/sdk/models/sdkModels"
+)
+
+// Prefix starts every value sealed by Encrypt
+const Prefix = "pii:v1:"
+
+// Suffixes of the columns stored next to a protected column
+const (
+	EncryptedSuffix = "Encrypted"
+	HashSuffix      = "Hash"
+)
+
+// sensitiveVariables are the template variables holding personal data, compared case-insensitively.
+var sensitiveVariables = map[string]bool{
+	"customername":      true,
+	"name":              true,
+	"firstname":         true,
+	"lastname":          true,
+	"loanid":            true,
+	"applicationnumber": true,
+	"mobile":            true,
+	"email":             true,
+}
+
+// protectedColumns are the input and output table columns holding personal data, with the function masking them.
+// Rows are matched case-insensitively, as MySQL columns are: the email output row stores its address as "email".
+var protectedColumns = map[string]func(string) string{
+	"Mobile":       redact.Mobile,
+	"MobileNumber": redact.Mobile,
+	"Email":        redact.Email,
+}
+
+var (
+	hashKeyOnce sync.Once
+	hashKey     []byte
+	hashKeyErr  error
+)
+
+// Enabled reports whether messages are encrypted before they are published and stored.
+func Enabled() bool {
+	on, _ := strconv.ParseBool(strings.TrimSpace(config.Configs.PiiEncryptionEnabled))
+	return on
+}
+
+// Check validates the keys, so a misconfiguration stops the process at start rather than failing every message.
+func Check() error {
+	if _, err := loadHashKey(); err != nil {
+		return err
+	}
+	if !Enabled() {
+		return nil
+	}
+	if _, err := Encrypt("check", "check", "check"); err != nil {
+		return fmt.Errorf("PII_ENCRYPTION_ENABLED needs a master key: %w", err)
+	}
+	return nil
+}
+
+func loadHashKey() ([]byte, error) {
+	hashKeyOnce.Do(func() {
+		value := strings.TrimSpace(config.Configs.PiiHashKey)
+		if value == "" {
+			return
+		}
+		key, err := base64.StdEncoding.DecodeString(value)
+		if err != nil || len(key) < 32 {
+			hashKeyErr = errors.New("invalid PII_HASH_KEY: expected at least 32 base64 encoded bytes")
+			return
+		}
+		hashKey = key
+	})
+	return hashKey, hashKeyErr
+}
+
+// Hash returns the keyed hash of value, or value itself while PII_HASH_KEY is not set. An empty value stays empty.
+func Hash(value string) string {
+	key, err := loadHashKey()
+	if err != nil || key == nil || value == "" {
+		return value
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(strings.TrimSpace(value)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Encrypted reports whether value was encrypted by Encrypt.
+func Encrypted(value string) bool {
+	return strings.HasPrefix(value, Prefix)
+}
+
+// Encrypt seals the field of the message commId. Empty and already encrypted values are returned unchanged.
+func Encrypt(commId, field, value string) (string, error) {
+	if value == "" || Encrypted(value) {
+		return value, nil
+	}
+	envelope, err := secrets.Seal([]byte(value), aad(commId, field))
+	if err != nil {
+		return "", fmt.Errorf("failed to encrypt %s: %w", field, err)
+	}
+	return Prefix + envelope.KeyId + ":" + envelope.WrappedKey + ":" + envelope.Ciphertext, nil
+}
+
+// Decrypt opens a field sealed by Encrypt for the same message and field; plaintext is returned unchanged.
+func Decrypt(commId, field, value string) (string, error) {
+	if !Encrypted(value) {
+		return value, nil
+	}
+	parts := strings.SplitN(strings.TrimPrefix(value, Prefix), ":", 3)
+	if len(parts) != 3 {
+		return "", fmt.Errorf("malformed encrypted %s", field)
+	}
+	plaintext, err := secrets.Open(secrets.Envelope{KeyId: parts[0], WrappedKey: parts[1], Ciphertext: parts[2]}, aad(commId, field))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt %s: %w", field, err)
+	}
+	return string(plaintext), nil
+}
+
+// Rewrap wraps the data key of a field sealed by Encrypt with the current master key, after the master key was
+// rotated. It reports whether the value changed; plaintext and values wrapped by the current key are unchanged.
+func Rewrap(value string) (string, bool, error) {
+	if !Encrypted(value) {
+		return value, false, nil
+	}
+	parts := strings.SplitN(strings.TrimPrefix(value, Prefix), ":", 3)
+	if len(parts) != 3 {
+		return "", false, errors.New("malformed encrypted value")
+	}
+	envelope := secrets.Envelope{KeyId: parts[0], WrappedKey: parts[1], Ciphertext: parts[2]}
+	if secrets.Current(envelope) {
+		return value, false, nil
+	}
+	rewrapped, err := secrets.Rewrap(envelope)
+	if err != nil {
+		return "", false, err
+	}
+	return Prefix + rewrapped.KeyId + ":" + rewrapped.WrappedKey + ":" + rewrapped.Ciphertext, true, nil
+}
+
+// CurrentPrefix returns the prefix of the values sealed under the current master key; other values starting with
+// Prefix need Rewrap.
+func CurrentPrefix() (string, error) {
+	current, err := secrets.CurrentKeyId()
+	if err != nil {
+		return "", err
+	}
+	return Prefix + current + ":", nil
+}
+
+func aad(commId, field string) []byte {
+	return []byte(commId + "\x00" + field)
+}
+
+// EncryptRequest returns a copy of the message with its personal data encrypted; data.CommId must already be assigned.
+func EncryptRequest(data sdkModels.CommApiRequestBody) (sdkModels.CommApiRequestBody, error) {
+	data.Variables = maps.Clone(data.Variables)
+	err := transform(&data, Encrypt)
+	return data, err
+}
+
+// DecryptRequest decrypts the personal data of a consumed message in place.
+func DecryptRequest(data *sdkModels.CommApiRequestBody) error {
+	return transform(data, Decrypt)
+}
+
+func transform(data *sdkModels.CommApiRequestBody, apply func(commId, field, value string) (string, error)) error {
+	fields := map[string]*string{
+		"mobile":            &data.Mobile,
+		"email":             &data.Email,
+		"customerName":      &data.CustomerName,
+		"loanId":            &data.LoanId,
+		"applicationNumber": &data.ApplicationNumber,
+	}
+	for field, value := range fields {
+		result, err := apply(data.CommId, field, *value)
+		if err != nil {
+			return err
+		}
+		*value = result
+	}
+
+	for key, value := range data.Variables {
+		if !sensitiveVariables[strings.ToLower(key)] {
+			continue
+		}
+		result, err := apply(data.CommId, "variables."+key, value)
+		if err != nil {
+			return err
+		}
+		data.Variables[key] = result
+	}
+	return nil
+}
+
+// ProtectRow masks the personal data columns of an input or output row, storing their envelope in the
+// <column>Encrypted column and, with PII_HASH_KEY set, their keyed hash in the <column>Hash column.
+// Rows are left as they are unless encryption is enabled.
+func ProtectRow(commId string, row map[string]interface{}) error {
+	if !Enabled() {
+		return nil
+	}
+	keys := make([]string, 0, len(row))
+	for key := range row {
+		keys = append(keys, key)
+	}
+	for _, key := range keys {
+		column, mask, ok := protectedColumn(key)
+		if !ok {
+			continue
+		}
+		value, ok := row[key].(string)
+		if !ok || value == "" {
+			continue
+		}
+		encrypted, err := Encrypt(commId, column, value)
+		if err != nil {
+			return err
+		}
+		row[column+EncryptedSuffix] = encrypted
+		if key, _ := loadHashKey(); key != nil {
+			row[column+HashSuffix] = Hash(value)
+		}
+		row[key] = mask(value)
+	}
+	return nil
+}
+
+// protectedColumn returns the canonical name and the mask of the protected column key names in any case.
+func protectedColumn(key string) (string, func(string) string, bool) {
+	for column, mask := range protectedColumns {
+		if strings.EqualFold(key, column) {
+			return column, mask, true
+		}
+	}
+	return "", nil, false
+}
+
+// RevealRow returns the plaintext of the protected columns of a row stored by ProtectRow, keyed by column.
+func RevealRow(commId string, row map[string]interface{}) (map[string]string, error) {
+	revealed := map[string]string{}
+	for key, encrypted := range row {
+		if len(key) <= len(EncryptedSuffix) || !strings.EqualFold(key[len(key)-len(EncryptedSuffix):], EncryptedSuffix) {
+			continue
+		}
+		column, _, ok := protectedColumn(key[:len(key)-len(EncryptedSuffix)])
+		if !ok || encrypted == nil {
+			continue
+		}
+		var value string
+		switch v := encrypted.(type) {
+		case string:
+			value = v
+		case []byte:
+			value = string(v)
+		}
+		if value == "" {
+			continue
+		}
+		plaintext, err := Decrypt(commId, column, value)
+		if err != nil {
+			return nil, err
+		}
+		revealed[column] = plaintext
+	}
+	return revealed, nil
+}
@@ -0,0 +1,145 @@
+package pii
+
+import (
+	"encoding/base64"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/wecredit/communication-sdk/config"
+	"github.com/wecredit/communication-sdk/sdk/models/sdkModels"
+)
+
+func TestMain(m *testing.M) {
+	// The keys are read once, on first use
+	config.Configs.SecretsMasterKeys = "k1:" + base64.StdEncoding.EncodeToString([]byte(strings.Repeat("a", 32)))
+	config.Configs.PiiHashKey = base64.StdEncoding.EncodeToString([]byte(strings.Repeat("h", 32)))
+	config.Configs.PiiEncryptionEnabled = "true"
+	os.Exit(m.Run())
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	encrypted, err := Encrypt("comm-1", "mobile", "9876543210")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(encrypted, Prefix+"k1:") || strings.Contains(encrypted, "9876543210") {
+		t.Fatalf("unexpected encrypted value %q", encrypted)
+	}
+	if again, err := Encrypt("comm-1", "mobile", encrypted); err != nil || again != encrypted {
+		t.Fatalf("expected an encrypted value to be returned unchanged, got %q, %v", again, err)
+	}
+
+	plaintext, err := Decrypt("comm-1", "mobile", encrypted)
+	if err != nil || plaintext != "9876543210" {
+		t.Fatalf("Decrypt() = %q, %v", plaintext, err)
+	}
+
+	tests := []struct {
+		name   string
+		commId string
+		field  string
+	}{
+		{name: "copied into another message", commId: "comm-2", field: "mobile"},
+		{name: "copied into another field", commId: "comm-1", field: "email"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Decrypt(tt.commId, tt.field, encrypted); err == nil {
+				t.Fatal("expected the value not to decrypt outside its message and field")
+			}
+		})
+	}
+
+	if _, err := Decrypt("comm-1", "mobile", Prefix+"k1:truncated"); err == nil {
+		t.Fatal("expected a malformed value not to decrypt")
+	}
+}
+
+func TestPlaintextPassesThrough(t *testing.T) {
+	for _, value := range []string{"", "9876543210"} {
+		if got, err := Decrypt("comm-1", "mobile", value); err != nil || got != value {
+			t.Fatalf("Decrypt(%q) = %q, %v", value, got, err)
+		}
+		if got, changed, err := Rewrap(value); err != nil || changed || got != value {
+			t.Fatalf("Rewrap(%q) = %q, %v, %v", value, got, changed, err)
+		}
+	}
+	if got, err := Encrypt("comm-1", "mobile", ""); err != nil || got != "" {
+		t.Fatalf("expected an empty value to stay empty, got %q, %v", got, err)
+	}
+}
+
+func TestEncryptRequest(t *testing.T) {
+	request := sdkModels.CommApiRequestBody{
+		CommId:       "comm-1",
+		Mobile:       "9876543210",
+		Email:        "jane@example.com",
+		CustomerName: "Jane",
+		ProcessName:  "COLLECTION",
+		Variables:    map[string]string{"CustomerName": "Jane", "EmiAmount": "1500"},
+	}
+
+	encrypted, err := EncryptRequest(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, value := range map[string]string{
+		"mobile":                 encrypted.Mobile,
+		"email":                  encrypted.Email,
+		"customerName":           encrypted.CustomerName,
+		"variables.CustomerName": encrypted.Variables["CustomerName"],
+	} {
+		if !Encrypted(value) {
+			t.Fatalf("expected %s to be encrypted, got %q", name, value)
+		}
+	}
+	if encrypted.Variables["EmiAmount"] != "1500" || encrypted.ProcessName != "COLLECTION" {
+		t.Fatal("expected fields without personal data to stay as they are")
+	}
+	if request.Variables["CustomerName"] != "Jane" {
+		t.Fatal("expected the variables of the original request not to change")
+	}
+
+	if err := DecryptRequest(&encrypted); err != nil {
+		t.Fatal(err)
+	}
+	if encrypted.Mobile != request.Mobile || encrypted.Email != request.Email || encrypted.CustomerName != request.CustomerName ||
+		encrypted.Variables["CustomerName"] != "Jane" {
+		t.Fatalf("unexpected decrypted request %+v", encrypted)
+	}
+}
+
+func TestProtectAndRevealRow(t *testing.T) {
+	row := map[string]interface{}{
+		"CommId":       "comm-1",
+		"MobileNumber": "919876543210",
+		"email":        "jane@example.com", // the email output row stores its address in lowercase
+		"Status":       "SENT",
+	}
+	if err := ProtectRow("comm-1", row); err != nil {
+		t.Fatal(err)
+	}
+
+	if row["MobileNumber"] != "91******3210" || row["email"] != "j***@example.com" || row["Status"] != "SENT" {
+		t.Fatalf("unexpected masked row %v", row)
+	}
+	if row["MobileNumberHash"] != Hash("919876543210") || row["EmailHash"] != Hash("jane@example.com") {
+		t.Fatal("expected the keyed hash of the protected columns")
+	}
+	if Hash("919876543210") == "919876543210" {
+		t.Fatal("expected the hash to differ from the value once PII_HASH_KEY is set")
+	}
+
+	revealed, err := RevealRow("comm-1", row)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if revealed["MobileNumber"] != "919876543210" || revealed["Email"] != "jane@example.com" {
+		t.Fatalf("unexpected revealed columns %v", revealed)
+	}
+
+	if _, err := RevealRow("comm-2", row); err == nil {
+		t.Fatal("expected the row not to be revealed for another message")
+	}
+}
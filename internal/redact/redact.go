@@ -0,0 +1,171 @@
+// Package redact strips credentials from vendor payloads and masks the mobile numbers and email addresses in them,
+// so payloads can be shown in previews and kept in the vendor audit log.
+package redact
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Value replaces every credential field.
+const Value = "[REDACTED]"
+
+// secretKeys are vendor payload fields that carry credentials; their whole value is replaced, at any depth.
+var secretKeys = map[string]bool{
+	"pass":          true,
+	"password":      true,
+	"user":          true,
+	"userid":        true,
+	"username":      true,
+	"credentials":   true,
+	"authorization": true,
+	"access_token":  true,
+	"refresh_token": true,
+	"token":         true,
+	"apikey":        true,
+	"api_key":       true,
+	"client_secret": true,
+}
+
+var (
+	mobilePattern = regexp.MustCompile(`\b(?:\+?91)?[6-9]\d{9}\b`)
+	emailPattern  = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+)
+
+// Secrets returns a copy of payload with credential fields replaced, at any depth.
+func Secrets(payload map[string]interface{}) map[string]interface{} {
+	return walk(payload, func(value string) string { return value })
+}
+
+// Payload returns a copy of payload with credential fields replaced and mobile numbers and email addresses masked.
+func Payload(payload map[string]interface{}) map[string]interface{} {
+	return walk(payload, Text)
+}
+
+// Body redacts a raw request or response body: JSON and form encoded bodies field by field, anything else as text.
+func Body(body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	// Numbers are decoded as json.Number, so a mobile number sent as a number is masked without losing digits
+	var decoded interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&decoded); err == nil && !decoder.More() {
+		redacted, err := json.Marshal(redactValue(decoded, Text))
+		if err == nil {
+			return string(redacted)
+		}
+	}
+
+	if form, err := url.ParseQuery(string(body)); err == nil && strings.Contains(string(body), "=") {
+		for key, values := range form {
+			if secretKeys[strings.ToLower(key)] {
+				form[key] = []string{Value}
+				continue
+			}
+			for i := range values {
+				values[i] = Text(values[i])
+			}
+		}
+		return form.Encode()
+	}
+
+	return Text(string(body))
+}
+
+// Text masks every mobile number and email address in s.
+func Text(s string) string {
+	s = emailPattern.ReplaceAllStringFunc(s, Email)
+	return mobilePattern.ReplaceAllStringFunc(s, Mobile)
+}
+
+// Mobile keeps the last four digits of a mobile number: 91******3210.
+func Mobile(mobile string) string {
+	if len(mobile) <= 4 {
+		return strings.Repeat("*", len(mobile))
+	}
+	keep := len(mobile) - 4
+	prefix := ""
+	if len(mobile) > 10 {
+		// the country code is not personal
+		prefix = mobile[:len(mobile)-10]
+	}
+	return prefix + strings.Repeat("*", keep-len(prefix)) + mobile[keep:]
+}
+
+// Email keeps the first character of the local part and the domain: j***@example.com.
+func Email(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at <= 0 {
+		return email
+	}
+	return email[:1] + "***" + email[at:]
+}
+
+func walk(payload map[string]interface{}, mask func(string) string) map[string]interface{} {
+	if payload == nil {
+		return nil
+	}
+	redacted := make(map[string]interface{}, len(payload))
+	for key, value := range payload {
+		if secretKeys[strings.ToLower(key)] {
+			redacted[key] = Value
+			continue
+		}
+		redacted[key] = redactValue(value, mask)
+	}
+	return redacted
+}
+
+func redactValue(value interface{}, mask func(string) string) interface{} {
+	switch v := value.(type) {
+	case string:
+		return mask(v)
+	case json.Number:
+		return maskNumber(v.String(), value, mask)
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+			return maskNumber(strconv.FormatFloat(v, 'f', -1, 64), value, mask)
+		}
+	case int:
+		return maskNumber(strconv.Itoa(v), value, mask)
+	case int64:
+		return maskNumber(strconv.FormatInt(v, 10), value, mask)
+	case map[string]interface{}:
+		return walk(v, mask)
+	case map[string]string:
+		converted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			converted[key] = item
+		}
+		return walk(converted, mask)
+	case []map[string]interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, item := range v {
+			redacted[i] = walk(item, mask)
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, item := range v {
+			redacted[i] = redactValue(item, mask)
+		}
+		return redacted
+	}
+	return value
+}
+
+// maskNumber masks a number holding a mobile number, which then becomes a masked string; other numbers are kept.
+func maskNumber(digits string, value interface{}, mask func(string) string) interface{} {
+	if masked := mask(digits); masked != digits {
+		return masked
+	}
+	return value
+}
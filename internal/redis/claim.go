@@ -0,0 +1,78 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// States of a CommId claimed by the consumer
+const (
+	ClaimInProgress = "in-progress"
+	ClaimDone       = "done"
+	ClaimFailed     = "failed"
+)
+
+// CommClaimKey holds the processing state of a message, so a redelivered message is not sent twice
+func CommClaimKey(commId string) string {
+	return "comm_claim:" + commId
+}
+
+// claimScript claims the CommId for ARGV[1] unless it is done or held by another worker whose lease has not expired.
+// The lease is measured with the clock of Redis, so it does not depend on the clocks of the consumers.
+// Returns the state found and 1 when the claim was taken.
+var claimScript = redis.NewScript(`
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local state = redis.call('HGET', KEYS[1], 'state')
+if state == 'done' then
+	return {state, 0}
+end
+if state == 'in-progress' and tonumber(redis.call('HGET', KEYS[1], 'leaseUntil') or '0') > now then
+	return {state, 0}
+end
+redis.call('HSET', KEYS[1], 'state', 'in-progress', 'owner', ARGV[1], 'leaseUntil', now + tonumber(ARGV[2]))
+redis.call('HINCRBY', KEYS[1], 'attempts', 1)
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+return {state or '', 1}
+`)
+
+// releaseScript records the outcome ARGV[2] of the claim of ARGV[1]; it does nothing once another worker took the
+// CommId over after the lease expired. Returns 1 when the outcome was recorded.
+var releaseScript = redis.NewScript(`
+if redis.call('HGET', KEYS[1], 'owner') ~= ARGV[1] then
+	return 0
+end
+redis.call('HSET', KEYS[1], 'state', ARGV[2])
+redis.call('HDEL', KEYS[1], 'leaseUntil')
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+return 1
+`)
+
+// ClaimComm atomically claims the CommId for owner for the lease. It returns whether the claim was taken and the
+// state the CommId was in: done, or in-progress under another worker's lease, when it was not; empty, failed, or
+// in-progress past its lease when it was. retention bounds how long the state is kept.
+func ClaimComm(ctx context.Context, rdb *redis.Client, commId, owner string, lease, retention time.Duration) (bool, string, error) {
+	result, err := claimScript.Run(ctx, rdb, []string{CommClaimKey(commId)}, owner, lease.Milliseconds(), retention.Milliseconds()).Slice()
+	if err != nil {
+		return false, "", fmt.Errorf("failed to claim CommId %s: %w", commId, err)
+	}
+	if len(result) != 2 {
+		return false, "", fmt.Errorf("failed to claim CommId %s: unexpected reply %v", commId, result)
+	}
+	state, _ := result[0].(string)
+	claimed, _ := result[1].(int64)
+	return claimed == 1, state, nil
+}
+
+// ReleaseComm records the outcome, done or failed, of the claim owner holds on the CommId. It returns false when the
+// lease had expired and another worker claimed the CommId since, in which case that worker records the outcome.
+func ReleaseComm(ctx context.Context, rdb *redis.Client, commId, owner, state string, retention time.Duration) (bool, error) {
+	released, err := releaseScript.Run(ctx, rdb, []string{CommClaimKey(commId)}, owner, state, retention.Milliseconds()).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to record %s for CommId %s: %w", state, commId, err)
+	}
+	return released == 1, nil
+}
@@ -0,0 +1,89 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestRedis(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return server, client
+}
+
+func TestClaimComm(t *testing.T) {
+	server, rdb := newTestRedis(t)
+	ctx := context.Background()
+	now := time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+	server.SetTime(now)
+	const lease, retention = time.Minute, time.Hour
+
+	claimed, state, err := ClaimComm(ctx, rdb, "c1", "worker-1", lease, retention)
+	if err != nil || !claimed || state != "" {
+		t.Fatalf("first claim = %v, %q, %v", claimed, state, err)
+	}
+	if ttl := server.TTL(CommClaimKey("c1")); ttl != retention {
+		t.Fatalf("expected the claim to be kept for the retention, got %s", ttl)
+	}
+
+	claimed, state, err = ClaimComm(ctx, rdb, "c1", "worker-2", lease, retention)
+	if err != nil || claimed || state != ClaimInProgress {
+		t.Fatalf("claim under another worker's lease = %v, %q, %v", claimed, state, err)
+	}
+
+	// The lease is measured with the clock of Redis
+	server.SetTime(now.Add(lease + time.Second))
+	claimed, state, err = ClaimComm(ctx, rdb, "c1", "worker-2", lease, retention)
+	if err != nil || !claimed || state != ClaimInProgress {
+		t.Fatalf("claim past the lease = %v, %q, %v", claimed, state, err)
+	}
+	if attempts := server.HGet(CommClaimKey("c1"), "attempts"); attempts != "2" {
+		t.Fatalf("expected 2 attempts, got %q", attempts)
+	}
+
+	// The worker whose lease expired cannot record its outcome over the new owner
+	released, err := ReleaseComm(ctx, rdb, "c1", "worker-1", ClaimDone, retention)
+	if err != nil || released {
+		t.Fatalf("release by the previous owner = %v, %v", released, err)
+	}
+	if state := server.HGet(CommClaimKey("c1"), "state"); state != ClaimInProgress {
+		t.Fatalf("expected the claim to stay in progress, got %q", state)
+	}
+
+	released, err = ReleaseComm(ctx, rdb, "c1", "worker-2", ClaimDone, retention)
+	if err != nil || !released {
+		t.Fatalf("release by the owner = %v, %v", released, err)
+	}
+	if server.HGet(CommClaimKey("c1"), "leaseUntil") != "" {
+		t.Fatal("expected the lease to be cleared once the outcome is recorded")
+	}
+
+	claimed, state, err = ClaimComm(ctx, rdb, "c1", "worker-3", lease, retention)
+	if err != nil || claimed || state != ClaimDone {
+		t.Fatalf("claim of a done CommId = %v, %q, %v", claimed, state, err)
+	}
+}
+
+func TestClaimFailedComm(t *testing.T) {
+	_, rdb := newTestRedis(t)
+	ctx := context.Background()
+
+	if claimed, _, err := ClaimComm(ctx, rdb, "c1", "worker-1", time.Minute, time.Hour); err != nil || !claimed {
+		t.Fatalf("first claim = %v, %v", claimed, err)
+	}
+	if released, err := ReleaseComm(ctx, rdb, "c1", "worker-1", ClaimFailed, time.Hour); err != nil || !released {
+		t.Fatalf("release = %v, %v", released, err)
+	}
+
+	// A failed message is retried at once by whichever worker receives it again
+	claimed, state, err := ClaimComm(ctx, rdb, "c1", "worker-2", time.Minute, time.Hour)
+	if err != nil || !claimed || state != ClaimFailed {
+		t.Fatalf("claim of a failed CommId = %v, %q, %v", claimed, state, err)
+	}
+}
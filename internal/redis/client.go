@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/redis/go-redis/v9"
+	"github.com/wecredit/communication-sdk/internal/metrics"
 	"github.com/wecredit/communication-sdk/sdk/utils"
 )
 
@@ -27,6 +28,8 @@ func RedisClient(address, password string) error {
 		TLSConfig: &tls.Config{InsecureSkipVerify: true}, // Set false in production with valid certs
 	})
 
+	RDB.AddHook(metrics.RedisHook{})
+
 	// Test the Redis connection
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
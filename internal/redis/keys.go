@@ -1,7 +1,21 @@
 package redis
 
-var (
-	CreditSeaWhatsappCount string = "credit_sea_whatsapp_count"
-	CreditSeaRcsCount      string = "credit_sea_rcs_count"
-	CreditSeaSmsCount      string = "credit_sea_sms_count"
-)
+// DailyCountKey counts the messages a client sent on a channel during one IST day, given as 2006-01-02
+func DailyCountKey(client, channel, day string) string {
+	return "daily_count:" + client + ":" + channel + ":" + day
+}
+
+// CacheVersionKey holds the latest published version of a cached dataset
+func CacheVersionKey(dataset string) string {
+	return "cache_version:" + dataset
+}
+
+// AuthFailuresKey counts the failed credential checks of a username from one source IP within the lockout window
+func AuthFailuresKey(username, source string) string {
+	return "auth_failures:" + username + ":" + source
+}
+
+// CronLockKey is held by the pod running a cluster wide cron job, so the job runs on one pod at a time
+func CronLockKey(job string) string {
+	return "cron_lock:" + job
+}
@@ -0,0 +1,46 @@
+package redis
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/wecredit/communication-sdk/sdk/utils"
+)
+
+// unlockScript deletes the lock only while ARGV[1] still holds it, so a holder whose lock expired never releases
+// the lock another pod has taken since.
+var unlockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// TryLock takes key for ttl unless another pod holds it, and returns the function releasing it. It reports false
+// when the lock is held elsewhere. The ttl bounds how long a pod that dies while holding the lock keeps it.
+func TryLock(ctx context.Context, key string, ttl time.Duration) (func(), bool, error) {
+	if RDB == nil {
+		return nil, false, fmt.Errorf("redis client not initialized")
+	}
+
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return nil, false, fmt.Errorf("failed to generate lock token: %w", err)
+	}
+	owner := hex.EncodeToString(token)
+
+	taken, err := RDB.SetNX(ctx, key, owner, ttl).Result()
+	if err != nil || !taken {
+		return nil, false, err
+	}
+	release := func() {
+		if err := unlockScript.Run(context.Background(), RDB, []string{key}, owner).Err(); err != nil {
+			utils.Error(fmt.Errorf("failed to release lock %s: %v", key, err))
+		}
+	}
+	return release, true, nil
+}
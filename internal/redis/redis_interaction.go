@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/wecredit/communication-sdk/internal/models/redisModels"
@@ -36,34 +37,28 @@ func StoreDataInRedis(query string, db *gorm.DB, RDB *redis.Client, redisKey str
 	return nil
 }
 
-func InitCreditSeaCounter(ctx context.Context, redisClient *redis.Client, key string, initialValue int) error {
-	ok, err := redisClient.SetNX(ctx, key, initialValue, 0).Result()
-	if err != nil {
-		return err
-	}
-	if !ok {
-		// Key already exists, no need to init
-		return nil
-	}
-	return nil
-}
-
-func IncrementCreditSeaCounter(ctx context.Context, redisClient *redis.Client, key string) error {
-	return redisClient.Incr(ctx, key).Err()
+// dailyCountRetention keeps a day's counter past the end of the day, so it is not lost while the day is reported on
+const dailyCountRetention = 48 * time.Hour
+
+// IncrementDailyCount counts one more message of the client on the channel for the current IST day.
+func IncrementDailyCount(ctx context.Context, redisClient *redis.Client, client, channel string) error {
+	key := DailyCountKey(client, channel, utils.IstNow().Format(time.DateOnly))
+	pipe := redisClient.TxPipeline()
+	pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, dailyCountRetention)
+	_, err := pipe.Exec(ctx)
+	return err
 }
 
-func GetCreditSeaCounter(ctx context.Context, redisClient *redis.Client, key string) (int, error) {
-	val, err := redisClient.Get(ctx, key).Int()
+// GetDailyCount returns the number of messages the client sent on the channel during the current IST day.
+func GetDailyCount(ctx context.Context, redisClient *redis.Client, client, channel string) (int, error) {
+	val, err := redisClient.Get(ctx, DailyCountKey(client, channel, utils.IstNow().Format(time.DateOnly))).Int()
 	if err == redis.Nil {
-		return 0, nil // key not found
+		return 0, nil // no message sent yet today
 	}
 	return val, err
 }
 
-func ResetCreditSeaCounter(ctx context.Context, redisClient *redis.Client, key string) error {
-	return redisClient.Set(ctx, key, 0, 0).Err()
-}
-
 // Check if mobile_channel exists and return both transactionId and errorMessage if present
 func GetMobileDataFromRedis(CommIdempotentKey string, redisKey string, rdb *redis.Client) (bool, string, string, error) {
 	ctx := context.Background()
@@ -106,6 +101,17 @@ func SetMobileChannelKey(RDB *redis.Client, commIdempotentKey, redisKey string)
 	return nil
 }
 
+// DeleteMobileChannelKey removes a mobile_channel key so the message can be submitted again
+func DeleteMobileChannelKey(RDB *redis.Client, commIdempotentKey, redisKey string) error {
+	ctx := context.Background()
+	if err := RDB.HDel(ctx, commIdempotentKey, redisKey).Err(); err != nil {
+		utils.Error(fmt.Errorf("failed to delete key %s from redis: %v", redisKey, err))
+		return err
+	}
+	utils.Info(fmt.Sprintf("Key %s removed from hash %s", redisKey, commIdempotentKey))
+	return nil
+}
+
 // 2. Update the value (e.g. responseId) for an existing mobile_channel key
 // This function is kept for backward compatibility
 func UpdateMobileChannelValue(RDB *redis.Client, commIdempotentKey, redisKey, responseId string) error {
@@ -0,0 +1,238 @@
+// Package secrets encrypts values stored in the database with envelope encryption: every value is sealed with its own
+// random data key, and only the data key, wrapped by a master key from SECRETS_MASTER_KEYS, is stored next to it.
+// Rotating the master key therefore only re-wraps data keys and never requires the plaintext.
+//
+// SECRETS_MASTER_KEY_FILE names a local file of master keys, one id:base64 pair per line, which stands in for a
+// KMS in development and tests. Its keys come after those of SECRETS_MASTER_KEYS.
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/wecredit/communication-sdk/config"
+)
+
+// ErrNoMasterKey is returned when no master key is configured or has no key with the id an envelope was wrapped with.
+var ErrNoMasterKey = errors.New("master key not configured")
+
+// Envelope is a sealed value as it is stored: the ciphertext, its wrapped data key and the id of the master key.
+type Envelope struct {
+	Ciphertext string // base64 of nonce and ciphertext under the data key
+	WrappedKey string // base64 of nonce and data key under the master key
+	KeyId      string
+}
+
+type masterKeys struct {
+	current string
+	keys    map[string][]byte
+	err     error
+}
+
+var (
+	loadOnce sync.Once
+	loaded   masterKeys
+)
+
+func keys() masterKeys {
+	loadOnce.Do(func() {
+		value := config.Configs.SecretsMasterKeys
+		if path := strings.TrimSpace(config.Configs.SecretsMasterKeyFile); path != "" {
+			fileKeys, err := readKeyFile(path)
+			if err != nil {
+				loaded = masterKeys{err: err}
+				return
+			}
+			value += "," + fileKeys
+		}
+		loaded = parseMasterKeys(value)
+	})
+	return loaded
+}
+
+// readKeyFile returns the keys of a key file as comma separated pairs; blank lines and # comments are skipped.
+func readKeyFile(path string) (string, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read master key file: %w", err)
+	}
+	var pairs []string
+	for _, line := range strings.Split(string(contents), "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			pairs = append(pairs, line)
+		}
+	}
+	return strings.Join(pairs, ","), nil
+}
+
+func parseMasterKeys(value string) masterKeys {
+	parsed := masterKeys{keys: map[string][]byte{}}
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		id, encoded, ok := strings.Cut(pair, ":")
+		if !ok || id == "" {
+			parsed.err = fmt.Errorf("invalid master key %q: expected id:base64", id)
+			return parsed
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != 32 {
+			parsed.err = fmt.Errorf("invalid master key %s: expected 32 base64 encoded bytes", id)
+			return parsed
+		}
+		if parsed.current == "" {
+			parsed.current = id
+		}
+		parsed.keys[id] = key
+	}
+	return parsed
+}
+
+// Seal encrypts plaintext under a new data key. aad binds the envelope to its row, so it cannot be opened for another one.
+func Seal(plaintext, aad []byte) (Envelope, error) {
+	master := keys()
+	if master.err != nil {
+		return Envelope{}, master.err
+	}
+	if master.current == "" {
+		return Envelope{}, ErrNoMasterKey
+	}
+
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return Envelope{}, fmt.Errorf("failed to generate data key: %w", err)
+	}
+	ciphertext, err := seal(dataKey, plaintext, aad)
+	if err != nil {
+		return Envelope{}, err
+	}
+	wrappedKey, err := seal(master.keys[master.current], dataKey, []byte(master.current))
+	if err != nil {
+		return Envelope{}, err
+	}
+
+	return Envelope{
+		Ciphertext: base64.StdEncoding.EncodeToString(ciphertext),
+		WrappedKey: base64.StdEncoding.EncodeToString(wrappedKey),
+		KeyId:      master.current,
+	}, nil
+}
+
+// Open decrypts an envelope sealed with the same aad.
+func Open(envelope Envelope, aad []byte) ([]byte, error) {
+	master := keys()
+	if master.err != nil {
+		return nil, master.err
+	}
+	masterKey, ok := master.keys[envelope.KeyId]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNoMasterKey, envelope.KeyId)
+	}
+
+	wrappedKey, err := base64.StdEncoding.DecodeString(envelope.WrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid wrapped key: %w", err)
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(envelope.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ciphertext: %w", err)
+	}
+
+	dataKey, err := open(masterKey, wrappedKey, []byte(envelope.KeyId))
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+	plaintext, err := open(dataKey, ciphertext, aad)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt: %w", err)
+	}
+	return plaintext, nil
+}
+
+// Current reports whether the envelope is wrapped by the current master key, i.e. whether it needs re-wrapping after a rotation.
+func Current(envelope Envelope) bool {
+	return envelope.KeyId == keys().current
+}
+
+// CurrentKeyId returns the id of the master key new envelopes are wrapped with.
+func CurrentKeyId() (string, error) {
+	master := keys()
+	if master.err != nil {
+		return "", master.err
+	}
+	if master.current == "" {
+		return "", ErrNoMasterKey
+	}
+	return master.current, nil
+}
+
+// Rewrap wraps the data key of the envelope with the current master key; the ciphertext, and so the aad it is
+// bound to, stays as it is. An envelope already wrapped by the current key is returned unchanged.
+func Rewrap(envelope Envelope) (Envelope, error) {
+	current, err := CurrentKeyId()
+	if err != nil {
+		return Envelope{}, err
+	}
+	if envelope.KeyId == current {
+		return envelope, nil
+	}
+	masterKey, ok := keys().keys[envelope.KeyId]
+	if !ok {
+		return Envelope{}, fmt.Errorf("%w: %s", ErrNoMasterKey, envelope.KeyId)
+	}
+
+	wrappedKey, err := base64.StdEncoding.DecodeString(envelope.WrappedKey)
+	if err != nil {
+		return Envelope{}, fmt.Errorf("invalid wrapped key: %w", err)
+	}
+	dataKey, err := open(masterKey, wrappedKey, []byte(envelope.KeyId))
+	if err != nil {
+		return Envelope{}, fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+	rewrapped, err := seal(keys().keys[current], dataKey, []byte(current))
+	if err != nil {
+		return Envelope{}, err
+	}
+	return Envelope{Ciphertext: envelope.Ciphertext, WrappedKey: base64.StdEncoding.EncodeToString(rewrapped), KeyId: current}, nil
+}
+
+func seal(key, plaintext, aad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return gcm.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+func open(key, sealed, aad []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, aad)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,148 @@
+package secrets
+
+import (
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testKey(fill byte) string {
+	return base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(fill), 32)))
+}
+
+// useMasterKeys replaces the master keys for the test, as a restart with SECRETS_MASTER_KEYS set to value would.
+func useMasterKeys(t *testing.T, value string) {
+	t.Helper()
+	loadOnce.Do(func() {})
+	previous := loaded
+	loaded = parseMasterKeys(value)
+	t.Cleanup(func() { loaded = previous })
+}
+
+func TestSealOpen(t *testing.T) {
+	useMasterKeys(t, "k1:"+testKey('a'))
+
+	envelope, err := Seal([]byte("s3cret"), []byte("row-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if envelope.KeyId != "k1" || strings.Contains(envelope.Ciphertext, "s3cret") {
+		t.Fatalf("unexpected envelope %+v", envelope)
+	}
+
+	plaintext, err := Open(envelope, []byte("row-1"))
+	if err != nil || string(plaintext) != "s3cret" {
+		t.Fatalf("Open() = %q, %v", plaintext, err)
+	}
+
+	if _, err := Open(envelope, []byte("row-2")); err == nil {
+		t.Fatal("expected an envelope moved to another row not to open")
+	}
+
+	tampered := envelope
+	tampered.KeyId = "k2"
+	if _, err := Open(tampered, []byte("row-1")); !errors.Is(err, ErrNoMasterKey) {
+		t.Fatalf("expected ErrNoMasterKey for an unknown key id, got %v", err)
+	}
+
+	again, err := Seal([]byte("s3cret"), []byte("row-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Ciphertext == envelope.Ciphertext || again.WrappedKey == envelope.WrappedKey {
+		t.Fatal("expected every seal to use a new data key")
+	}
+}
+
+func TestRotation(t *testing.T) {
+	useMasterKeys(t, "k1:"+testKey('a'))
+	envelope, err := Seal([]byte("s3cret"), []byte("row-1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// k2 becomes current while k1 is kept to open the envelopes it wrapped
+	useMasterKeys(t, "k2:"+testKey('b')+",k1:"+testKey('a'))
+	if Current(envelope) {
+		t.Fatal("expected an envelope wrapped by the previous key not to be current")
+	}
+	if plaintext, err := Open(envelope, []byte("row-1")); err != nil || string(plaintext) != "s3cret" {
+		t.Fatalf("expected the previous key to still open its envelopes, got %q, %v", plaintext, err)
+	}
+
+	rewrapped, err := Rewrap(envelope)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rewrapped.KeyId != "k2" || rewrapped.Ciphertext != envelope.Ciphertext || !Current(rewrapped) {
+		t.Fatalf("unexpected re-wrapped envelope %+v", rewrapped)
+	}
+	if unchanged, err := Rewrap(rewrapped); err != nil || unchanged != rewrapped {
+		t.Fatalf("expected a current envelope to be returned unchanged, got %+v, %v", unchanged, err)
+	}
+
+	// Once every envelope is re-wrapped, k1 can be dropped
+	useMasterKeys(t, "k2:"+testKey('b'))
+	if plaintext, err := Open(rewrapped, []byte("row-1")); err != nil || string(plaintext) != "s3cret" {
+		t.Fatalf("Open() = %q, %v", plaintext, err)
+	}
+	if _, err := Open(envelope, []byte("row-1")); !errors.Is(err, ErrNoMasterKey) {
+		t.Fatalf("expected ErrNoMasterKey once the previous key is dropped, got %v", err)
+	}
+}
+
+func TestNoMasterKey(t *testing.T) {
+	useMasterKeys(t, "")
+	if _, err := Seal([]byte("s3cret"), nil); !errors.Is(err, ErrNoMasterKey) {
+		t.Fatalf("expected ErrNoMasterKey, got %v", err)
+	}
+	if _, err := CurrentKeyId(); !errors.Is(err, ErrNoMasterKey) {
+		t.Fatalf("expected ErrNoMasterKey, got %v", err)
+	}
+}
+
+func TestParseMasterKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		current string
+		wantErr bool
+	}{
+		{name: "empty", value: ""},
+		{name: "first key is current", value: "k2:" + testKey('b') + ", k1:" + testKey('a'), current: "k2"},
+		{name: "missing id", value: testKey('a'), wantErr: true},
+		{name: "short key", value: "k1:" + base64.StdEncoding.EncodeToString([]byte("short")), wantErr: true},
+		{name: "not base64", value: "k1:not base64", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed := parseMasterKeys(tt.value)
+			if (parsed.err != nil) != tt.wantErr {
+				t.Fatalf("parseMasterKeys() error = %v, wantErr %v", parsed.err, tt.wantErr)
+			}
+			if !tt.wantErr && parsed.current != tt.current {
+				t.Fatalf("current = %q, want %q", parsed.current, tt.current)
+			}
+		})
+	}
+}
+
+func TestReadKeyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "master.keys")
+	contents := "# development keys\nk2:" + testKey('b') + "\n\nk1:" + testKey('a') + "\n"
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	pairs, err := readKeyFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed := parseMasterKeys(pairs)
+	if parsed.err != nil || parsed.current != "k2" || len(parsed.keys) != 2 {
+		t.Fatalf("unexpected keys from file: current %q, %d keys, %v", parsed.current, len(parsed.keys), parsed.err)
+	}
+}
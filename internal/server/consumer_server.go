@@ -1,19 +1,36 @@
 package server
 
 import (
+	"context"
 	"log"
 	"net"
+	"net/http"
 	"fmt"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/wecredit/communication-sdk/config"
 	"github.com/wecredit/communication-sdk/cron"
 	"github.com/wecredit/communication-sdk/health"
 	"github.com/wecredit/communication-sdk/internal/database"
 	"github.com/wecredit/communication-sdk/internal/handlers"
+	"github.com/wecredit/communication-sdk/internal/logLevel"
+	"github.com/wecredit/communication-sdk/internal/metrics"
+	"github.com/wecredit/communication-sdk/internal/middleware"
+	"github.com/wecredit/communication-sdk/internal/pii"
 	apiServices "github.com/wecredit/communication-sdk/internal/services/apiServices"
+	"github.com/wecredit/communication-sdk/pkg/cache"
 	"github.com/wecredit/communication-sdk/sdk/utils"
 	services "github.com/wecredit/communication-sdk/internal/services/consumerServices"
+	"github.com/wecredit/communication-sdk/internal/services/webhookService"
+	"github.com/wecredit/communication-sdk/internal/tracing"
+	"github.com/wecredit/communication-sdk/internal/vendorAccounts"
+	"github.com/wecredit/communication-sdk/internal/vendorAudit"
 )
 
 func GetLocalIP() string {
@@ -36,8 +53,26 @@ func GetLocalIP() string {
 }
 
 func StartConsumer(port string) {
+	startTracing()
+	// A missing or invalid PII key would fail every message, so the pod does not start without one
+	if err := pii.Check(); err != nil {
+		log.Fatalf("Failed to start consumer: %v", err)
+	}
+	seedVendorAccounts()
+	bootstrapAdminKey()
+	if err := webhookService.SealPlaintextSecrets(database.DBtechWrite); err != nil {
+		utils.Error(err)
+	}
 	go services.ConsumerService(10, config.Configs.AwsQueueUrl)
-	go cron.StartMidnightResetCron()
+	go cron.StartTemplateActivationCron()
+	go webhookService.StartDeliveryWorker(context.Background(), database.DBtechWrite)
+	go cache.StartInvalidationSubscriber(context.Background())
+	go logLevel.StartSubscriber(context.Background())
+	go webhookService.StartStatusEventsSubscriber(context.Background())
+	go cache.StartPeriodicRefresh(context.Background())
+	go vendorAudit.StartWriter(context.Background(), database.DBtechWrite)
+	go cron.StartVendorAuditRetentionCron()
+	go StartGrpcServer(config.Configs.GrpcPort, config.Configs.GrpcTlsCert, config.Configs.GrpcTlsKey)
 	utils.Debug(fmt.Sprintf("Starting Consumer Server on port %s", port))
 
 	// Set up Gin router
@@ -45,39 +80,202 @@ func StartConsumer(port string) {
 
 	r.GET("/health", health.HealthCheckHandler(port))
 
-	vendorHandler := handlers.NewVendorHandler(apiServices.NewVendorService(database.DBtechRead)) // Create handler for vendors passing them database object
-	vendors := r.Group("/vendors")
+	if err := metrics.Register(prometheus.DefaultRegisterer); err != nil {
+		utils.Error(fmt.Errorf("failed to register metrics: %v", err))
+	}
+	go startMetricsServer(config.Configs.MetricsPort)
+
+	commHandler := handlers.NewCommHandler(apiServices.NewCommService(database.DBtechWrite))
+	communications := r.Group("/v1/communications", middleware.Tracing(), middleware.GinBasicAuth())
 	{
-		vendors.GET("/", vendorHandler.GetVendors) // endpoint:- /vendors; filter: ?channel=WHATSAPP
-		vendors.POST("/add-vendor", vendorHandler.AddVendor)
-		vendors.PUT("/:name/:channel", vendorHandler.UpdateVendorByNameAndChannel)
-		vendors.GET("/id/:id", vendorHandler.GetVendorByID) // endpoint:- /vendors/{id};
-		vendors.DELETE("/id/:id", vendorHandler.DeleteVendor)
+		communications.POST("", commHandler.SendCommunication)            // used by the SDK unless it is created WithDirectDB
+		communications.POST("/batch", commHandler.SendCommunicationBatch) // body: {"messages": [...]}
 	}
 
 	clientHandler := handlers.NewClientHandler(apiServices.NewClientService(database.DBtechRead)) // Create handler for vendors passing them database object
-	clients := r.Group("/clients")
+	r.POST("/clients/validate-client", clientHandler.ValidateClient)                              // used by the SDK; checks the client's own credentials
+
+	// Admin routes need an API key whose role grants the permission of the route
+	admin := r.Group("", middleware.AdminAuth())
+
+	read, write := middleware.RequirePermission(middleware.PermVendorsRead), middleware.RequirePermission(middleware.PermVendorsWrite)
+	vendorHandler := handlers.NewVendorHandler(apiServices.NewVendorService(database.DBtechRead)) // Create handler for vendors passing them database object
+	vendors := admin.Group("/vendors")
+	{
+		vendors.GET("/", read, vendorHandler.GetVendors) // endpoint:- /vendors; filter: ?channel=WHATSAPP
+		vendors.POST("/add-vendor", write, vendorHandler.AddVendor)
+		vendors.PUT("/:name/:channel", write, vendorHandler.UpdateVendorByNameAndChannel)
+		vendors.GET("/id/:id", read, vendorHandler.GetVendorByID) // endpoint:- /vendors/{id};
+		vendors.DELETE("/id/:id", write, vendorHandler.DeleteVendor)
+	}
+
+	vendorAccountHandler := handlers.NewVendorAccountHandler(apiServices.NewVendorAccountService(database.DBtechWrite))
+	vendorAccounts := admin.Group("/vendor-accounts")
 	{
-		clients.GET("/", clientHandler.GetClients)
-		clients.POST("/add-client", clientHandler.AddClient)
-		clients.PUT("/:name/:channel", clientHandler.UpdateClientByNameAndChannel)
-		clients.GET("/id/:id", clientHandler.GetClientByID)
-		clients.DELETE("/id/:id", clientHandler.DeleteClient)
-		clients.POST("/validate-client", clientHandler.ValidateClient)
+		vendorAccounts.GET("/", read, vendorAccountHandler.GetAccounts)                 // filter: ?client=&channel=&vendor=
+		vendorAccounts.POST("/", write, vendorAccountHandler.AddAccount)                // body: {"client": "creditsea", "channel": "SMS", "vendor": "SINCH", "appId": "...", "sender": "...", "credentials": {"username": "...", "password": "..."}}
+		vendorAccounts.PUT("/id/:id", write, vendorAccountHandler.UpdateAccount)        // credentials are only replaced when given
+		vendorAccounts.DELETE("/id/:id", write, vendorAccountHandler.DeactivateAccount) // messages fall back to the vendor's shared account
 	}
 
-	templateHandler := handlers.NewTemplateHandler(apiServices.NewTemplateService(database.DBtechRead))
-	templates := r.Group("/templates")
+	// Bodies are kept with credentials stripped and mobile numbers and email addresses masked
+	readAudit := middleware.RequirePermission(middleware.PermVendorAuditRead)
+	vendorAuditHandler := handlers.NewVendorAuditHandler(apiServices.NewVendorAuditService(database.DBtechRead))
+	admin.GET("/vendor-calls", readAudit, vendorAuditHandler.GetCalls) // endpoint:- /vendor-calls?commId=...; filter: &vendor=SINCH
+
+	// Personal data is stored encrypted; reading it back is logged with the API key
+	readPii := middleware.RequirePermission(middleware.PermPiiRead)
+	piiHandler := handlers.NewPiiHandler(apiServices.NewPiiService(database.DBtechRead))
+	admin.GET("/comm-pii", readPii, piiHandler.RevealPii) // endpoint:- /comm-pii?commId=...; filter: &channel=SMS
+
+	// After a master key rotation, the stored envelopes are re-wrapped before the old key is dropped
+	rewrapPii := middleware.RequirePermission(middleware.PermPiiRewrap)
+	piiRewrapHandler := handlers.NewPiiRewrapHandler(apiServices.NewPiiRewrapService(database.DBtechWrite))
+	admin.POST("/comm-pii/rewrap", rewrapPii, piiRewrapHandler.StartRewrap) // runs in the background
+	admin.GET("/comm-pii/rewrap", rewrapPii, piiRewrapHandler.GetRewrapStatus)
+
+	read, write = middleware.RequirePermission(middleware.PermClientsRead), middleware.RequirePermission(middleware.PermClientsWrite)
+	clients := admin.Group("/clients")
 	{
-		templates.GET("/", templateHandler.GetTemplates)
-		templates.POST("/add-template", templateHandler.AddTemplate)
-		templates.PUT("/id/:id", templateHandler.UpdateTemplateById)
-		templates.GET("/id/:id", templateHandler.GetTemplateByID)
-		templates.DELETE("/id/:id", templateHandler.DeleteTemplate)
+		clients.GET("/", read, clientHandler.GetClients)
+		clients.POST("/add-client", write, clientHandler.AddClient)
+		clients.PUT("/:name/:channel", write, clientHandler.UpdateClientByNameAndChannel)
+		clients.GET("/id/:id", read, clientHandler.GetClientByID)
+		clients.DELETE("/id/:id", write, clientHandler.DeleteClient)
+		clients.PUT("/:name/:channel/webhook", write, clientHandler.UpdateClientWebhook)
 	}
 
+	read, write = middleware.RequirePermission(middleware.PermWebhooksRead), middleware.RequirePermission(middleware.PermWebhooksWrite)
+	webhookHandler := handlers.NewWebhookHandler(webhookService.NewWebhookService(database.DBtechWrite))
+	webhooks := admin.Group("/webhooks")
+	{
+		webhooks.GET("/deliveries", read, webhookHandler.GetDeliveries) // endpoint:- /webhooks/deliveries; filter: ?commId=&client=&status=
+		webhooks.POST("/deliveries/id/:id/redeliver", write, webhookHandler.Redeliver)
+	}
+
+	// Keys scoped to a client only reach that client's templates
+	read, write = middleware.RequirePermission(middleware.PermTemplatesRead), middleware.RequirePermission(middleware.PermTemplatesWrite)
+	// Template edits, and the lock keeping one active template per key, must be on the server activation writes to;
+	// the GETs are answered from the cache
+	templateHandler := handlers.NewTemplateHandler(apiServices.NewTemplateService(database.DBtechWrite))
+	templateVersionHandler := handlers.NewTemplateVersionHandler(apiServices.NewTemplateVersionService(database.DBtechWrite))
+	ownTemplate := middleware.RequireClientScope(templateHandler.TemplateClient)
+	ownVersion := middleware.RequireClientScope(templateVersionHandler.VersionClient)
+	templates := admin.Group("/templates")
+	{
+		templates.GET("/", read, templateHandler.GetTemplates)
+		templates.POST("/add-template", write, templateHandler.AddTemplate)
+		templates.PUT("/id/:id", write, ownTemplate, templateHandler.UpdateTemplateById) // content changes are stored as a draft version
+		templates.GET("/id/:id", read, ownTemplate, templateHandler.GetTemplateByID)
+		templates.DELETE("/id/:id", write, ownTemplate, templateHandler.DeleteTemplate)
+		templates.POST("/id/:id/preview", read, ownTemplate, templateHandler.PreviewTemplate)
+
+		templates.POST("/id/:id/versions", write, ownTemplate, templateVersionHandler.CreateDraft)
+		templates.GET("/id/:id/versions", read, ownTemplate, templateVersionHandler.GetVersions)
+		templates.POST("/id/:id/rollback", write, ownTemplate, templateVersionHandler.RollbackTemplate)
+		templates.POST("/versions/id/:versionId/approve", write, ownVersion, templateVersionHandler.ApproveVersion)
+		templates.POST("/versions/id/:versionId/activate", write, ownVersion, templateVersionHandler.ActivateVersion) // body (optional): {"activateAt": "2025-10-20T10:00:00+05:30"}
+	}
+
+	manageKeys := middleware.RequirePermission(middleware.PermApiKeysManage)
+	adminKeyHandler := handlers.NewAdminKeyHandler(apiServices.NewAdminKeyService(database.DBtechWrite))
+	apiKeys := admin.Group("/admin/api-keys", manageKeys)
+	{
+		apiKeys.GET("/", adminKeyHandler.GetKeys)
+		apiKeys.POST("/", adminKeyHandler.CreateKey) // body: {"name": "...", "role": "template-editor", "client": "creditsea"}; the key is returned once
+		apiKeys.DELETE("/id/:id", adminKeyHandler.RevokeKey)
+	}
+
+	// The level changes on every pod, through the LOG_LEVEL_CHANNEL pub/sub channel; GET reports the serving pod
+	manageLogging := middleware.RequirePermission(middleware.PermLoggingManage)
+	admin.GET("/admin/log-level", manageLogging, handlers.GetLogLevel)
+	admin.PUT("/admin/log-level", manageLogging, handlers.SetLogLevel) // body: {"level": "DEBUG"}
+
+	manageCredentials := middleware.RequirePermission(middleware.PermCredentialsManage)
+	credentialService := apiServices.NewCredentialService(database.DBtechWrite)
+	credentialHandler := handlers.NewCredentialHandler(credentialService)
+	credentials := admin.Group("/credentials", manageCredentials)
+	{
+		credentials.GET("/", credentialHandler.GetCredentials)                    // filter: ?username=
+		credentials.POST("/", credentialHandler.CreateCredential)                 // body: {"username": "creditsea"}; the password is returned once
+		credentials.POST("/:username/rotate", credentialHandler.RotateCredential) // body (optional): {"graceSeconds": 86400}
+		credentials.DELETE("/id/:id", credentialHandler.RevokeCredential)
+	}
+
+	// Legacy plaintext passwords are hashed, and cleared once AUTH_CLEAR_PLAINTEXT_PASSWORDS is set; clients keep
+	// using the same password
+	go func() {
+		if err := credentialService.MigratePlaintextPasswords(); err != nil {
+			utils.Error(err)
+		}
+	}()
+
 	// if err := r.Run(":" + port); err != nil {
 	if err := r.Run("0.0.0.0:" + port); err != nil {
 		log.Fatalf("Failed to start server: %v", err)
 	}
 }
+
+// seedVendorAccounts moves the client accounts still configured through environment variables into the
+// VendorAccounts table before the first message is consumed.
+func seedVendorAccounts() {
+	seeded, err := vendorAccounts.SeedLegacyAccounts(database.DBtechWrite)
+	if err != nil {
+		utils.Error(fmt.Errorf("failed to seed vendor accounts: %v", err))
+	}
+	if seeded > 0 {
+		cache.Refresh(cache.VendorAccountsData, database.DBtechWrite)
+	}
+}
+
+// startMetricsServer serves /metrics on its own port, which is scraped inside the cluster and not published with
+// the API port, so the metrics are not readable from outside.
+func startMetricsServer(port string) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+
+	utils.Debug(fmt.Sprintf("Starting metrics server on port %s", port))
+	server := &http.Server{Addr: "0.0.0.0:" + port, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	if err := server.ListenAndServe(); err != nil {
+		utils.Error(fmt.Errorf("metrics server stopped: %v", err))
+	}
+}
+
+// bootstrapAdminKey stores ADMIN_BOOTSTRAP_API_KEY as the first ops-admin key, which creates the others.
+func bootstrapAdminKey() {
+	stored, err := apiServices.NewAdminKeyService(database.DBtechWrite).BootstrapKey(config.Configs.AdminBootstrapApiKey)
+	if err != nil {
+		utils.Error(fmt.Errorf("failed to bootstrap the admin API key: %v", err))
+		return
+	}
+	if stored {
+		utils.Warn("stored ADMIN_BOOTSTRAP_API_KEY as an ops-admin key: revoke it and unset the variable once real keys are created")
+	}
+}
+
+// startTracing installs the exporter of TRACING_EXPORTER and flushes its spans when the process is stopped.
+func startTracing() {
+	ratio, err := strconv.ParseFloat(config.Configs.TracingSampleRatio, 64)
+	if err != nil || ratio < 0 || ratio > 1 {
+		utils.Warn(fmt.Sprintf("invalid TRACING_SAMPLE_RATIO %q, sampling every trace", config.Configs.TracingSampleRatio))
+		ratio = 1
+	}
+
+	shutdown, err := tracing.Setup(context.Background(), config.Configs.TracingExporter, config.Configs.TracingServiceName, ratio)
+	if err != nil {
+		utils.Error(fmt.Errorf("failed to start tracing: %v", err))
+		return
+	}
+
+	go func() {
+		ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+		defer stop()
+		<-ctx.Done()
+
+		flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdown(flushCtx); err != nil {
+			utils.Error(fmt.Errorf("failed to flush traces: %v", err))
+		}
+	}()
+}
@@ -0,0 +1,271 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/wecredit/communication-sdk/internal/credentials"
+	"github.com/wecredit/communication-sdk/internal/database"
+	"github.com/wecredit/communication-sdk/internal/middleware"
+	apiServices "github.com/wecredit/communication-sdk/internal/services/apiServices"
+	"github.com/wecredit/communication-sdk/internal/services/webhookService"
+	"github.com/wecredit/communication-sdk/pkg/commpb"
+	"github.com/wecredit/communication-sdk/sdk/models/sdkModels"
+	sdkServices "github.com/wecredit/communication-sdk/sdk/services"
+	"github.com/wecredit/communication-sdk/sdk/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpcCredentials "google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+type grpcClientKey struct{}
+
+// commGrpcServer serves the CommunicationService with the same CommService as POST /v1/communications.
+type commGrpcServer struct {
+	commpb.UnimplementedCommunicationServiceServer
+	service *apiServices.CommService
+}
+
+// grpcShutdownTimeout bounds how long in-flight calls and status streams are given to finish on shutdown.
+const grpcShutdownTimeout = 10 * time.Second
+
+// StartGrpcServer serves the gRPC CommunicationService over TLS on port until the process is stopped. Clients send
+// basic auth credentials with every call, so the server does not start without a certificate and key.
+func StartGrpcServer(port, certFile, keyFile string) {
+	if certFile == "" || keyFile == "" {
+		utils.Error(fmt.Errorf("gRPC server not started: GRPC_TLS_CERT_FILE and GRPC_TLS_KEY_FILE are required"))
+		return
+	}
+	tlsCredentials, err := grpcCredentials.NewServerTLSFromFile(certFile, keyFile)
+	if err != nil {
+		utils.Error(fmt.Errorf("gRPC server not started: failed to load the TLS certificate: %v", err))
+		return
+	}
+
+	listener, err := net.Listen("tcp", "0.0.0.0:"+port)
+	if err != nil {
+		utils.Error(fmt.Errorf("failed to listen for gRPC on port %s: %v", port, err))
+		return
+	}
+
+	grpcServer := grpc.NewServer(
+		grpc.Creds(tlsCredentials),
+		grpc.UnaryInterceptor(unaryBasicAuth),
+		grpc.StreamInterceptor(streamBasicAuth),
+	)
+	commpb.RegisterCommunicationServiceServer(grpcServer, &commGrpcServer{
+		service: apiServices.NewCommService(database.DBtechWrite),
+	})
+
+	go func() {
+		ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+		defer stop()
+		<-ctx.Done()
+		stopGrpcServer(grpcServer)
+	}()
+
+	utils.Debug(fmt.Sprintf("Starting gRPC Server on port %s", port))
+	if err := grpcServer.Serve(listener); err != nil {
+		utils.Error(fmt.Errorf("gRPC server stopped: %v", err))
+	}
+}
+
+// stopGrpcServer lets in-flight calls finish, and closes the connections left after grpcShutdownTimeout.
+func stopGrpcServer(grpcServer *grpc.Server) {
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(grpcShutdownTimeout):
+		utils.Warn("gRPC calls still running after the shutdown timeout, closing them")
+		grpcServer.Stop()
+	}
+}
+
+func (s *commGrpcServer) Send(ctx context.Context, req *commpb.SendRequest) (*commpb.SendResponse, error) {
+	if req.GetMessage() == nil {
+		return nil, status.Error(codes.InvalidArgument, "message is required")
+	}
+
+	msg := toCommRequest(req.GetMessage())
+	response, err := s.service.Ingest(ctx, grpcClient(ctx), &msg)
+	if err != nil {
+		return nil, status.Error(grpcCode(err), err.Error())
+	}
+	return &commpb.SendResponse{CommId: response.CommId}, nil
+}
+
+func (s *commGrpcServer) SendBatch(ctx context.Context, req *commpb.SendBatchRequest) (*commpb.SendBatchResponse, error) {
+	if len(req.GetMessages()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "messages must not be empty")
+	}
+
+	msgs := make([]sdkModels.CommApiRequestBody, len(req.GetMessages()))
+	for i, message := range req.GetMessages() {
+		msgs[i] = toCommRequest(message)
+	}
+
+	results, err := s.service.IngestBatch(ctx, grpcClient(ctx), msgs)
+	if err != nil {
+		return nil, status.Error(grpcCode(err), err.Error())
+	}
+
+	response := &commpb.SendBatchResponse{Results: make([]*commpb.SendBatchResult, len(results))}
+	for i, result := range results {
+		code := codes.OK
+		if !result.Success {
+			code = grpcCode(result.Err)
+		}
+		response.Results[i] = &commpb.SendBatchResult{
+			Index:   int32(result.Index),
+			CommId:  result.CommId,
+			Success: result.Success,
+			Code:    int32(code),
+			Error:   result.Error,
+		}
+	}
+	return response, nil
+}
+
+func (s *commGrpcServer) GetStatus(ctx context.Context, req *commpb.GetStatusRequest) (*commpb.GetStatusResponse, error) {
+	if req.GetCommId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "comm_id is required")
+	}
+
+	commStatus, err := s.service.GetStatus(grpcClient(ctx), req.GetCommId(), req.GetChannel())
+	if err != nil {
+		return nil, status.Error(grpcCode(err), err.Error())
+	}
+	return &commpb.GetStatusResponse{
+		CommId:          commStatus.CommId,
+		Channel:         commStatus.Channel,
+		Status:          commStatus.Status,
+		Vendor:          commStatus.Vendor,
+		TransactionId:   commStatus.TransactionId,
+		ResponseMessage: commStatus.ResponseMessage,
+	}, nil
+}
+
+func (s *commGrpcServer) SubscribeStatus(req *commpb.SubscribeStatusRequest, stream grpc.ServerStreamingServer[commpb.StatusEvent]) error {
+	ctx := stream.Context()
+	events, err := webhookService.SubscribeStatusEvents(ctx, grpcClient(ctx), req.GetChannel())
+	if err != nil {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+
+	for event := range events {
+		if err := stream.Send(&commpb.StatusEvent{
+			Event:           event.Event,
+			CommId:          event.CommId,
+			Client:          event.Client,
+			Channel:         event.Channel,
+			ProcessName:     event.ProcessName,
+			Stage:           event.Stage,
+			Vendor:          event.Vendor,
+			TransactionId:   event.TransactionId,
+			ResponseMessage: event.ResponseMessage,
+			Timestamp:       event.Timestamp,
+		}); err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+func toCommRequest(message *commpb.Message) sdkModels.CommApiRequestBody {
+	return sdkModels.CommApiRequestBody{
+		Mobile:      message.GetMobile(),
+		Email:       message.GetEmail(),
+		Channel:     message.GetChannel(),
+		ProcessName: message.GetProcessName(),
+		Stage:       message.GetStage(),
+		IsPriority:  message.GetIsPriority(),
+		Variables:   message.GetVariables(),
+		Description: message.GetDescription(),
+		PaymentLink: message.GetPaymentLink(),
+	}
+}
+
+// grpcCode maps CommService errors to the gRPC codes matching the HTTP statuses of POST /v1/communications.
+func grpcCode(err error) codes.Code {
+	switch {
+	case errors.Is(err, sdkServices.ErrInvalidRequest), errors.Is(err, apiServices.ErrBatchTooLarge):
+		return codes.InvalidArgument
+	case errors.Is(err, sdkServices.ErrDuplicateMessage):
+		return codes.AlreadyExists
+	case errors.Is(err, sdkServices.ErrIdempotencyUnavailable):
+		return codes.Unavailable
+	case errors.Is(err, apiServices.ErrClientNotAllowed):
+		return codes.PermissionDenied
+	case errors.Is(err, apiServices.ErrCommNotFound):
+		return codes.NotFound
+	}
+	return codes.Internal
+}
+
+func grpcClient(ctx context.Context) string {
+	client, _ := ctx.Value(grpcClientKey{}).(string)
+	return client
+}
+
+// authenticate checks the basic auth credentials in the "authorization" metadata against the basic auth table.
+func authenticate(ctx context.Context) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "missing basic auth credentials")
+	}
+
+	request := http.Request{Header: http.Header{"Authorization": {values[0]}}}
+	username, password, ok := request.BasicAuth()
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "invalid username or password")
+	}
+	source := ""
+	if p, ok := peer.FromContext(ctx); ok {
+		source, _, _ = net.SplitHostPort(p.Addr.String())
+	}
+	if err := middleware.ValidCredentials(ctx, username, password, source); errors.Is(err, credentials.ErrLocked) {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	} else if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid username or password")
+	}
+	return context.WithValue(ctx, grpcClientKey{}, username), nil
+}
+
+func unaryBasicAuth(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func streamBasicAuth(srv interface{}, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := authenticate(stream.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+}
+
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
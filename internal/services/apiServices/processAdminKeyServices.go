@@ -0,0 +1,126 @@
+package apiServices
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/wecredit/communication-sdk/config"
+	"github.com/wecredit/communication-sdk/internal/credentials"
+	"github.com/wecredit/communication-sdk/internal/middleware"
+	"github.com/wecredit/communication-sdk/internal/models/apiModels"
+	"github.com/wecredit/communication-sdk/pkg/cache"
+	"github.com/wecredit/communication-sdk/sdk/utils"
+	"github.com/wecredit/communication-sdk/sdk/variables"
+	"gorm.io/gorm"
+)
+
+type AdminKeyService struct {
+	DB *gorm.DB
+}
+
+func NewAdminKeyService(db *gorm.DB) *AdminKeyService {
+	return &AdminKeyService{DB: db}
+}
+
+func (s *AdminKeyService) keys() *gorm.DB {
+	return s.DB.Table(config.Configs.AdminApiKeyTable)
+}
+
+// GetKeys returns every admin API key, revoked ones included. Key hashes are never serialised.
+func (s *AdminKeyService) GetKeys() ([]apiModels.AdminApiKey, error) {
+	var keys []apiModels.AdminApiKey
+	if err := s.keys().Order("Id").Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// CreateKey stores a new admin API key and returns the key itself, which is not kept and cannot be shown again.
+func (s *AdminKeyService) CreateKey(key *apiModels.AdminApiKey) (string, error) {
+	key.Role = strings.ToLower(strings.TrimSpace(key.Role))
+	key.Client = strings.ToLower(strings.TrimSpace(key.Client))
+
+	switch key.Role {
+	case variables.RoleViewer, variables.RoleTemplateEditor:
+	case variables.RoleOpsAdmin:
+		if key.Client != "" {
+			return "", errors.New("an ops-admin key cannot be scoped to a client")
+		}
+	default:
+		return "", fmt.Errorf("invalid role %q: expected %s, %s or %s", key.Role, variables.RoleViewer, variables.RoleTemplateEditor, variables.RoleOpsAdmin)
+	}
+
+	apiKey, err := credentials.NewSecret()
+	if err != nil {
+		return "", err
+	}
+
+	key.Id = 0
+	key.KeyHash = middleware.HashApiKey(apiKey)
+	key.Status = int(variables.Active)
+	key.CreatedOn = utils.IstNow()
+	key.UpdatedOn = nil
+
+	if err := s.keys().Create(key).Error; err != nil {
+		return "", err
+	}
+	cache.Refresh(cache.AdminKeysData, s.DB)
+	return apiKey, nil
+}
+
+// RevokeKey deactivates the admin API key with the given id.
+func (s *AdminKeyService) RevokeKey(id int) error {
+	now := utils.IstNow()
+	result := s.keys().Where("Id = ?", id).Updates(map[string]interface{}{
+		"Status":    0,
+		"UpdatedOn": now,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	cache.Refresh(cache.AdminKeysData, s.DB)
+	return nil
+}
+
+// minBootstrapKeyLength is the shortest ADMIN_BOOTSTRAP_API_KEY accepted, the length of the keys CreateKey returns.
+const minBootstrapKeyLength = 64
+
+// BootstrapKey stores apiKey as an ops-admin key while the table has no active ops-admin key, so the first keys
+// can be created through /admin/api-keys. It reports whether the key was stored; once real keys exist, the
+// bootstrap key should be revoked and its variable unset.
+func (s *AdminKeyService) BootstrapKey(apiKey string) (bool, error) {
+	apiKey = strings.TrimSpace(apiKey)
+	if apiKey == "" {
+		return false, nil
+	}
+	if len(apiKey) < minBootstrapKeyLength {
+		return false, fmt.Errorf("ADMIN_BOOTSTRAP_API_KEY must be at least %d characters", minBootstrapKeyLength)
+	}
+
+	var existing int64
+	err := s.keys().Where("(Role = ? AND Status = ?) OR KeyHash = ?", variables.RoleOpsAdmin, variables.Active, middleware.HashApiKey(apiKey)).
+		Count(&existing).Error
+	if err != nil {
+		return false, err
+	}
+	if existing > 0 {
+		return false, nil
+	}
+
+	key := apiModels.AdminApiKey{
+		Name:      "bootstrap",
+		KeyHash:   middleware.HashApiKey(apiKey),
+		Role:      variables.RoleOpsAdmin,
+		Status:    int(variables.Active),
+		CreatedOn: utils.IstNow(),
+	}
+	if err := s.keys().Create(&key).Error; err != nil {
+		return false, err
+	}
+	cache.Refresh(cache.AdminKeysData, s.DB)
+	return true, nil
+}
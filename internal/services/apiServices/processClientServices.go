@@ -1,15 +1,21 @@
 package apiServices
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net/url"
 	"strings"
 	"time"
 
 	"github.com/wecredit/communication-sdk/config"
+	"github.com/wecredit/communication-sdk/internal/channels/channelHelper"
+	"github.com/wecredit/communication-sdk/internal/credentials"
 	"github.com/wecredit/communication-sdk/internal/models/apiModels"
+	"github.com/wecredit/communication-sdk/internal/services/webhookService"
 	"github.com/wecredit/communication-sdk/pkg/cache"
 	"github.com/wecredit/communication-sdk/sdk/utils"
+	"github.com/wecredit/communication-sdk/sdk/variables"
 	"gorm.io/gorm"
 )
 
@@ -22,8 +28,8 @@ func NewClientService(db *gorm.DB) *ClientService {
 }
 
 func (s *ClientService) GetClients(channel, name string) ([]apiModels.Client, error) {
-	clientDetails, found := cache.GetCache().GetMappedData(cache.ClientsData)
-	if !found {
+	snapshot := cache.Current()
+	if !snapshot.Loaded(cache.ClientsData) {
 		utils.Error(fmt.Errorf("client data not found in cache"))
 		return nil, errors.New("client data not found in cache")
 	}
@@ -32,64 +38,36 @@ func (s *ClientService) GetClients(channel, name string) ([]apiModels.Client, er
 
 	// Case 1: Both name and channel provided -> direct key lookup
 	if name != "" && channel != "" {
-		key := fmt.Sprintf("Name:%s|Channel:%s", name, channel)
-		if data, ok := clientDetails[key]; ok {
-			client, err := mapToClient(data)
-			if err != nil {
-				utils.Error(fmt.Errorf("failed to convert cache data to client: %v", err))
-				return nil, err
-			}
-			return []apiModels.Client{*client}, nil
+		if client, ok := snapshot.Client(name, channel); ok {
+			return []apiModels.Client{client}, nil
 		}
 		return nil, nil // No match
 	}
 
 	// Case 2: Filtering loop
-	for _, data := range clientDetails {
-		if (channel != "" && data["Channel"] != channel) || (name != "" && data["Name"] != name) {
+	for _, client := range snapshot.Clients {
+		if (channel != "" && client.Channel != channel) || (name != "" && client.Name != name) {
 			continue
 		}
-		client, err := mapToClient(data)
-		if err != nil {
-			utils.Error(fmt.Errorf("skipping invalid client data: %v", err))
-			continue
-		}
-		clients = append(clients, *client)
+		clients = append(clients, client)
 	}
 
 	return clients, nil
 }
 
 func (s *ClientService) GetClientByID(id uint) (*apiModels.Client, error) {
-	idIndex, found := cache.GetCache().GetMappedIdData(cache.ClientsData + ":IdIndex")
-	if !found {
-		utils.Error(fmt.Errorf("client Id index not found in cache"))
-		return nil, errors.New("client Id index not found in cache")
-	}
-
-	key, ok := idIndex[id]
-	if !ok {
-		return nil, errors.New("client not found")
-	}
-
-	clientDetails, found := cache.GetCache().GetMappedData(cache.ClientsData)
-	if !found {
+	snapshot := cache.Current()
+	if !snapshot.Loaded(cache.ClientsData) {
 		utils.Error(fmt.Errorf("client data not found in cache"))
 		return nil, errors.New("client data not found in cache")
 	}
 
-	data, ok := clientDetails[key]
+	client, ok := snapshot.ClientById(id)
 	if !ok {
 		return nil, errors.New("client not found")
 	}
 
-	client, err := mapToClient(data)
-	if err != nil {
-		utils.Error(fmt.Errorf("failed to convert cache data to client: %v", err))
-		return nil, err
-	}
-
-	return client, nil
+	return &client, nil
 }
 
 func (s *ClientService) AddClient(client *apiModels.Client) error {
@@ -109,6 +87,10 @@ func (s *ClientService) AddClient(client *apiModels.Client) error {
 	client.Name = strings.ToLower(client.Name)
 	client.Channel = strings.ToUpper(client.Channel)
 
+	if err := normalizeFallbackPolicy(&client.TemplateFallbackPolicy); err != nil {
+		return err
+	}
+
 	client.Status = 1
 	istOffset := 5*time.Hour + 30*time.Minute
 	client.CreatedOn = time.Now().UTC().Add(istOffset)
@@ -117,7 +99,7 @@ func (s *ClientService) AddClient(client *apiModels.Client) error {
 	if err != nil {
 		return err
 	}
-	cache.StoreMappedDataIntoCache(cache.ClientsData, config.Configs.ClientsTable, "Name", "Channel", s.DB)
+	cache.Refresh(cache.ClientsData, s.DB)
 	return nil
 }
 
@@ -131,6 +113,61 @@ func (s *ClientService) UpdateClientByNameAndChannel(name, channel string, updat
 	// existing.Channel = strings.ToUpper(existing.Channel)
 	existing.Status = updates.Status
 	existing.RateLimitPerMinute = updates.RateLimitPerMinute
+	existing.DailyLimit = updates.DailyLimit
+	if updates.TemplateFallbackPolicy != "" {
+		if err := normalizeFallbackPolicy(&updates.TemplateFallbackPolicy); err != nil {
+			return err
+		}
+		existing.TemplateFallbackPolicy = updates.TemplateFallbackPolicy
+	}
+	istOffset := 5*time.Hour + 30*time.Minute
+	now := time.Now().UTC().Add(istOffset)
+	existing.UpdatedOn = &now
+
+	err := s.DB.Save(&existing).Error
+	if err != nil {
+		return err
+	}
+	cache.Refresh(cache.ClientsData, s.DB)
+	return nil
+}
+
+func (s *ClientService) UpdateClientWebhook(name, channel string, webhook apiModels.ClientWebhook) error {
+	var existing apiModels.Client
+	if err := s.DB.Where("name = ? AND channel = ?", name, channel).First(&existing).Error; err != nil {
+		return errors.New("client not found")
+	}
+
+	webhook.WebhookUrl = strings.TrimSpace(webhook.WebhookUrl)
+	if webhook.WebhookUrl != "" {
+		parsed, err := url.Parse(webhook.WebhookUrl)
+		if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+			return errors.New("webhookUrl must be a valid http(s) url")
+		}
+		if webhook.WebhookSecret == "" && existing.WebhookSecret == "" && existing.WebhookSecretEncrypted == "" {
+			return errors.New("webhookSecret is required to sign webhook events")
+		}
+	}
+
+	var events []string
+	for _, event := range strings.Split(webhook.WebhookEvents, ",") {
+		event = strings.ToUpper(strings.TrimSpace(event))
+		if event == "" {
+			continue
+		}
+		if event != variables.WebhookEventSent && event != variables.WebhookEventFailed {
+			return fmt.Errorf("unsupported webhook event: %s", event)
+		}
+		events = append(events, event)
+	}
+
+	existing.WebhookUrl = webhook.WebhookUrl
+	existing.WebhookEvents = strings.Join(events, ",")
+	if webhook.WebhookSecret != "" {
+		if err := webhookService.SealSecret(&existing, webhook.WebhookSecret); err != nil {
+			return err
+		}
+	}
 	istOffset := 5*time.Hour + 30*time.Minute
 	now := time.Now().UTC().Add(istOffset)
 	existing.UpdatedOn = &now
@@ -139,7 +176,7 @@ func (s *ClientService) UpdateClientByNameAndChannel(name, channel string, updat
 	if err != nil {
 		return err
 	}
-	cache.StoreMappedDataIntoCache(cache.ClientsData, config.Configs.ClientsTable, "Name", "Channel", s.DB)
+	cache.Refresh(cache.ClientsData, s.DB)
 	return nil
 }
 
@@ -151,45 +188,33 @@ func (s *ClientService) DeleteClient(id int) error {
 	if result.RowsAffected == 0 {
 		return gorm.ErrRecordNotFound
 	}
-	cache.StoreMappedDataIntoCache(cache.ClientsData, config.Configs.ClientsTable, "Name", "Channel", s.DB)
+	cache.Refresh(cache.ClientsData, s.DB)
 	return nil
 }
 
-func (s *ClientService) ValidateCredentials(username, password, channel string) (string, string, string, string, error) {
+// ErrCredentialsLocked is returned while a username is locked out from a source after repeated failed credential checks.
+var ErrCredentialsLocked = credentials.ErrLocked
+
+// ValidateCredentials checks the credentials a request from source, its client IP, presented for channel.
+func (s *ClientService) ValidateCredentials(username, password, channel, source string) (string, string, string, string, error) {
 	// Collecting BasicAuthData
-	authDetails, _ := cache.GetCache().Get("authDetails")
+	snapshot := cache.Current()
 
 	username = strings.ToLower(username)
 	channel = strings.ToUpper(channel)
 
 	// Validate the credentials
-	isValid := false
-	for _, data := range authDetails {
-		// extract username and password from the map
-		usernameFromData, _ := data["username"].(string)
-		passwordFromData, _ := data["password"].(string)
-
-		// Validate headers username and password
-		if usernameFromData == username && passwordFromData == password {
-			isValid = true
-			break
-		}
-
-	}
-
-	if !isValid {
-		return "", "", "", "", errors.New("invalid Username and Password")
+	if err := credentials.Authenticate(context.Background(), username, password, source); err != nil {
+		return "", "", "", "", err
 	}
 
-	clientDetails, found := cache.GetCache().GetMappedData(cache.ClientsData)
-	if !found {
+	if !snapshot.Loaded(cache.ClientsData) {
 		utils.Error(fmt.Errorf("client data not found in cache"))
 		return "", "", "", "", errors.New("client not found for particular channel")
 	}
 
 	// Case 1: Both name and channel provided -> direct key lookup
-	key := fmt.Sprintf("Name:%s|Channel:%s", username, channel)
-	if client, exists := clientDetails[key]; !exists || client["Status"].(int64) != 1 {
+	if client, exists := snapshot.Client(username, channel); !exists || client.Status != 1 {
 		utils.Error(fmt.Errorf("client not found for channel %s and username %s", channel, username))
 		return "", "", "", "", errors.New("client not found for particular channel")
 	}
@@ -200,34 +225,16 @@ func (s *ClientService) ValidateCredentials(username, password, channel string)
 	return username, channel, topicArn, redisAddress, nil
 }
 
-// Helper function to convert map to Client struct
-func mapToClient(data map[string]interface{}) (*apiModels.Client, error) {
-	client := &apiModels.Client{
-		Id:                 int(data["Id"].(int64)),
-		Name:               data["Name"].(string),
-		Channel:            data["Channel"].(string),
-		Status:             int(data["Status"].(int64)),
-		RateLimitPerMinute: int(data["RateLimitPerMinute"].(int64)),
-	}
-
-	if createdOn, ok := data["CreatedOn"].(time.Time); ok {
-		client.CreatedOn = createdOn
-	}
-
-	if updatedOn, ok := data["UpdatedOn"]; ok && updatedOn != nil {
-		switch v := updatedOn.(type) {
-		case time.Time:
-			client.UpdatedOn = &v
-		case string:
-			parsed, err := time.Parse("2006-01-02 15:04:05.999 +0000 UTC", v)
-			if err != nil {
-				return nil, fmt.Errorf("failed to parse UpdatedOn string: %v", err)
-			}
-			client.UpdatedOn = &parsed
-		default:
-			return nil, fmt.Errorf("unsupported type for UpdatedOn: %T", updatedOn)
-		}
+// normalizeFallbackPolicy validates a TemplateFallbackPolicy and rewrites it in canonical form.
+func normalizeFallbackPolicy(policy *string) error {
+	if strings.TrimSpace(*policy) == "" {
+		*policy = ""
+		return nil
 	}
-
-	return client, nil
+	steps, err := channelHelper.ParseFallbackPolicy(*policy)
+	if err != nil {
+		return err
+	}
+	*policy = strings.Join(steps, ",")
+	return nil
 }
@@ -0,0 +1,213 @@
+package apiServices
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/wecredit/communication-sdk/config"
+	"github.com/wecredit/communication-sdk/internal/redis"
+	"github.com/wecredit/communication-sdk/pkg/cache"
+	sdkHelper "github.com/wecredit/communication-sdk/sdk/helper"
+	"github.com/wecredit/communication-sdk/sdk/models/sdkModels"
+	"github.com/wecredit/communication-sdk/sdk/queue"
+	sdkServices "github.com/wecredit/communication-sdk/sdk/services"
+	"github.com/wecredit/communication-sdk/sdk/utils"
+	"github.com/wecredit/communication-sdk/sdk/variables"
+	"gorm.io/gorm"
+)
+
+// CommService ingests messages sent through the API, so SDK clients need only their credentials
+// and never a connection to the input tables.
+type CommService struct {
+	DB *gorm.DB
+}
+
+func NewCommService(db *gorm.DB) *CommService {
+	return &CommService{DB: db}
+}
+
+var (
+	// ErrClientNotAllowed is returned when the client is not active on the message channel.
+	ErrClientNotAllowed = errors.New("client is not enabled for this channel")
+	// ErrBatchTooLarge is returned when a batch holds more messages than COMM_BATCH_MAX_SIZE.
+	ErrBatchTooLarge = errors.New("batch is too large")
+	// ErrCommNotFound is returned when the client has sent no message with the CommId.
+	ErrCommNotFound = errors.New("communication not found")
+)
+
+// CommStatus is the delivery status of one message.
+type CommStatus struct {
+	CommId          string `json:"commId"`
+	Channel         string `json:"channel"`
+	Status          string `json:"status"` // QUEUED, SENT or FAILED
+	Vendor          string `json:"vendor,omitempty"`
+	TransactionId   string `json:"transactionId,omitempty"`
+	ResponseMessage string `json:"responseMessage,omitempty"`
+}
+
+// Ingest writes the message to the input table of its channel and publishes it to SNS on behalf of client.
+// The publish continues the trace of ctx.
+func (s *CommService) Ingest(ctx context.Context, client string, msg *sdkModels.CommApiRequestBody) (sdkModels.CommApiResponseBody, error) {
+	msg.Channel = strings.ToUpper(msg.Channel)
+	msg.ProcessName = strings.ToUpper(msg.ProcessName)
+	msg.Description = strings.ToUpper(msg.Description)
+	msg.Client = strings.ToLower(client)
+
+	// Validated here as well so an unknown channel is reported as invalid rather than as not enabled
+	if isValid, message := sdkHelper.ValidateCommRequest(*msg); !isValid {
+		return sdkModels.CommApiResponseBody{Success: false}, fmt.Errorf("%w: %s", sdkServices.ErrInvalidRequest, message)
+	}
+	if clientRow, ok := cache.Current().Client(msg.Client, msg.Channel); !ok || int64(clientRow.Status) != variables.Active {
+		return sdkModels.CommApiResponseBody{Success: false}, fmt.Errorf("%w: client %s, channel %s", ErrClientNotAllowed, msg.Client, msg.Channel)
+	}
+
+	inputTable := inputTableForChannel(msg.Channel)
+	if inputTable == "" {
+		return sdkModels.CommApiResponseBody{Success: false}, fmt.Errorf("no input table configured for channel %s", msg.Channel)
+	}
+	msg.DbClient = s.DB
+	msg.InputTableName = inputTable
+
+	response, err := sdkServices.ProcessCommApiData(ctx, msg, queue.SNSClient, config.Configs.AwsSnsArn, redis.RDB)
+	if err != nil {
+		utils.Error(fmt.Errorf("error in ingesting message for client %s, mobile %s and channel %s for stage %f: %v", msg.Client, msg.Mobile, msg.Channel, msg.Stage, err))
+		return sdkModels.CommApiResponseBody{Success: false}, err
+	}
+	return response, nil
+}
+
+// IngestBatch ingests every message independently; the result of each message is at its index.
+func (s *CommService) IngestBatch(ctx context.Context, client string, msgs []sdkModels.CommApiRequestBody) ([]sdkModels.CommBatchResult, error) {
+	if maxSize := s.MaxBatchSize(); len(msgs) > maxSize {
+		return nil, fmt.Errorf("%w: %d messages, at most %d allowed", ErrBatchTooLarge, len(msgs), maxSize)
+	}
+
+	results := make([]sdkModels.CommBatchResult, len(msgs))
+	for i := range msgs {
+		results[i].Index = i
+		response, err := s.Ingest(ctx, client, &msgs[i])
+		if err != nil {
+			results[i].Error = err.Error()
+			results[i].Err = err
+			continue
+		}
+		results[i].CommId = response.CommId
+		results[i].Success = true
+	}
+	return results, nil
+}
+
+// MaxBatchSize returns the configured maximum number of messages in one batch.
+func (s *CommService) MaxBatchSize() int {
+	size, err := strconv.Atoi(config.Configs.CommBatchMaxSize)
+	if err != nil || size <= 0 {
+		return 500
+	}
+	return size
+}
+
+// GetStatus looks the message up in the input and output tables of its channel, or of every channel when channel is empty.
+// Only messages sent by client are found.
+func (s *CommService) GetStatus(client, commId, channel string) (CommStatus, error) {
+	channels := []string{variables.SMS, variables.WhatsApp, variables.Email, variables.RCS}
+	if channel != "" {
+		channels = []string{strings.ToUpper(channel)}
+	}
+
+	for _, ch := range channels {
+		inputTable := inputTableForChannel(ch)
+		if inputTable == "" {
+			continue
+		}
+
+		var accepted int64
+		if err := s.DB.Table(inputTable).Where("CommId = ? AND Client = ?", commId, strings.ToLower(client)).Count(&accepted).Error; err != nil {
+			return CommStatus{}, fmt.Errorf("failed to look up CommId %s in %s: %w", commId, inputTable, err)
+		}
+		if accepted == 0 {
+			continue
+		}
+
+		status := CommStatus{CommId: commId, Channel: ch, Status: variables.CommQueued}
+		outputTable := outputTableForChannel(ch)
+		if outputTable == "" {
+			return status, nil
+		}
+
+		var rows []map[string]interface{}
+		if err := s.DB.Table(outputTable).Where("CommId = ?", commId).Limit(1).Find(&rows).Error; err != nil {
+			return CommStatus{}, fmt.Errorf("failed to look up CommId %s in %s: %w", commId, outputTable, err)
+		}
+		if len(rows) == 0 {
+			return status, nil
+		}
+
+		status.Status = variables.CommFailed
+		if isTrue(rows[0]["IsSent"]) {
+			status.Status = variables.CommSent
+		}
+		status.Vendor = stringValue(rows[0]["Vendor"])
+		status.TransactionId = stringValue(rows[0]["TransactionId"])
+		status.ResponseMessage = stringValue(rows[0]["ResponseMessage"])
+		return status, nil
+	}
+	return CommStatus{}, fmt.Errorf("%w: %s", ErrCommNotFound, commId)
+}
+
+func outputTableForChannel(channel string) string {
+	switch channel {
+	case variables.SMS:
+		return config.Configs.SmsOutputTable
+	case variables.WhatsApp:
+		return config.Configs.WhatsappOutputTable
+	case variables.Email:
+		return config.Configs.EmailOutputTable
+	case variables.RCS:
+		return config.Configs.RcsOutputTable
+	}
+	return ""
+}
+
+// isTrue reads a MySQL boolean, which the driver returns as an integer or as bytes depending on the column type.
+func isTrue(value interface{}) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case int64:
+		return v == 1
+	case []byte:
+		return len(v) == 1 && (v[0] == 1 || v[0] == '1')
+	case string:
+		return v == "1" || strings.EqualFold(v, "true")
+	}
+	return false
+}
+
+func stringValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case nil:
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+func inputTableForChannel(channel string) string {
+	switch channel {
+	case variables.SMS:
+		return config.Configs.SdkSmsInputTable
+	case variables.WhatsApp:
+		return config.Configs.SdkWhatsappInputTable
+	case variables.Email:
+		return config.Configs.SdkEmailInputTable
+	case variables.RCS:
+		return config.Configs.SdkRcsInputTable
+	}
+	return ""
+}
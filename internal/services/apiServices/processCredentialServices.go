@@ -0,0 +1,231 @@
+package apiServices
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wecredit/communication-sdk/config"
+	"github.com/wecredit/communication-sdk/internal/credentials"
+	"github.com/wecredit/communication-sdk/internal/models/apiModels"
+	"github.com/wecredit/communication-sdk/pkg/cache"
+	"github.com/wecredit/communication-sdk/sdk/utils"
+	"gorm.io/gorm"
+)
+
+// CredentialService manages the client secrets of the basic auth table.
+type CredentialService struct {
+	DB *gorm.DB
+}
+
+func NewCredentialService(db *gorm.DB) *CredentialService {
+	return &CredentialService{DB: db}
+}
+
+func (s *CredentialService) credentials() *gorm.DB {
+	return s.DB.Table(config.Configs.BasicAuthTableName)
+}
+
+// GetCredentials returns every secret of username, or of every client when username is empty. Secrets are never returned.
+func (s *CredentialService) GetCredentials(username string) ([]apiModels.Userbasicauth, error) {
+	query := s.credentials().Order("Id")
+	if username != "" {
+		query = query.Where("username = ?", strings.ToLower(username))
+	}
+
+	var rows []apiModels.Userbasicauth
+	if err := query.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		rows[i].Password = ""
+	}
+	return rows, nil
+}
+
+// CreateCredential issues the first secret of a client and returns it; only its hash is stored.
+func (s *CredentialService) CreateCredential(username string) (string, *apiModels.Userbasicauth, error) {
+	username = strings.ToLower(strings.TrimSpace(username))
+	if username == "" {
+		return "", nil, errors.New("username is required")
+	}
+
+	active, err := s.activeCount(s.DB, username)
+	if err != nil {
+		return "", nil, err
+	}
+	if active > 0 {
+		return "", nil, fmt.Errorf("client %s already has an active secret, rotate it instead", username)
+	}
+
+	var secret string
+	var row *apiModels.Userbasicauth
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		secret, row, err = s.insertSecret(tx, username)
+		return err
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	cache.Refresh(cache.AuthDetails, s.DB)
+	return secret, row, nil
+}
+
+// RotateCredential issues a new secret for a client. Its current secrets keep working for the grace period,
+// so callers can roll the new one out before the old ones expire.
+func (s *CredentialService) RotateCredential(username string, grace time.Duration) (string, *apiModels.Userbasicauth, error) {
+	username = strings.ToLower(strings.TrimSpace(username))
+	if grace < 0 {
+		return "", nil, errors.New("grace period cannot be negative")
+	}
+
+	active, err := s.activeCount(s.DB, username)
+	if err != nil {
+		return "", nil, err
+	}
+	if active == 0 {
+		return "", nil, gorm.ErrRecordNotFound
+	}
+
+	now := utils.IstNow()
+	expiresOn := now.Add(grace)
+
+	var secret string
+	var row *apiModels.Userbasicauth
+	err = s.DB.Transaction(func(tx *gorm.DB) error {
+		// A secret already due to expire sooner keeps its earlier expiry
+		err := tx.Table(config.Configs.BasicAuthTableName).
+			Where("username = ? AND revokedOn IS NULL AND (expiresOn IS NULL OR expiresOn > ?)", username, expiresOn).
+			Updates(map[string]interface{}{
+				"expiresOn": expiresOn,
+				"updatedOn": now,
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		secret, row, err = s.insertSecret(tx, username)
+		return err
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	cache.Refresh(cache.AuthDetails, s.DB)
+	return secret, row, nil
+}
+
+// RevokeCredential stops the secret with the given id from authenticating immediately.
+func (s *CredentialService) RevokeCredential(id int) error {
+	now := utils.IstNow()
+	result := s.credentials().Where("Id = ? AND revokedOn IS NULL", id).Updates(map[string]interface{}{
+		"revokedOn": now,
+		"updatedOn": now,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	cache.Refresh(cache.AuthDetails, s.DB)
+	return nil
+}
+
+// RotationGrace returns the configured grace period of rotated secrets.
+func (s *CredentialService) RotationGrace() time.Duration {
+	seconds, err := strconv.Atoi(config.Configs.CredentialRotationGraceSeconds)
+	if err != nil || seconds < 0 {
+		seconds = 86400
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// MigratePlaintextPasswords stores the bcrypt hash of every legacy row still holding only its plaintext password.
+// Clients keep using the same password; only how it is checked changes. The plaintext stays in place for pods that
+// predate the hashes, until AUTH_CLEAR_PLAINTEXT_PASSWORDS is set once every pod has rolled out.
+func (s *CredentialService) MigratePlaintextPasswords() error {
+	var rows []apiModels.Userbasicauth
+	err := s.credentials().
+		Where("(passwordHash IS NULL OR passwordHash = '') AND password IS NOT NULL AND password <> ''").
+		Find(&rows).Error
+	if err != nil {
+		return fmt.Errorf("failed to read plaintext credentials: %w", err)
+	}
+
+	migrated := 0
+	for _, row := range rows {
+		hash, err := credentials.Hash(row.Password)
+		if err != nil {
+			utils.Error(fmt.Errorf("failed to hash credential %d of %s: %v", row.Id, row.Username, err))
+			continue
+		}
+		// Only a row still holding the plaintext that was hashed, and no hash yet, is updated
+		result := s.credentials().
+			Where("Id = ? AND password = ? AND (passwordHash IS NULL OR passwordHash = '')", row.Id, row.Password).
+			Updates(map[string]interface{}{
+				"passwordHash": hash,
+				"updatedOn":    utils.IstNow(),
+			})
+		if result.Error != nil {
+			utils.Error(fmt.Errorf("failed to migrate credential %d of %s: %v", row.Id, row.Username, result.Error))
+			continue
+		}
+		migrated += int(result.RowsAffected)
+	}
+	if len(rows) > 0 {
+		utils.Info(fmt.Sprintf("Hashed %d of %d plaintext credentials with bcrypt", migrated, len(rows)))
+	}
+
+	cleared := int64(0)
+	if clear, _ := strconv.ParseBool(config.Configs.AuthClearPlaintextPasswords); clear {
+		result := s.credentials().
+			Where("passwordHash IS NOT NULL AND passwordHash <> '' AND password IS NOT NULL AND password <> ''").
+			Updates(map[string]interface{}{
+				"password":  "",
+				"updatedOn": utils.IstNow(),
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to clear plaintext credentials: %w", result.Error)
+		}
+		cleared = result.RowsAffected
+		if cleared > 0 {
+			utils.Info(fmt.Sprintf("Cleared the plaintext password of %d hashed credentials", cleared))
+		}
+	}
+
+	if migrated > 0 || cleared > 0 {
+		cache.Refresh(cache.AuthDetails, s.DB)
+	}
+	return nil
+}
+
+func (s *CredentialService) activeCount(db *gorm.DB, username string) (int64, error) {
+	var count int64
+	err := db.Table(config.Configs.BasicAuthTableName).
+		Where("username = ? AND revokedOn IS NULL AND (expiresOn IS NULL OR expiresOn > ?)", username, utils.IstNow()).
+		Count(&count).Error
+	return count, err
+}
+
+func (s *CredentialService) insertSecret(tx *gorm.DB, username string) (string, *apiModels.Userbasicauth, error) {
+	secret, err := credentials.NewSecret()
+	if err != nil {
+		return "", nil, err
+	}
+	hash, err := credentials.Hash(secret)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to hash secret: %w", err)
+	}
+
+	row := &apiModels.Userbasicauth{
+		Username:     username,
+		PasswordHash: hash,
+		CreatedOn:    utils.IstNow(),
+	}
+	if err := tx.Table(config.Configs.BasicAuthTableName).Create(row).Error; err != nil {
+		return "", nil, err
+	}
+	return secret, row, nil
+}
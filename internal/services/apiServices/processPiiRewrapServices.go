@@ -0,0 +1,163 @@
+package apiServices
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/wecredit/communication-sdk/internal/pii"
+	"github.com/wecredit/communication-sdk/sdk/utils"
+	"github.com/wecredit/communication-sdk/sdk/variables"
+	"gorm.io/gorm"
+)
+
+// rewrapBatchSize is the number of rows read and re-wrapped at a time.
+const rewrapBatchSize = 500
+
+// ErrRewrapRunning is returned when a re-wrap is started while this pod is already running one.
+var ErrRewrapRunning = errors.New("a re-wrap is already running")
+
+// PiiRewrapStatus reports the last re-wrap of the personal data envelopes run by this pod.
+type PiiRewrapStatus struct {
+	Running    bool       `json:"running"`
+	StartedOn  *time.Time `json:"startedOn,omitempty"`
+	FinishedOn *time.Time `json:"finishedOn,omitempty"`
+	Rewrapped  int        `json:"rewrapped"`
+	Failed     int        `json:"failed"`
+	LastError  string     `json:"lastError,omitempty"`
+}
+
+// PiiRewrapService re-wraps the envelopes of the <column>Encrypted columns with the current master key after a
+// rotation, so the previous key can be dropped from SECRETS_MASTER_KEYS. The data keys, and so the ciphertexts,
+// stay as they are.
+type PiiRewrapService struct {
+	DB *gorm.DB
+
+	mu     sync.Mutex
+	status PiiRewrapStatus
+}
+
+func NewPiiRewrapService(db *gorm.DB) *PiiRewrapService {
+	return &PiiRewrapService{DB: db}
+}
+
+// encryptedColumns returns the envelope columns of every input and output table.
+func encryptedColumns() map[string][]string {
+	columns := map[string][]string{}
+	add := func(table, column string) {
+		if table != "" {
+			columns[table] = append(columns[table], column+pii.EncryptedSuffix)
+		}
+	}
+	for _, channel := range []string{variables.SMS, variables.WhatsApp, variables.Email, variables.RCS} {
+		add(inputTableForChannel(channel), "Mobile")
+	}
+	add(outputTableForChannel(variables.SMS), "MobileNumber")
+	add(outputTableForChannel(variables.WhatsApp), "MobileNumber")
+	add(outputTableForChannel(variables.Email), "Email")
+	return columns
+}
+
+// Start runs a re-wrap in the background; its progress is reported by Status.
+func (s *PiiRewrapService) Start() error {
+	prefix, err := pii.CurrentPrefix()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.status.Running {
+		return ErrRewrapRunning
+	}
+	now := utils.IstNow()
+	s.status = PiiRewrapStatus{Running: true, StartedOn: &now}
+
+	go s.run(prefix)
+	return nil
+}
+
+// Status returns the progress of the running or last re-wrap.
+func (s *PiiRewrapService) Status() PiiRewrapStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.status
+}
+
+func (s *PiiRewrapService) run(currentPrefix string) {
+	var runErr error
+	for table, columns := range encryptedColumns() {
+		for _, column := range columns {
+			if err := s.rewrapColumn(table, column, currentPrefix); err != nil {
+				utils.Error(fmt.Errorf("failed to re-wrap %s.%s: %v", table, column, err))
+				runErr = err
+			}
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := utils.IstNow()
+	s.status.Running = false
+	s.status.FinishedOn = &now
+	if runErr != nil {
+		s.status.LastError = runErr.Error()
+	}
+	utils.Info(fmt.Sprintf("Re-wrapped %d personal data envelopes, %d failed", s.status.Rewrapped, s.status.Failed))
+}
+
+// rewrapColumn re-wraps the envelopes of column not sealed under the current master key, a batch at a time.
+// A row is only updated while it holds the envelope that was read, so a concurrent write is never overwritten.
+func (s *PiiRewrapService) rewrapColumn(table, column, currentPrefix string) error {
+	failed := map[string]bool{}
+	for {
+		var rows []struct {
+			CommId string
+			Value  string
+		}
+		err := s.DB.Table(table).
+			Select(fmt.Sprintf("CommId, %s AS Value", column)).
+			Where(fmt.Sprintf("%s LIKE ? AND %s NOT LIKE ?", column, column), pii.Prefix+"%", currentPrefix+"%").
+			Limit(rewrapBatchSize).
+			Scan(&rows).Error
+		if err != nil {
+			return err
+		}
+
+		progressed := false
+		for _, row := range rows {
+			if failed[row.Value] {
+				continue
+			}
+			rewrapped, changed, err := pii.Rewrap(row.Value)
+			if err != nil || !changed {
+				if err != nil {
+					utils.Error(fmt.Errorf("failed to re-wrap %s of CommId %s: %v", column, row.CommId, err))
+				}
+				failed[row.Value] = true
+				s.count(0, 1)
+				continue
+			}
+			result := s.DB.Table(table).Where(fmt.Sprintf("CommId = ? AND %s = ?", column), row.CommId, row.Value).
+				Update(column, rewrapped)
+			if result.Error != nil {
+				return result.Error
+			}
+			progressed = true
+			s.count(int(result.RowsAffected), 0)
+		}
+
+		// The rows left are the ones that could not be re-wrapped
+		if len(rows) < rewrapBatchSize || !progressed {
+			return nil
+		}
+	}
+}
+
+func (s *PiiRewrapService) count(rewrapped, failed int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status.Rewrapped += rewrapped
+	s.status.Failed += failed
+}
@@ -0,0 +1,72 @@
+package apiServices
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/wecredit/communication-sdk/internal/pii"
+	"github.com/wecredit/communication-sdk/sdk/variables"
+	"gorm.io/gorm"
+)
+
+// CommPii is the personal data of one message, decrypted from its input and output rows.
+type CommPii struct {
+	CommId  string `json:"commId"`
+	Channel string `json:"channel"`
+	Mobile  string `json:"mobile,omitempty"`
+	Email   string `json:"email,omitempty"`
+}
+
+// PiiService decrypts the personal data stored encrypted by pii.ProtectRow.
+type PiiService struct {
+	DB *gorm.DB
+}
+
+func NewPiiService(db *gorm.DB) *PiiService {
+	return &PiiService{DB: db}
+}
+
+// Reveal returns the personal data of the message, looking it up in the tables of its channel, or of every channel
+// when channel is empty. Rows stored before encryption was enabled hold no envelope and reveal nothing.
+func (s *PiiService) Reveal(commId, channel string) (CommPii, error) {
+	commId = strings.TrimSpace(commId)
+	channels := []string{variables.SMS, variables.WhatsApp, variables.Email, variables.RCS}
+	if channel != "" {
+		channels = []string{strings.ToUpper(channel)}
+	}
+
+	for _, ch := range channels {
+		var rows []map[string]interface{}
+		for _, table := range []string{inputTableForChannel(ch), outputTableForChannel(ch)} {
+			if table == "" {
+				continue
+			}
+			var found []map[string]interface{}
+			if err := s.DB.Table(table).Where("CommId = ?", commId).Limit(1).Find(&found).Error; err != nil {
+				return CommPii{}, fmt.Errorf("failed to look up CommId %s in %s: %w", commId, table, err)
+			}
+			rows = append(rows, found...)
+		}
+		if len(rows) == 0 {
+			continue
+		}
+
+		result := CommPii{CommId: commId, Channel: ch}
+		for _, row := range rows {
+			revealed, err := pii.RevealRow(commId, row)
+			if err != nil {
+				return CommPii{}, fmt.Errorf("failed to decrypt CommId %s: %w", commId, err)
+			}
+			for _, column := range []string{"Mobile", "MobileNumber"} {
+				if result.Mobile == "" {
+					result.Mobile = revealed[column]
+				}
+			}
+			if result.Email == "" {
+				result.Email = revealed["Email"]
+			}
+		}
+		return result, nil
+	}
+	return CommPii{}, fmt.Errorf("%w: %s", ErrCommNotFound, commId)
+}
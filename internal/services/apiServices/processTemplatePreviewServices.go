@@ -0,0 +1,147 @@
+package apiServices
+
+import (
+	"fmt"
+
+	"github.com/wecredit/communication-sdk/internal/channels/channelHelper"
+	sinchEmailPayload "github.com/wecredit/communication-sdk/internal/channels/email/sinch/sinchPayloads"
+	sinchSmsPayload "github.com/wecredit/communication-sdk/internal/channels/sms/sinch/sinchPayloads"
+	timesSmsPayload "github.com/wecredit/communication-sdk/internal/channels/sms/times/timesPayloads"
+	"github.com/wecredit/communication-sdk/internal/channels/whatsapp"
+	sinchWhatsapp "github.com/wecredit/communication-sdk/internal/channels/whatsapp/sinch"
+	timesWhatsapp "github.com/wecredit/communication-sdk/internal/channels/whatsapp/times"
+	"github.com/wecredit/communication-sdk/internal/models/apiModels"
+	extapimodels "github.com/wecredit/communication-sdk/internal/models/extApiModels"
+	"github.com/wecredit/communication-sdk/internal/redact"
+	"github.com/wecredit/communication-sdk/internal/vendorAccounts"
+	sdkHelper "github.com/wecredit/communication-sdk/sdk/helper"
+	"github.com/wecredit/communication-sdk/sdk/models/sdkModels"
+	"github.com/wecredit/communication-sdk/sdk/variables"
+)
+
+// PreviewTemplate renders the template with sample variables and builds the payload its vendor would receive.
+// It never calls the vendor or touches Redis; render and validation problems are reported in the preview.
+func (s *TemplateService) PreviewTemplate(id uint, req apiModels.TemplatePreviewRequest) (*apiModels.TemplatePreview, error) {
+	template, err := s.GetTemplateByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &apiModels.TemplatePreview{
+		TemplateId: template.Id,
+		Channel:    template.Channel,
+		Vendor:     template.Vendor,
+	}
+
+	if ok, message := sdkHelper.ValidateCommRequest(sdkModels.CommApiRequestBody{
+		Mobile:      req.Mobile,
+		Email:       req.Email,
+		Channel:     template.Channel,
+		ProcessName: template.Process,
+	}); !ok {
+		preview.Errors = append(preview.Errors, message)
+	}
+
+	var payload map[string]interface{}
+	switch template.Channel {
+	case variables.SMS:
+		payload, err = previewSms(template, req, preview)
+	case variables.WhatsApp:
+		payload, err = previewWhatsapp(template, req, preview)
+	case variables.Email:
+		payload, err = previewEmail(template, req, preview)
+	default:
+		err = fmt.Errorf("preview is not supported for channel %s", template.Channel)
+	}
+	if err != nil {
+		preview.Errors = append(preview.Errors, err.Error())
+	}
+
+	preview.Payload = redact.Secrets(payload)
+	preview.Valid = len(preview.Errors) == 0
+	return preview, nil
+}
+
+func previewSms(template *apiModels.Templatedetails, req apiModels.TemplatePreviewRequest, preview *apiModels.TemplatePreview) (map[string]interface{}, error) {
+	smsReq := extapimodels.SmsRequestBody{
+		Mobile:    req.Mobile,
+		Process:   template.Process,
+		Client:    template.Client,
+		Variables: req.Variables,
+	}
+	channelHelper.PopulateSmsFields(&smsReq, *template)
+	if err := channelHelper.RenderSmsRequest(&smsReq); err != nil {
+		return nil, err
+	}
+	preview.RenderedText = smsReq.TemplateText
+
+	switch template.Vendor {
+	case variables.TIMES, variables.SINCH:
+		account, err := vendorAccounts.Resolve(template.Client, variables.SMS, template.Vendor)
+		if err != nil {
+			return nil, err
+		}
+		if template.Vendor == variables.TIMES {
+			return timesSmsPayload.GetTemplatePayload(smsReq, account)
+		}
+		return sinchSmsPayload.GetTemplatePayload(smsReq, account)
+	}
+	return nil, fmt.Errorf("preview is not supported for SMS vendor %s", template.Vendor)
+}
+
+func previewWhatsapp(template *apiModels.Templatedetails, req apiModels.TemplatePreviewRequest, preview *apiModels.TemplatePreview) (map[string]interface{}, error) {
+	wpReq := extapimodels.WhatsappRequestBody{
+		Mobile:    req.Mobile,
+		Process:   template.Process,
+		Client:    template.Client,
+		Variables: req.Variables,
+	}
+	channelHelper.PopulateWhatsappFields(&wpReq, *template)
+	if err := channelHelper.RenderWhatsappRequest(&wpReq); err != nil {
+		return nil, err
+	}
+	preview.BodyParameters = wpReq.BodyParameters
+	whatsapp.ApplyPaymentLink(&wpReq, template.Stage)
+
+	// The button link helpers slice the mobile number, so they need a full one to build a payload
+	if len(wpReq.Mobile) < 10 {
+		return nil, fmt.Errorf("a 10 digit mobile is required to build the WhatsApp payload")
+	}
+
+	switch template.Vendor {
+	case variables.TIMES:
+		return timesWhatsapp.GetPayload(wpReq)
+	case variables.SINCH:
+		account, err := vendorAccounts.Resolve(wpReq.Client, variables.WhatsApp, variables.SINCH)
+		if err != nil {
+			return nil, err
+		}
+		wpReq.AppId = account.AppId
+		return sinchWhatsapp.GetPayload(wpReq)
+	}
+	return nil, fmt.Errorf("preview is not supported for WhatsApp vendor %s", template.Vendor)
+}
+
+func previewEmail(template *apiModels.Templatedetails, req apiModels.TemplatePreviewRequest, preview *apiModels.TemplatePreview) (map[string]interface{}, error) {
+	emailReq := extapimodels.EmailRequestBody{
+		ToEmail:   req.Email,
+		Process:   template.Process,
+		Client:    template.Client,
+		Variables: req.Variables,
+	}
+	channelHelper.PopulateEmailFields(&emailReq, *template)
+	if err := channelHelper.RenderEmailRequest(&emailReq); err != nil {
+		return nil, err
+	}
+	preview.Attributes = emailReq.Attributes
+
+	switch template.Vendor {
+	case variables.SINCH:
+		account, err := vendorAccounts.Resolve(emailReq.Client, variables.Email, variables.SINCH)
+		if err != nil {
+			return nil, err
+		}
+		return sinchEmailPayload.GetTemplatePayload(emailReq, account)
+	}
+	return nil, fmt.Errorf("preview is not supported for Email vendor %s", template.Vendor)
+}
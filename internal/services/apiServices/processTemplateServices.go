@@ -1,14 +1,14 @@
 package apiServices
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"sort"
-	"strconv"
 	"strings"
 	"time"
 
-	"github.com/wecredit/communication-sdk/config"
+	"github.com/wecredit/communication-sdk/internal/channels/channelHelper"
 	"github.com/wecredit/communication-sdk/internal/models/apiModels"
 	"github.com/wecredit/communication-sdk/pkg/cache"
 	"github.com/wecredit/communication-sdk/sdk/utils"
@@ -24,9 +24,8 @@ func NewTemplateService(db *gorm.DB) *TemplateService {
 }
 
 func (s *TemplateService) GetTemplates(process, stage, client, channel, vendor string) ([]apiModels.Templatedetails, error) {
-	// cache.StoreMappedDataIntoCache(cache.TemplateDetailsData, config.Configs.TemplateDetailsTable, "Process", "Stage", s.DB) // Temporary: ensure cache is populated
-	templateDetails, found := cache.GetCache().GetMappedData(cache.TemplateDetailsData)
-	if !found {
+	snapshot := cache.Current()
+	if !snapshot.Loaded(cache.TemplateDetailsData) {
 		utils.Error(fmt.Errorf("template data not found in cache"))
 		return nil, errors.New("template data not found in cache")
 	}
@@ -36,33 +35,22 @@ func (s *TemplateService) GetTemplates(process, stage, client, channel, vendor s
 	// Case 1: all params provided → direct key lookup
 	if process != "" && stage != "" && client != "" && channel != "" && vendor != "" {
 		key := fmt.Sprintf("Process:%s|Stage:%s|Client:%s|Channel:%s|Vendor:%s", process, stage, client, channel, vendor)
-		if data, ok := templateDetails[key]; ok {
-			template, err := mapToTemplate(data)
-			if err != nil {
-				utils.Error(fmt.Errorf("failed to convert cache data to template: %v", err))
-				return nil, err
-			}
-			return []apiModels.Templatedetails{*template}, nil
+		if template, ok := snapshot.Templates[key]; ok {
+			return []apiModels.Templatedetails{template}, nil
 		}
 		return nil, nil // no match
 	}
 
 	// Case 2: filtering
-	for _, data := range templateDetails {
-		stageFloat, _ := strconv.ParseFloat(string(data["Stage"].([]uint8)), 64)
-		if (process != "" && data["Process"] != process) ||
-			(stage != "" && fmt.Sprintf("%.2f", stageFloat) != stage) ||
-			(client != "" && data["Client"] != client) ||
-			(channel != "" && data["Channel"] != channel) ||
-			(vendor != "" && data["Vendor"] != vendor) {
-			continue
-		}
-		template, err := mapToTemplate(data)
-		if err != nil {
-			utils.Error(fmt.Errorf("skipping invalid template data: %v", err))
+	for _, template := range snapshot.Templates {
+		if (process != "" && template.Process != process) ||
+			(stage != "" && fmt.Sprintf("%.2f", template.Stage) != stage) ||
+			(client != "" && template.Client != client) ||
+			(channel != "" && template.Channel != channel) ||
+			(vendor != "" && template.Vendor != vendor) {
 			continue
 		}
-		templates = append(templates, *template)
+		templates = append(templates, template)
 	}
 
 	// Sorting in required flow: Client > Channel > Process > Stage > Vendor
@@ -85,36 +73,20 @@ func (s *TemplateService) GetTemplates(process, stage, client, channel, vendor s
 	return templates, nil
 }
 
+// GetTemplateByID returns the cached template for id, as the consumer sees it.
 func (s *TemplateService) GetTemplateByID(id uint) (*apiModels.Templatedetails, error) {
-	idIndex, found := cache.GetCache().GetMappedIdData(cache.TemplateDetailsData + ":IdIndex")
-	if !found {
-		utils.Error(fmt.Errorf("template Id index not found in cache"))
-		return nil, errors.New("template Id index not found in cache")
-	}
-
-	key, ok := idIndex[id]
-	if !ok {
-		return nil, errors.New("template not found")
-	}
-
-	templateDetails, found := cache.GetCache().GetMappedData(cache.TemplateDetailsData)
-	if !found {
+	snapshot := cache.Current()
+	if !snapshot.Loaded(cache.TemplateDetailsData) {
 		utils.Error(fmt.Errorf("template data not found in cache"))
 		return nil, errors.New("template data not found in cache")
 	}
 
-	data, ok := templateDetails[key]
+	template, ok := snapshot.TemplateById(id)
 	if !ok {
-		return nil, errors.New("template not found")
-	}
-
-	template, err := mapToTemplate(data)
-	if err != nil {
-		utils.Error(fmt.Errorf("failed to convert cache data to template: %v", err))
-		return nil, err
+		return nil, fmt.Errorf("template not found: %w", gorm.ErrRecordNotFound)
 	}
 
-	return template, nil
+	return &template, nil
 }
 
 func (s *TemplateService) AddTemplate(template *apiModels.Templatedetails) error {
@@ -125,32 +97,96 @@ func (s *TemplateService) AddTemplate(template *apiModels.Templatedetails) error
 	template.Vendor = strings.ToUpper(template.Vendor)
 	template.Client = strings.ToLower(template.Client)
 
-	err := s.DB.Create(template).Error
+	if _, err := channelHelper.ParseVariableSpecs(template.TemplateVariables, template.Channel); err != nil {
+		return fmt.Errorf("invalid TemplateVariables: %v", err)
+	}
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if template.IsActive {
+			if err := ensureNoOtherActiveTemplate(tx, template.Process, template.Stage, template.Client, template.Channel, template.Vendor, 0); err != nil {
+				return err
+			}
+		}
+		return tx.Create(template).Error
+	})
 	if err != nil {
 		return err
 	}
 
-	cache.StoreMappedDataIntoCache(cache.TemplateDetailsData, config.Configs.TemplateDetailsTable, "Process", "Stage", s.DB)
+	cache.Refresh(cache.TemplateDetailsData, s.DB)
 
 	return nil
 }
 
-func (s *TemplateService) UpdateTemplateById(id int, updates map[string]interface{}) error {
+// templateContentFields are the fields of a template that are versioned, keyed by their JSON name in lower case.
+var templateContentFields = map[string]bool{
+	"templatename":      true,
+	"imageid":           true,
+	"imageurl":          true,
+	"dlttemplateid":     true,
+	"templatetext":      true,
+	"link":              true,
+	"templatecategory":  true,
+	"templatevariables": true,
+	"subject":           true,
+	"fromemail":         true,
+	"notes":             true,
+}
+
+// UpdateTemplateById applies updates to the template with the given id. Content changes are stored as a DRAFT
+// version, which is returned and goes live once approved and activated like any other; only isActive changes the
+// live row directly. The key of a template (process, stage, client, channel and vendor) cannot be changed.
+func (s *TemplateService) UpdateTemplateById(id int, updates map[string]interface{}) (*apiModels.TemplateVersion, error) {
 	var existing apiModels.Templatedetails
 	if err := s.DB.Where("id = ?", id).First(&existing).Error; err != nil {
-		return errors.New("template not found")
+		return nil, errors.New("template not found")
 	}
 
-	// add updatedOn timestamp
-	istOffset := 5*time.Hour + 30*time.Minute
-	updates["UpdatedOn"] = time.Now().UTC().Add(istOffset)
+	content := map[string]interface{}{}
+	var isActive *bool
+	for field, value := range updates {
+		switch name := strings.ToLower(field); {
+		case templateContentFields[name]:
+			content[field] = value
+		case name == "isactive":
+			active, ok := value.(bool)
+			if !ok {
+				return nil, errors.New("invalid update: isActive must be a boolean")
+			}
+			isActive = &active
+		default:
+			return nil, fmt.Errorf("invalid update: %s cannot be changed, create a new template instead", field)
+		}
+	}
 
-	if err := s.DB.Model(&existing).Updates(updates).Error; err != nil {
-		return err
+	var draft *apiModels.TemplateVersion
+	if len(content) > 0 {
+		version := versionFromTemplate(existing)
+		raw, _ := json.Marshal(content)
+		if err := json.Unmarshal(raw, &version); err != nil {
+			return nil, fmt.Errorf("invalid update: %v", err)
+		}
+		if err := NewTemplateVersionService(s.DB).CreateDraft(id, &version); err != nil {
+			return nil, err
+		}
+		draft = &version
 	}
 
-	cache.StoreMappedDataIntoCache(cache.TemplateDetailsData, config.Configs.TemplateDetailsTable, "Process", "Stage", s.DB)
-	return nil
+	if isActive != nil && *isActive != existing.IsActive {
+		err := s.DB.Transaction(func(tx *gorm.DB) error {
+			if *isActive {
+				if err := ensureNoOtherActiveTemplate(tx, existing.Process, existing.Stage, existing.Client, existing.Channel, existing.Vendor, existing.Id); err != nil {
+					return err
+				}
+			}
+			return tx.Model(&existing).Updates(map[string]interface{}{"IsActive": *isActive, "UpdatedOn": utils.IstNow()}).Error
+		})
+		if err != nil {
+			return draft, err
+		}
+		cache.Refresh(cache.TemplateDetailsData, s.DB)
+	}
+	return draft, nil
 }
 
 func (s *TemplateService) DeleteTemplate(id int) error {
@@ -162,94 +198,6 @@ func (s *TemplateService) DeleteTemplate(id int) error {
 		return gorm.ErrRecordNotFound
 	}
 
-	cache.StoreMappedDataIntoCache(cache.TemplateDetailsData, config.Configs.TemplateDetailsTable, "Process", "Stage", s.DB)
+	cache.Refresh(cache.TemplateDetailsData, s.DB)
 	return nil
 }
-func mapToTemplate(data map[string]interface{}) (*apiModels.Templatedetails, error) {
-	if data == nil {
-		return nil, fmt.Errorf("input data is nil")
-	}
-
-	getStr := func(key string) string {
-		if val, ok := data[key].(string); ok {
-			return val
-		}
-		return ""
-	}
-
-	getInt := func(key string) int {
-		if val, ok := data[key].(int64); ok {
-			return int(val)
-		}
-		return 0
-	}
-
-	getFloat := func(key string) float64 {
-		switch val := data[key].(type) {
-		case float64:
-			return val
-		case []byte:
-			f, err := strconv.ParseFloat(string(val), 64)
-			if err == nil {
-				return f
-			}
-		case string:
-			f, err := strconv.ParseFloat(val, 64)
-			if err == nil {
-				return f
-			}
-		}
-		return 0
-	}
-
-	getBool := func(key string) bool {
-		if val, ok := data[key].(int64); ok {
-			return val == 1
-		}
-		return false
-	}
-
-	template := &apiModels.Templatedetails{
-		Id:                getInt("Id"),
-		Client:            getStr("Client"),
-		Channel:           getStr("Channel"),
-		Process:           getStr("Process"),
-		Stage:             getFloat("Stage"),
-		Vendor:            getStr("Vendor"),
-		TemplateName:      getStr("TemplateName"),
-		ImageId:           getStr("ImageId"),
-		ImageUrl:          getStr("ImageUrl"),
-		DltTemplateId:     int64(getInt("DltTemplateId")), // stored as int64 anyway
-		IsActive:          getBool("IsActive"),
-		TemplateText:      getStr("TemplateText"),
-		TemplateCategory:  int64(getInt("TemplateCategory")),
-		TemplateVariables: getStr("TemplateVariables"),
-		FromEmail:         getStr("FromEmail"),
-		Subject:           getStr("Subject"),
-		Link:              getStr("Link"),
-	}
-
-	// CreatedOn
-	if createdOn, ok := data["CreatedOn"].(time.Time); ok {
-		template.CreatedOn = createdOn
-	}
-
-	// UpdatedOn
-	if raw, ok := data["UpdatedOn"]; ok && raw != nil {
-		switch v := raw.(type) {
-		case time.Time:
-			template.UpdatedOn = &v
-		case string:
-			layout := "2006-01-02 15:04:05.999 +0000 UTC"
-			if parsed, err := time.Parse(layout, v); err == nil {
-				template.UpdatedOn = &parsed
-			} else {
-				return nil, fmt.Errorf("invalid UpdatedOn time format: %v", err)
-			}
-		default:
-			return nil, fmt.Errorf("unsupported type for UpdatedOn: %T", raw)
-		}
-	}
-
-	return template, nil
-}
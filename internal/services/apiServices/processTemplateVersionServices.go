@@ -0,0 +1,319 @@
+package apiServices
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/wecredit/communication-sdk/config"
+	"github.com/wecredit/communication-sdk/internal/channels/channelHelper"
+	"github.com/wecredit/communication-sdk/internal/models/apiModels"
+	"github.com/wecredit/communication-sdk/pkg/cache"
+	"github.com/wecredit/communication-sdk/sdk/utils"
+	"github.com/wecredit/communication-sdk/sdk/variables"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrInvalidVersionState is returned when a version transition is not allowed from its current state.
+var ErrInvalidVersionState = errors.New("invalid template version state")
+
+type TemplateVersionService struct {
+	DB *gorm.DB
+}
+
+func NewTemplateVersionService(db *gorm.DB) *TemplateVersionService {
+	return &TemplateVersionService{DB: db}
+}
+
+func (s *TemplateVersionService) versions(tx *gorm.DB) *gorm.DB {
+	return tx.Table(config.Configs.TemplateVersionTable)
+}
+
+// CreateDraft stores a new DRAFT version of the template with the given id.
+// The live template row is snapshotted as the first ACTIVE version if it has no history yet, so it can be rolled back to.
+func (s *TemplateVersionService) CreateDraft(templateId int, draft *apiModels.TemplateVersion) error {
+	var template apiModels.Templatedetails
+	if err := s.DB.Where("id = ?", templateId).First(&template).Error; err != nil {
+		return err
+	}
+
+	if _, err := channelHelper.ParseVariableSpecs(draft.TemplateVariables, template.Channel); err != nil {
+		return fmt.Errorf("invalid TemplateVariables: %v", err)
+	}
+
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		latest, err := s.ensureBaselineVersion(tx, template)
+		if err != nil {
+			return err
+		}
+
+		draft.Id = 0
+		draft.TemplateId = template.Id
+		draft.Version = latest + 1
+		draft.State = variables.TemplateDraft
+		draft.Client = template.Client
+		draft.Channel = template.Channel
+		draft.Process = template.Process
+		draft.Stage = template.Stage
+		draft.Vendor = template.Vendor
+		draft.ActivateOn = nil
+		draft.ApprovedOn = nil
+		draft.ActivatedOn = nil
+		draft.CreatedOn = utils.IstNow()
+
+		return s.versions(tx).Create(draft).Error
+	})
+}
+
+// ensureBaselineVersion records the live template as its first version and returns the latest version number.
+func (s *TemplateVersionService) ensureBaselineVersion(tx *gorm.DB, template apiModels.Templatedetails) (int, error) {
+	var latest int
+	if err := s.versions(tx).Where("TemplateId = ?", template.Id).Select("COALESCE(MAX(Version), 0)").Scan(&latest).Error; err != nil {
+		return 0, err
+	}
+	if latest > 0 {
+		return latest, nil
+	}
+
+	now := utils.IstNow()
+	baseline := versionFromTemplate(template)
+	baseline.Version = 1
+	baseline.State = variables.TemplateSuperseded
+	if template.IsActive {
+		baseline.State = variables.TemplateActive
+		baseline.ActivatedOn = &now
+	}
+	baseline.CreatedOn = now
+
+	if err := s.versions(tx).Create(&baseline).Error; err != nil {
+		return 0, err
+	}
+	return baseline.Version, nil
+}
+
+func (s *TemplateVersionService) GetVersions(templateId int) ([]apiModels.TemplateVersion, error) {
+	var versions []apiModels.TemplateVersion
+	err := s.versions(s.DB).Where("TemplateId = ?", templateId).Order("Version DESC").Find(&versions).Error
+	return versions, err
+}
+
+// ApproveVersion moves a DRAFT version to APPROVED.
+func (s *TemplateVersionService) ApproveVersion(versionId int) (*apiModels.TemplateVersion, error) {
+	now := utils.IstNow()
+	result := s.versions(s.DB).
+		Where("Id = ? AND State = ?", versionId, variables.TemplateDraft).
+		Updates(map[string]interface{}{"State": variables.TemplateApproved, "ApprovedOn": now, "UpdatedOn": now})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		if _, err := s.getVersion(s.DB, versionId); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: only DRAFT versions can be approved", ErrInvalidVersionState)
+	}
+	return s.getVersion(s.DB, versionId)
+}
+
+// ActivateVersion makes an APPROVED version live, either immediately or at activateAt.
+func (s *TemplateVersionService) ActivateVersion(versionId int, activateAt *time.Time) (*apiModels.TemplateVersion, error) {
+	version, err := s.getVersion(s.DB, versionId)
+	if err != nil {
+		return nil, err
+	}
+	if version.State != variables.TemplateApproved {
+		return nil, fmt.Errorf("%w: only APPROVED versions can be activated, version %d is %s", ErrInvalidVersionState, version.Version, version.State)
+	}
+
+	if activateAt != nil {
+		// Timestamps are stored as IST wall-clock values, like every other column in these tables
+		istOffset := 5*time.Hour + 30*time.Minute
+		scheduled := activateAt.UTC().Add(istOffset)
+		activateAt = &scheduled
+	}
+
+	if activateAt != nil && activateAt.After(utils.IstNow()) {
+		if err := s.versions(s.DB).Where("Id = ?", versionId).
+			Updates(map[string]interface{}{"ActivateOn": *activateAt, "UpdatedOn": utils.IstNow()}).Error; err != nil {
+			return nil, err
+		}
+		utils.Info(fmt.Sprintf("Template %d version %d scheduled for activation at %s IST", version.TemplateId, version.Version, activateAt.Format("2006-01-02 15:04:05")))
+		return s.getVersion(s.DB, versionId)
+	}
+
+	if err := s.activate(versionId, variables.TemplateApproved); err != nil {
+		return nil, err
+	}
+	return s.getVersion(s.DB, versionId)
+}
+
+// RollbackTemplate re-activates the version that was live before the current ACTIVE one.
+func (s *TemplateVersionService) RollbackTemplate(templateId int) (*apiModels.TemplateVersion, error) {
+	var current apiModels.TemplateVersion
+	if err := s.versions(s.DB).Where("TemplateId = ? AND State = ?", templateId, variables.TemplateActive).First(&current).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: template %d has no active version to roll back", ErrInvalidVersionState, templateId)
+		}
+		return nil, err
+	}
+
+	var previous apiModels.TemplateVersion
+	err := s.versions(s.DB).
+		Where("TemplateId = ? AND State = ? AND ActivatedOn IS NOT NULL", templateId, variables.TemplateSuperseded).
+		Order("ActivatedOn DESC").Order("Version DESC").
+		First(&previous).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: template %d has no previous version to roll back to", ErrInvalidVersionState, templateId)
+		}
+		return nil, err
+	}
+
+	if err := s.activate(previous.Id, variables.TemplateSuperseded); err != nil {
+		return nil, err
+	}
+	utils.Info(fmt.Sprintf("Template %d rolled back from version %d to version %d", templateId, current.Version, previous.Version))
+	return s.getVersion(s.DB, previous.Id)
+}
+
+// ActivateScheduledVersions activates every APPROVED version whose ActivateOn has passed.
+func (s *TemplateVersionService) ActivateScheduledVersions() {
+	var due []apiModels.TemplateVersion
+	err := s.versions(s.DB).
+		Where("State = ? AND ActivateOn IS NOT NULL AND ActivateOn <= ?", variables.TemplateApproved, utils.IstNow()).
+		Order("ActivateOn ASC").
+		Find(&due).Error
+	if err != nil {
+		utils.Error(fmt.Errorf("failed to fetch scheduled template versions: %v", err))
+		return
+	}
+
+	for _, version := range due {
+		if err := s.activate(version.Id, variables.TemplateApproved); err != nil {
+			utils.Error(fmt.Errorf("scheduled activation failed for template %d version %d: %v", version.TemplateId, version.Version, err))
+			continue
+		}
+		utils.Info(fmt.Sprintf("Template %d version %d activated on schedule", version.TemplateId, version.Version))
+	}
+}
+
+// activate copies the version into the live template row inside a single transaction.
+// The template row is locked so concurrent activations for it are serialised, and the write is refused if
+// another live row already serves the same (process, stage, client, channel, vendor).
+func (s *TemplateVersionService) activate(versionId int, fromState string) error {
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var version apiModels.TemplateVersion
+		if err := s.versions(tx).Clauses(clause.Locking{Strength: "UPDATE"}).Where("Id = ?", versionId).First(&version).Error; err != nil {
+			return err
+		}
+		if version.State != fromState {
+			return fmt.Errorf("%w: version %d is %s, expected %s", ErrInvalidVersionState, version.Version, version.State, fromState)
+		}
+
+		var template apiModels.Templatedetails
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", version.TemplateId).First(&template).Error; err != nil {
+			return err
+		}
+
+		if err := ensureNoOtherActiveTemplate(tx, template.Process, template.Stage, template.Client, template.Channel, template.Vendor, template.Id); err != nil {
+			return err
+		}
+
+		now := utils.IstNow()
+		if err := s.versions(tx).
+			Where("TemplateId = ? AND State = ?", version.TemplateId, variables.TemplateActive).
+			Updates(map[string]interface{}{"State": variables.TemplateSuperseded, "UpdatedOn": now}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&template).Updates(map[string]interface{}{
+			"TemplateName":      version.TemplateName,
+			"ImageId":           version.ImageId,
+			"ImageUrl":          version.ImageUrl,
+			"DltTemplateId":     version.DltTemplateId,
+			"TemplateText":      version.TemplateText,
+			"Link":              version.Link,
+			"TemplateCategory":  version.TemplateCategory,
+			"TemplateVariables": version.TemplateVariables,
+			"Subject":           version.Subject,
+			"FromEmail":         version.FromEmail,
+			"IsActive":          true,
+			"UpdatedOn":         now,
+		}).Error; err != nil {
+			return err
+		}
+
+		return s.versions(tx).Where("Id = ?", version.Id).Updates(map[string]interface{}{
+			"State":       variables.TemplateActive,
+			"ActivateOn":  nil,
+			"ActivatedOn": now,
+			"UpdatedOn":   now,
+		}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	cache.Refresh(cache.TemplateDetailsData, s.DB)
+	return nil
+}
+
+// GetVersion returns the template version with the given id.
+func (s *TemplateVersionService) GetVersion(versionId int) (*apiModels.TemplateVersion, error) {
+	return s.getVersion(s.DB, versionId)
+}
+
+func (s *TemplateVersionService) getVersion(tx *gorm.DB, versionId int) (*apiModels.TemplateVersion, error) {
+	var version apiModels.TemplateVersion
+	if err := s.versions(tx).Where("Id = ?", versionId).First(&version).Error; err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
+
+// ensureNoOtherActiveTemplate refuses a write that would leave two live templates for the same key. tx must be a
+// transaction: the rows of the key, and the gap around them, stay locked until it ends, so a concurrent write of
+// another live template for the key waits for it and then sees its result.
+func ensureNoOtherActiveTemplate(tx *gorm.DB, process string, stage float64, client, channel, vendor string, excludeId int) error {
+	var rows []apiModels.Templatedetails
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id", "IsActive").
+		Where("Process = ? AND Stage = ? AND Client = ? AND Channel = ? AND Vendor = ?", process, stage, client, channel, vendor).
+		Find(&rows).Error
+	if err != nil {
+		return err
+	}
+	count := 0
+	for _, row := range rows {
+		if row.IsActive && row.Id != excludeId {
+			count++
+		}
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: another active template exists for Process: %s, Stage: %.2f, Client: %s, Channel: %s, Vendor: %s",
+			ErrInvalidVersionState, process, stage, client, channel, vendor)
+	}
+	return nil
+}
+
+func versionFromTemplate(template apiModels.Templatedetails) apiModels.TemplateVersion {
+	return apiModels.TemplateVersion{
+		TemplateId:        template.Id,
+		Client:            template.Client,
+		Channel:           template.Channel,
+		Process:           template.Process,
+		Stage:             template.Stage,
+		Vendor:            template.Vendor,
+		TemplateName:      template.TemplateName,
+		ImageId:           template.ImageId,
+		ImageUrl:          template.ImageUrl,
+		DltTemplateId:     template.DltTemplateId,
+		TemplateText:      template.TemplateText,
+		Link:              template.Link,
+		TemplateCategory:  template.TemplateCategory,
+		TemplateVariables: template.TemplateVariables,
+		Subject:           template.Subject,
+		FromEmail:         template.FromEmail,
+	}
+}
@@ -0,0 +1,137 @@
+package apiServices
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/wecredit/communication-sdk/config"
+	"github.com/wecredit/communication-sdk/internal/models/apiModels"
+	"github.com/wecredit/communication-sdk/internal/vendorAccounts"
+	"github.com/wecredit/communication-sdk/pkg/cache"
+	"github.com/wecredit/communication-sdk/sdk/utils"
+	"github.com/wecredit/communication-sdk/sdk/variables"
+	"gorm.io/gorm"
+)
+
+// VendorAccountService manages the accounts clients send through at each vendor.
+type VendorAccountService struct {
+	DB *gorm.DB
+}
+
+func NewVendorAccountService(db *gorm.DB) *VendorAccountService {
+	return &VendorAccountService{DB: db}
+}
+
+func (s *VendorAccountService) accounts() *gorm.DB {
+	return s.DB.Table(config.Configs.VendorAccountTable)
+}
+
+// GetAccounts returns the vendor accounts matching the filters, inactive ones included. Credentials are never returned.
+func (s *VendorAccountService) GetAccounts(client, channel, vendor string) ([]apiModels.VendorAccount, error) {
+	query := s.accounts().Order("Id")
+	if client != "" {
+		query = query.Where("Client = ?", strings.ToLower(strings.TrimSpace(client)))
+	}
+	if channel != "" {
+		query = query.Where("Channel = ?", strings.ToUpper(strings.TrimSpace(channel)))
+	}
+	if vendor != "" {
+		query = query.Where("Vendor = ?", strings.ToUpper(strings.TrimSpace(vendor)))
+	}
+
+	var accounts []apiModels.VendorAccount
+	if err := query.Find(&accounts).Error; err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+// CreateAccount stores a vendor account with its credentials encrypted.
+func (s *VendorAccountService) CreateAccount(account *apiModels.VendorAccount) error {
+	account.Client = strings.ToLower(strings.TrimSpace(account.Client))
+	account.Channel = strings.ToUpper(strings.TrimSpace(account.Channel))
+	account.Vendor = strings.ToUpper(strings.TrimSpace(account.Vendor))
+	if account.Credentials == nil {
+		return errors.New("credentials are required")
+	}
+
+	var existing int64
+	err := s.accounts().Where("Client = ? AND Channel = ? AND Vendor = ? AND Status = ?", account.Client, account.Channel, account.Vendor, variables.Active).
+		Count(&existing).Error
+	if err != nil {
+		return err
+	}
+	if existing > 0 {
+		return fmt.Errorf("an active %s %s account already exists for client %q", account.Vendor, account.Channel, account.Client)
+	}
+
+	if err := vendorAccounts.Seal(account, *account.Credentials); err != nil {
+		return fmt.Errorf("failed to encrypt credentials: %w", err)
+	}
+	account.Id = 0
+	account.Status = int(variables.Active)
+	account.CreatedOn = utils.IstNow()
+	account.UpdatedOn = nil
+
+	if err := s.accounts().Create(account).Error; err != nil {
+		return err
+	}
+	account.Credentials = nil
+	cache.Refresh(cache.VendorAccountsData, s.DB)
+	return nil
+}
+
+// UpdateAccount replaces the app id, sender details and, when given, the credentials of the account.
+// Client, channel and vendor cannot change, as the encrypted credentials are bound to them.
+func (s *VendorAccountService) UpdateAccount(id int, input apiModels.VendorAccount) (*apiModels.VendorAccount, error) {
+	var account apiModels.VendorAccount
+	if err := s.accounts().Where("Id = ?", id).First(&account).Error; err != nil {
+		return nil, err
+	}
+
+	now := utils.IstNow()
+	updates := map[string]interface{}{
+		"AppId":      input.AppId,
+		"Sender":     input.Sender,
+		"SenderName": input.SenderName,
+		"ReplyTo":    input.ReplyTo,
+		"UpdatedOn":  now,
+	}
+	if input.Credentials != nil {
+		if err := vendorAccounts.Seal(&account, *input.Credentials); err != nil {
+			return nil, fmt.Errorf("failed to encrypt credentials: %w", err)
+		}
+		updates["EncryptedCredentials"] = account.EncryptedCredentials
+		updates["WrappedKey"] = account.WrappedKey
+		updates["KeyId"] = account.KeyId
+	}
+
+	if err := s.accounts().Where("Id = ?", id).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+	account.AppId = input.AppId
+	account.Sender = input.Sender
+	account.SenderName = input.SenderName
+	account.ReplyTo = input.ReplyTo
+	account.UpdatedOn = &now
+	cache.Refresh(cache.VendorAccountsData, s.DB)
+	return &account, nil
+}
+
+// DeactivateAccount stops the account from being used; messages move to the client's accounts at other vendors
+// or, when it has none left for the channel, to the vendor's shared account.
+func (s *VendorAccountService) DeactivateAccount(id int) error {
+	result := s.accounts().Where("Id = ?", id).Updates(map[string]interface{}{
+		"Status":    0,
+		"UpdatedOn": utils.IstNow(),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	cache.Refresh(cache.VendorAccountsData, s.DB)
+	return nil
+}
@@ -0,0 +1,32 @@
+package apiServices
+
+import (
+	"strings"
+
+	"github.com/wecredit/communication-sdk/config"
+	"github.com/wecredit/communication-sdk/internal/models/apiModels"
+	"gorm.io/gorm"
+)
+
+// VendorAuditService reads the vendor call audit log written by vendorAudit.StartWriter.
+type VendorAuditService struct {
+	DB *gorm.DB
+}
+
+func NewVendorAuditService(db *gorm.DB) *VendorAuditService {
+	return &VendorAuditService{DB: db}
+}
+
+// GetCalls returns the vendor calls made for the message, oldest first, optionally of one vendor only.
+func (s *VendorAuditService) GetCalls(commId, vendor string) ([]apiModels.VendorCallAudit, error) {
+	query := s.DB.Table(config.Configs.VendorAuditTable).Where("CommId = ?", strings.TrimSpace(commId))
+	if vendor != "" {
+		query = query.Where("Vendor = ?", strings.ToUpper(strings.TrimSpace(vendor)))
+	}
+
+	var calls []apiModels.VendorCallAudit
+	if err := query.Order("Id").Limit(200).Find(&calls).Error; err != nil {
+		return nil, err
+	}
+	return calls, nil
+}
@@ -6,7 +6,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/wecredit/communication-sdk/config"
 	"github.com/wecredit/communication-sdk/internal/models/apiModels"
 	"github.com/wecredit/communication-sdk/pkg/cache"
 	"github.com/wecredit/communication-sdk/sdk/utils"
@@ -22,8 +21,8 @@ func NewVendorService(db *gorm.DB) *VendorService {
 }
 
 func (s *VendorService) GetVendors(channel, name, client string) ([]apiModels.Vendor, error) {
-	vendorDetails, found := cache.GetCache().GetMappedData(cache.VendorsData)
-	if !found {
+	snapshot := cache.Current()
+	if !snapshot.Loaded(cache.VendorsData) {
 		utils.Error(fmt.Errorf("vendor data not found in cache"))
 		return nil, errors.New("vendor data not found in cache")
 	}
@@ -36,77 +35,42 @@ func (s *VendorService) GetVendors(channel, name, client string) ([]apiModels.Ve
 
 	// Case 1: name, channel and client provided -> direct key lookup
 	if name != "" && channel != "" && client != "" {
-		key := fmt.Sprintf("Name:%s|Channel:%s|Client:%s", name, channel, client)
-		if data, ok := vendorDetails[key]; ok {
-			vendor, err := mapToVendor(data)
-			if err != nil {
-				utils.Error(fmt.Errorf("failed to convert cache data to vendor: %v", err))
-				return nil, err
-			}
-			return []apiModels.Vendor{*vendor}, nil
+		if vendor, ok := snapshot.Vendors[cache.VendorCacheKey(name, channel, client)]; ok {
+			return []apiModels.Vendor{vendor}, nil
 		}
 		return nil, nil // No match found
 	}
 
 	// Case 2: loop through entries with applied filters
-	for _, data := range vendorDetails {
-		dataChannel := strings.ToUpper(extractString(data["Channel"]))
-		dataName := strings.ToUpper(extractString(data["Name"]))
-		dataClient := strings.ToLower(extractString(data["Client"]))
-
-		if channel != "" && dataChannel != channel {
-			continue
-		}
-		if name != "" && dataName != name {
+	for _, vendor := range snapshot.Vendors {
+		if channel != "" && strings.ToUpper(vendor.Channel) != channel {
 			continue
 		}
-		if client != "" && dataClient != client {
+		if name != "" && strings.ToUpper(vendor.Name) != name {
 			continue
 		}
-		vendor, err := mapToVendor(data)
-		if err != nil {
-			utils.Error(fmt.Errorf("skipping invalid vendor data: %v", err))
+		if client != "" && vendor.Client != client {
 			continue
 		}
-		vendors = append(vendors, *vendor)
+		vendors = append(vendors, vendor)
 	}
 
 	return vendors, nil
 }
 
 func (s *VendorService) GetVendorByID(id uint) (*apiModels.Vendor, error) {
-	// Fetch Id index
-	idIndex, found := cache.GetCache().GetMappedIdData(cache.VendorsData + ":IdIndex")
-	if !found {
-		utils.Error(fmt.Errorf("vendor Id index not found in cache"))
-		return nil, errors.New("vendor Id index not found in cache")
-	}
-
-	// Look up cache key by Id
-	key, ok := idIndex[id]
-	if !ok {
-		return nil, errors.New("vendor not found")
-	}
-
-	// Fetch vendor data using the key
-	vendorDetails, found := cache.GetCache().GetMappedData(cache.VendorsData)
-	if !found {
+	snapshot := cache.Current()
+	if !snapshot.Loaded(cache.VendorsData) {
 		utils.Error(fmt.Errorf("vendor data not found in cache"))
 		return nil, errors.New("vendor data not found in cache")
 	}
 
-	data, ok := vendorDetails[key]
+	vendor, ok := snapshot.VendorById(id)
 	if !ok {
 		return nil, errors.New("vendor not found")
 	}
 
-	vendor, err := mapToVendor(data)
-	if err != nil {
-		utils.Error(fmt.Errorf("failed to convert cache data to vendor: %v", err))
-		return nil, err
-	}
-
-	return vendor, nil
+	return &vendor, nil
 }
 
 func (s *VendorService) AddVendor(vendor *apiModels.Vendor) error {
@@ -142,7 +106,7 @@ func (s *VendorService) AddVendor(vendor *apiModels.Vendor) error {
 	if err != nil {
 		return err
 	}
-	cache.StoreMappedDataIntoCache(cache.VendorsData, config.Configs.VendorTable, "Name", "Channel", s.DB)
+	cache.Refresh(cache.VendorsData, s.DB)
 	return nil
 }
 
@@ -182,7 +146,7 @@ func (s *VendorService) UpdateVendorByNameAndChannel(_, _ string, updates apiMod
 	if err != nil {
 		return err
 	}
-	cache.StoreMappedDataIntoCache(cache.VendorsData, config.Configs.VendorTable, "Name", "Channel", s.DB)
+	cache.Refresh(cache.VendorsData, s.DB)
 	return nil
 }
 
@@ -196,60 +160,7 @@ func (s *VendorService) DeleteVendor(id int) error {
 		return gorm.ErrRecordNotFound
 	}
 
-	cache.StoreMappedDataIntoCache(cache.VendorsData, config.Configs.VendorTable, "Name", "Channel", s.DB)
+	cache.Refresh(cache.VendorsData, s.DB)
 
 	return nil
 }
-
-// Helper function to convert map to Vendor struct
-func mapToVendor(data map[string]interface{}) (*apiModels.Vendor, error) {
-	vendor := &apiModels.Vendor{
-		Id:        int(data["Id"].(int64)),
-		Name:      extractString(data["Name"]),
-		Channel:   extractString(data["Channel"]),
-		Client:    strings.ToLower(extractString(data["Client"])),
-		Status:    int(data["Status"].(int64)),
-		IsHealthy: int(data["IsHealthy"].(int64)),
-		Weight:    int(data["Weight"].(int64)),
-	}
-
-	// Parse CreatedOn
-	if createdOn, ok := data["CreatedOn"].(time.Time); ok {
-		// parsed, err := time.Parse("2006-01-02 15:04:05.999 +0000 UTC", createdOn)
-		// if err != nil {
-		// 	return nil, fmt.Errorf("failed to parse CreatedOn: %v", err)
-		// }
-		vendor.CreatedOn = createdOn
-	}
-
-	// Parse UpdatedOn (nullable)
-	if updatedOn, ok := data["UpdatedOn"]; ok && updatedOn != nil {
-		switch v := updatedOn.(type) {
-		case time.Time:
-			vendor.UpdatedOn = &v
-		case string:
-			parsed, err := time.Parse("2006-01-02 15:04:05.999 +0000 UTC", v)
-			if err != nil {
-				return nil, fmt.Errorf("failed to parse UpdatedOn string: %v", err)
-			}
-			vendor.UpdatedOn = &parsed
-		default:
-			return nil, fmt.Errorf("unsupported type for UpdatedOn: %T", updatedOn)
-		}
-	}
-
-	return vendor, nil
-}
-
-func extractString(val interface{}) string {
-	switch v := val.(type) {
-	case nil:
-		return ""
-	case string:
-		return v
-	case []byte:
-		return string(v)
-	default:
-		return fmt.Sprintf("%v", v)
-	}
-}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/google/uuid"
+	"github.com/wecredit/communication-sdk/config"
+	"github.com/wecredit/communication-sdk/internal/metrics"
+	"github.com/wecredit/communication-sdk/internal/redis"
+	"github.com/wecredit/communication-sdk/sdk/models/sdkModels"
+	"github.com/wecredit/communication-sdk/sdk/utils"
+)
+
+// claimSettings returns the lease of a claimed CommId and how long its state is kept, from the COMM_CLAIM_ settings.
+func claimSettings() (time.Duration, time.Duration) {
+	lease, err := strconv.Atoi(config.Configs.CommClaimLeaseSeconds)
+	if err != nil || lease <= 0 {
+		lease = 300
+	}
+	retention, err := strconv.Atoi(config.Configs.CommClaimRetentionHours)
+	if err != nil || retention <= 0 {
+		retention = 96
+	}
+	return time.Duration(lease) * time.Second, time.Duration(retention) * time.Hour
+}
+
+// claimMessage claims the CommId of the message before a vendor is called, returning the owner token of the claim.
+// When the message must not be sent now, ok is false and processed and deleted report what was done with it:
+// a CommId already done is acknowledged, and one another worker is sending is left for SQS to redeliver.
+func claimMessage(ctx context.Context, sqsClient *sqs.SQS, queueURL string, msg *sqs.Message, data sdkModels.CommApiRequestBody) (owner string, ok, processed, deleted bool) {
+	lease, retention := claimSettings()
+	owner = uuid.NewString()
+	claimed, state, err := redis.ClaimComm(ctx, redis.RDB, data.CommId, owner, lease, retention)
+	if err != nil {
+		// Redis error is transient - don't send without the claim, let SQS redeliver
+		utils.ErrorCtx(ctx, err)
+		return "", false, false, false
+	}
+
+	if claimed {
+		if state == redis.ClaimInProgress {
+			utils.WarnCtx(ctx, fmt.Sprintf("lease of CommId %s expired before its send finished, sending it again", data.CommId))
+		}
+		return owner, true, false, false
+	}
+
+	metrics.MessagesSkipped.WithLabelValues(strings.ToLower(data.Client), strings.ToUpper(data.Channel), state).Inc()
+	if state == redis.ClaimInProgress {
+		utils.InfoCtx(ctx, fmt.Sprintf("CommId %s is being sent by another worker, leaving the redelivered message", data.CommId))
+		return "", false, false, false
+	}
+
+	utils.InfoCtx(ctx, fmt.Sprintf("CommId %s was already sent, acknowledging the redelivered message", data.CommId))
+	deleted, delErr := deleteMessage(ctx, sqsClient, queueURL, msg, data)
+	if !deleted {
+		utils.ErrorCtx(ctx, fmt.Errorf("failed to delete redelivered message of CommId %s: %v", data.CommId, delErr))
+	}
+	return "", false, true, deleted
+}
+
+// releaseMessage records the outcome of the claim: done once the message was processed, whether it was sent or
+// not, and failed when it is left for SQS to redeliver, so the next delivery claims it again.
+func releaseMessage(ctx context.Context, data sdkModels.CommApiRequestBody, owner string, processed bool) {
+	state := redis.ClaimFailed
+	if processed {
+		state = redis.ClaimDone
+	}
+	_, retention := claimSettings()
+	released, err := redis.ReleaseComm(context.WithoutCancel(ctx), redis.RDB, data.CommId, owner, state, retention)
+	if err != nil {
+		utils.ErrorCtx(ctx, err)
+		return
+	}
+	if !released {
+		utils.WarnCtx(ctx, fmt.Sprintf("CommId %s was claimed by another worker after the lease expired, not recording %s", data.CommId, state))
+	}
+}
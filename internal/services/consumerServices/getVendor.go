@@ -48,7 +48,7 @@ func GetVendorByClientAndChannel(channel, client, idempotencyKey string) string
 	channel = strings.ToUpper(channel)
 	client = strings.ToLower(client)
 
-	if channelSlots, ok := cache.ChannelVendorSlots[channel]; ok {
+	if channelSlots, ok := cache.Current().VendorSlots[channel]; ok {
 		if slots, ok := channelSlots[client]; ok {
 			if vendor := slots[val]; vendor != "" {
 				return vendor
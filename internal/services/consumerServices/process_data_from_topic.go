@@ -6,7 +6,7 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
-	"strconv"
+	"slices"
 	"strings"
 	"sync"
 	"syscall"
@@ -16,24 +16,31 @@ import (
 	"github.com/aws/aws-sdk-go/service/sqs"
 	"github.com/wecredit/communication-sdk/config"
 
-	// "github.com/wecredit/communication-sdk/internal/channels/channelHelper"
+	"github.com/wecredit/communication-sdk/internal/channels/channelHelper"
 	email "github.com/wecredit/communication-sdk/internal/channels/email"
 	rcs "github.com/wecredit/communication-sdk/internal/channels/rcs"
 	sms "github.com/wecredit/communication-sdk/internal/channels/sms"
 	"github.com/wecredit/communication-sdk/internal/channels/whatsapp"
-	"github.com/wecredit/communication-sdk/internal/database"
+	"github.com/wecredit/communication-sdk/internal/metrics"
 	"github.com/wecredit/communication-sdk/internal/models/awsModels"
+	"github.com/wecredit/communication-sdk/internal/pii"
 	"github.com/wecredit/communication-sdk/internal/redis"
 	dbservices "github.com/wecredit/communication-sdk/internal/services/dbService"
+	"github.com/wecredit/communication-sdk/internal/services/webhookService"
+	"github.com/wecredit/communication-sdk/internal/snsAuth"
+	"github.com/wecredit/communication-sdk/internal/tracing"
+	"github.com/wecredit/communication-sdk/pkg/cache"
 	"github.com/wecredit/communication-sdk/sdk/models/sdkModels"
 	"github.com/wecredit/communication-sdk/sdk/queue"
 	"github.com/wecredit/communication-sdk/sdk/utils"
 	"github.com/wecredit/communication-sdk/sdk/variables"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type MessageWrapper struct {
-	Message *sqs.Message
-	Payload sdkModels.CommApiRequestBody
+	Message     *sqs.Message
+	Payload     sdkModels.CommApiRequestBody
+	SpanContext trace.SpanContext // span the message was published in, from its SNS message attributes
 }
 
 type clientRoutine struct {
@@ -55,6 +62,12 @@ func ConsumerService(workerCount int, queueURL string) {
 
 	go handleShutdown(cancel)
 
+	verifier, err := newMessageVerifier()
+	if err != nil {
+		utils.Error(fmt.Errorf("failed to start consumer: %v", err))
+		return
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -88,13 +101,13 @@ func ConsumerService(workerCount int, queueURL string) {
 			utils.Debug(fmt.Sprintf("[Consumer] Received %d messages from queue %s", len(result.Messages), queueURL))
 
 			for _, msg := range result.Messages {
-				go routeMessageToClient(ctx, msg, queueURL)
+				go routeMessageToClient(ctx, verifier, msg, queueURL)
 			}
 		}
 	}
 }
 
-func routeMessageToClient(ctx context.Context, msg *sqs.Message, queueURL string) {
+func routeMessageToClient(ctx context.Context, verifier *snsAuth.Verifier, msg *sqs.Message, queueURL string) {
 	defer func() {
 		if r := recover(); r != nil {
 			utils.Error(fmt.Errorf("panic recovered in routeMessageToClient: %v", r))
@@ -113,11 +126,23 @@ func routeMessageToClient(ctx context.Context, msg *sqs.Message, queueURL string
 		return
 	}
 
+	// Only messages delivered by SNS and published by our producers are sent
+	if err := verifier.Verify(ctx, snsWrapper); err != nil {
+		rejectMessage(ctx, msg, queueURL, snsWrapper, data, err)
+		return
+	}
+	if err := pii.DecryptRequest(&data); err != nil {
+		utils.Error(fmt.Errorf("failed to decrypt SQS message %s of CommId %s, leaving it for redelivery: %v", aws.StringValue(msg.MessageId), data.CommId, err))
+		return
+	}
+
 	client := strings.ToLower(data.Client)
 	if client == "" {
 		utils.Warn("Empty client found in payload. Skipping.")
 		return
 	}
+	metrics.MessagesReceived.WithLabelValues(client, strings.ToUpper(data.Channel)).Inc()
+	metrics.ObserveReceiveLag(strings.ToUpper(data.Channel), snsWrapper.Timestamp)
 
 	clientMux.Lock()
 	handler, exists := clientHandlers[client]
@@ -133,11 +158,13 @@ func routeMessageToClient(ctx context.Context, msg *sqs.Message, queueURL string
 			handler.wg.Add(1)
 			go startClientWorker(ctx, client, handler.msgChan, queue.SQSClient, queueURL, handler.wg)
 		}
+		metrics.WorkersConfigured.WithLabelValues(client).Set(float64(handler.workers))
 		utils.Info(fmt.Sprintf("Started %d workers for client: %s", handler.workers, client))
 	}
 	clientMux.Unlock()
 
-	handler.msgChan <- MessageWrapper{Message: msg, Payload: data}
+	metrics.WorkerQueueLength.WithLabelValues(client).Inc()
+	handler.msgChan <- MessageWrapper{Message: msg, Payload: data, SpanContext: tracing.ExtractSNS(snsWrapper.MessageAttributes)}
 }
 
 func startClientWorker(ctx context.Context, client string, msgChan <-chan MessageWrapper, sqsClient *sqs.SQS, queueURL string, wg *sync.WaitGroup) {
@@ -163,7 +190,15 @@ func startClientWorker(ctx context.Context, client string, msgChan <-chan Messag
 				<-timeout.C
 			}
 			timeout.Reset(time.Hour)
+			metrics.WorkerQueueLength.WithLabelValues(client).Dec()
+			metrics.WorkersBusy.WithLabelValues(client).Inc()
 			isMessageProcessed, deleted := processMessage(ctx, sqsClient, queueURL, msgWrapper)
+			metrics.WorkersBusy.WithLabelValues(client).Dec()
+			result := metrics.ResultProcessed
+			if !isMessageProcessed {
+				result = metrics.ResultRetry
+			}
+			metrics.MessagesProcessed.WithLabelValues(client, strings.ToUpper(msgWrapper.Payload.Channel), result).Inc()
 			// Note: Message deletion is handled inside processMessage and channel handlers
 			// Only delete here if processMessage explicitly indicates it should be deleted
 			// but wasn't already deleted (e.g., on fatal errors)
@@ -187,6 +222,7 @@ func startClientWorker(ctx context.Context, client string, msgChan <-chan Messag
 					close(handler.msgChan)
 				})
 				delete(clientHandlers, client)
+				metrics.WorkersConfigured.DeleteLabelValues(client)
 			}
 			clientMux.Unlock()
 			return
@@ -202,93 +238,58 @@ func handleShutdown(cancelFunc context.CancelFunc) {
 	cancelFunc()
 }
 
-func processMessage(ctx context.Context, sqsClient *sqs.SQS, queueURL string, msgWrapper MessageWrapper) (bool, bool) {
+func processMessage(ctx context.Context, sqsClient *sqs.SQS, queueURL string, msgWrapper MessageWrapper) (isMessageProcessed, deleted bool) {
 	msg := msgWrapper.Message
 	data := msgWrapper.Payload
 
-	// redis check : mobile_channel already exists. no api hit. upsert query.  redis key : mobile_channel -> sinch_responseid -> database insert.
-	// continue
-	// else store key in redis (mobile_channel)
-
-	// Convert stage to string for Redis key
-	// redisKey := channelHelper.GenerateRedisKey(data.Mobile, data.Channel, data.Stage)
-
-	// // check if message already sent for once
-	// exists, transactionId, errorMessage, err := redis.GetMobileDataFromRedis(config.Configs.CommIdempotentKey, redisKey, redis.RDB)
-	// if err != nil {
-	// 	utils.Error(fmt.Errorf("error in checking mobile: %s, redisKey: %s on redis: %v", data.Mobile, redisKey, err))
-	// 	// Redis error is transient - don't delete message, let it retry
-	// 	return false, false // message is not processed as redis check failed
-	// }
-
-	// // If we have data from Redis, handle accordingly
-	// if exists {
-	// 	// Priority: If we have a transactionId, the message was successfully processed before
-	// 	if transactionId != "" {
-	// 		// check if record already exists in output table
-	// 		dataExistsAlready, err := CheckIfDataAlreadyExists(data, redisKey, transactionId)
-	// 		if err != nil {
-	// 			utils.Error(fmt.Errorf("error checking if data exists for mobile: %s, redisKey: %s, transactionId: %s: %v", data.Mobile, redisKey, transactionId, err))
-	// 			return false, false
-	// 		}
-
-	// 		// for debugging purpose
-	// 		if dataExistsAlready {
-	// 			utils.Debug("Data already exists in output table, skipping processing")
-	// 		} else {
-	// 			utils.Debug("Data does not exist in output table, inserted new record")
-	// 		}
-	// 		deleted, err := deleteMessage(ctx, sqsClient, queueURL, msg, data)
-	// 		if !deleted {
-	// 			utils.Error(fmt.Errorf("failed to delete message after successful processing check: %v", err))
-	// 		}
-	// 		return true, deleted // message processed
-	// 	}
-
-	// 	// If we have an error message (and no transactionId), skip processing
-	// 	if errorMessage != "" && transactionId == "" {
-	// 		utils.Debug(fmt.Sprintf("Message already processed for redisKey: %s with error: %s, skipping", redisKey, errorMessage))
-	// 		deleted, err := deleteMessage(ctx, sqsClient, queueURL, msg, data)
-	// 		if !deleted {
-	// 			utils.Error(fmt.Errorf("failed to delete message after error processing check: %v", err))
-	// 		}
-	// 		return true, deleted // message processed
-	// 	}
-
-	// 	// Redis key exists but no transactionId or errorMessage - new message
-	// 	// Delete it to prevent reprocessing
-	// 	// utils.Debug(fmt.Sprintf("Message already processed for redisKey: %s (key exists but no transactionId/errorMessage), deleting", redisKey))
-	// 	// deleteMessage(ctx, sqsClient, queueURL, msg, data)
-	// 	// return true // message processed
-	// }
-
-	// // If not exists, add key with blank value
-	// err = redis.SetMobileChannelKey(redis.RDB, config.Configs.CommIdempotentKey, redisKey)
-	// if err != nil {
-	// 	utils.Error(fmt.Errorf("redis add failed: %v", err))
-	// }
-
-	utils.Debug(fmt.Sprintf("Payload: %+v", data))
+	utils.DebugCtx(ctx, fmt.Sprintf("Payload: %+v", data))
 
 	data.Client = strings.ToLower(data.Client)
 	data.Channel = strings.ToUpper(data.Channel)
 	data.ProcessName = strings.ToUpper(data.ProcessName)
 	data.AzureIdempotencyKey = fmt.Sprintf("%s_%s", strings.ToLower(data.ProcessName), strings.ToLower(data.Description))
+	ctx = utils.WithMessage(ctx, data.CommId, data.Client, data.Channel, data.Stage)
+	ctx, span := tracing.Start(trace.ContextWithRemoteSpanContext(ctx, msgWrapper.SpanContext), "consumer.process", trace.SpanKindConsumer,
+		tracing.MessageAttributes(data.CommId, data.Client, data.Channel, data.Stage)...)
+	// The failures below are logged where they happen; the span is marked failed for every message not processed
+	var spanErr error
+	defer func() {
+		if spanErr == nil && !isMessageProcessed {
+			spanErr = fmt.Errorf("%s message was not processed", data.Channel)
+		}
+		tracing.End(span, spanErr)
+	}()
 
 	dbMappedData, err := dbservices.MapIntoDbModel(data)
 	if err != nil {
-		utils.Error(fmt.Errorf("error in mapping data into dbModel: %v", err))
+		spanErr = fmt.Errorf("error in mapping data into dbModel: %v", err)
+		utils.ErrorCtx(ctx, spanErr)
 		// Data mapping error is likely permanent - delete message to prevent infinite retries
 		// But log it for investigation
 		deleted, delErr := deleteMessage(ctx, sqsClient, queueURL, msg, data)
 		if !deleted {
-			utils.Error(fmt.Errorf("failed to delete message after mapping error: %v", delErr))
+			utils.ErrorCtx(ctx, fmt.Errorf("failed to delete message after mapping error: %v", delErr))
 		}
 		return false, deleted // Return false to indicate processing failed
 	}
 
-	utils.Debug(fmt.Sprintf("[Client:%s CommId:%s] Processing %s", data.Client, data.CommId, data.Channel))
+	utils.DebugCtx(ctx, fmt.Sprintf("Processing %s", data.Channel))
 
+	// A redelivered message must not reach the vendor again, so the CommId is claimed before it is sent
+	if data.CommId == "" {
+		return sendToChannel(ctx, data, dbMappedData, sqsClient, queueURL, msg)
+	}
+	owner, claimed, isMessageProcessed, deleted := claimMessage(ctx, sqsClient, queueURL, msg, data)
+	if !claimed {
+		return isMessageProcessed, deleted
+	}
+	isMessageProcessed, deleted = sendToChannel(ctx, data, dbMappedData, sqsClient, queueURL, msg)
+	releaseMessage(ctx, data, owner, isMessageProcessed)
+	return isMessageProcessed, deleted
+}
+
+// sendToChannel sends the message through the service of its channel.
+func sendToChannel(ctx context.Context, data sdkModels.CommApiRequestBody, dbMappedData map[string]interface{}, sqsClient *sqs.SQS, queueURL string, msg *sqs.Message) (bool, bool) {
 	switch data.Channel {
 	case variables.WhatsApp:
 		isMessageProcessed, deleted := handleWhatsapp(ctx, data, dbMappedData, sqsClient, queueURL, msg)
@@ -303,11 +304,11 @@ func processMessage(ctx context.Context, sqsClient *sqs.SQS, queueURL string, ms
 		isMessageProcessed, deleted := handleEmail(ctx, data, dbMappedData, sqsClient, queueURL, msg)
 		return isMessageProcessed, deleted
 	default:
-		utils.Error(fmt.Errorf("[Client:%s CommId:%s] invalid channel: %s", data.Client, data.CommId, data.Channel))
+		utils.ErrorCtx(ctx, fmt.Errorf("invalid channel: %s", data.Channel))
 		// Delete invalid messages to prevent unnecessary retries
 		deleted, err := deleteMessage(ctx, sqsClient, queueURL, msg, data)
 		if !deleted {
-			utils.Error(fmt.Errorf("failed to delete message with invalid channel: %v", err))
+			utils.ErrorCtx(ctx, fmt.Errorf("failed to delete message with invalid channel: %v", err))
 		}
 		return true, deleted // message processed (rejected due to invalid channel)
 	}
@@ -318,46 +319,46 @@ func handleWhatsapp(ctx context.Context, data sdkModels.CommApiRequestBody, dbMa
 	// 	utils.Error(fmt.Errorf("error inserting data into wp input table for mobile %s: %v", data.Mobile, err))
 	// }
 
-	maxCountInt, _ := strconv.Atoi(config.Configs.CreditSeaWhatsappMaxCount)
-	if data.Client == variables.CreditSea {
-		count, err := redis.GetCreditSeaCounter(context.Background(), redis.RDB, redis.CreditSeaWhatsappCount)
+	AssignVendor(&data)
+	if limit := channelHelper.DailyLimit(data.Client, data.Channel); limit > 0 {
+		count, err := redis.GetDailyCount(ctx, redis.RDB, data.Client, data.Channel)
 		if err != nil {
-			utils.Error(fmt.Errorf("redis error: %v", err))
+			utils.ErrorCtx(ctx, fmt.Errorf("redis error: %v", err))
 		}
-		data.Vendor = variables.SINCH
-		if count > maxCountInt {
-			utils.Error(fmt.Errorf("CreditSea Whatsapp count exceeded: current count:%d, maxCount:%d", count, maxCountInt))
-			if err := database.InsertData(config.Configs.WhatsappOutputTable, database.DBtechWrite, map[string]interface{}{
+		if count >= limit {
+			utils.ErrorCtx(ctx, fmt.Errorf("daily whatsapp limit of client %s reached: current count:%d, limit:%d", data.Client, count, limit))
+			limitExceededData := map[string]interface{}{
 				"CommId":          data.CommId,
 				"Vendor":          data.Vendor,
 				"MobileNumber":    data.Mobile,
 				"IsSent":          false,
-				"ResponseMessage": fmt.Sprintf("CreditSea whatsapp limit exceeeded. Message not sent for commid: %s", data.CommId),
-			}); err != nil {
-				utils.Error(fmt.Errorf("error inserting data into wp output table for mobile %s: %v", data.Mobile, err))
+				"ResponseMessage": fmt.Sprintf("Daily whatsapp limit of %s reached. Message not sent for commid: %s", data.Client, data.CommId),
 			}
+			if err := channelHelper.InsertOutput(ctx, config.Configs.WhatsappOutputTable, data.CommId, limitExceededData); err != nil {
+				utils.ErrorCtx(ctx, fmt.Errorf("error inserting data into wp output table for mobile %s: %v", data.Mobile, err))
+			}
+			webhookService.Emit(data, limitExceededData)
 			deleted, err := deleteMessage(ctx, sqsClient, queueURL, msg, data)
 			if !deleted {
-				utils.Error(fmt.Errorf("failed to delete message after CreditSea limit exceeded: %v", err))
+				utils.ErrorCtx(ctx, fmt.Errorf("failed to delete message after daily whatsapp limit reached: %v", err))
 			}
-			return true, deleted // message processed but not sent as CreditSea whatsapp limit exceeeded
+			return true, deleted // message processed but not sent as the daily whatsapp limit is reached
 		}
-	} else {
-		data.Vendor = GetVendorByClientAndChannel(data.Channel, data.Client, data.CommId)
 	}
 	var deleted bool
 	var delErr error
 
-	isMessageProcessed, dbMappedData, err := whatsapp.SendWpByProcess(data)
+	// A send in flight is finished on shutdown, so its result is recorded and the message deleted
+	isMessageProcessed, dbMappedData, err := whatsapp.SendWpByProcess(context.WithoutCancel(ctx), data)
 	if err != nil {
-		utils.Error(fmt.Errorf("error in sending whatsapp: %v", err))
+		utils.ErrorCtx(ctx, fmt.Errorf("error in sending whatsapp: %v", err))
 		// If processing failed, don't delete message - let it retry after visibility timeout
 		// However, if isMessageProcessed is true (partial success), we should delete to prevent duplicates
 
 		if isMessageProcessed {
 			deleted, delErr = deleteMessage(ctx, sqsClient, queueURL, msg, data)
 			if !deleted {
-				utils.Error(fmt.Errorf("failed to delete message after partial whatsapp processing: %v", delErr))
+				utils.ErrorCtx(ctx, fmt.Errorf("failed to delete message after partial whatsapp processing: %v", delErr))
 			}
 		}
 		return isMessageProcessed, deleted
@@ -367,13 +368,14 @@ func handleWhatsapp(ctx context.Context, data sdkModels.CommApiRequestBody, dbMa
 	if isMessageProcessed {
 		deleted, err = deleteMessage(ctx, sqsClient, queueURL, msg, data)
 		if !deleted {
-			utils.Error(fmt.Errorf("failed to delete message after successful whatsapp processing: %v", err))
+			utils.ErrorCtx(ctx, fmt.Errorf("failed to delete message after successful whatsapp processing: %v", err))
 		}
 	}
 
-	if err := database.InsertData(config.Configs.WhatsappOutputTable, database.DBtechWrite, dbMappedData); err != nil {
-		utils.Error(fmt.Errorf("error inserting data into wp output table for mobile %s: %v", data.Mobile, err))
+	if err := channelHelper.InsertOutput(ctx, config.Configs.WhatsappOutputTable, data.CommId, dbMappedData); err != nil {
+		utils.ErrorCtx(ctx, fmt.Errorf("error inserting data into wp output table for mobile %s: %v", data.Mobile, err))
 	}
+	webhookService.Emit(data, dbMappedData)
 
 	return isMessageProcessed, deleted
 
@@ -386,16 +388,16 @@ func handleRCS(ctx context.Context, data sdkModels.CommApiRequestBody, dbMappedD
 	var deleted bool
 	var delErr error
 	AssignVendor(&data)
-	isMessageProcessed, err := rcs.SendRcsByProcess(data)
+	isMessageProcessed, err := rcs.SendRcsByProcess(context.WithoutCancel(ctx), data)
 	if err != nil {
-		utils.Error(fmt.Errorf("[Client:%s CommId:%s] error in sending RCS: %v", data.Client, data.CommId, err))
+		utils.ErrorCtx(ctx, fmt.Errorf("error in sending RCS: %v", err))
 		// If processing failed, don't delete message - let it retry after visibility timeout
 		// However, if isMessageProcessed is true (partial success), we should delete to prevent duplicates
 
 		if isMessageProcessed {
 			deleted, delErr = deleteMessage(ctx, sqsClient, queueURL, msg, data)
 			if !deleted {
-				utils.Error(fmt.Errorf("failed to delete message after partial RCS processing: %v", delErr))
+				utils.ErrorCtx(ctx, fmt.Errorf("failed to delete message after partial RCS processing: %v", delErr))
 			}
 		}
 		return isMessageProcessed, deleted
@@ -404,7 +406,7 @@ func handleRCS(ctx context.Context, data sdkModels.CommApiRequestBody, dbMappedD
 	if isMessageProcessed {
 		deleted, err := deleteMessage(ctx, sqsClient, queueURL, msg, data)
 		if !deleted {
-			utils.Error(fmt.Errorf("failed to delete message after successful RCS processing: %v", err))
+			utils.ErrorCtx(ctx, fmt.Errorf("failed to delete message after successful RCS processing: %v", err))
 		}
 	}
 
@@ -418,15 +420,15 @@ func handleSMS(ctx context.Context, data sdkModels.CommApiRequestBody, dbMappedD
 	var deleted bool
 	var delErr error
 	AssignVendor(&data)
-	isMessageProcessed, dbMappedData, err := sms.SendSmsByProcess(data)
+	isMessageProcessed, dbMappedData, err := sms.SendSmsByProcess(context.WithoutCancel(ctx), data)
 	if err != nil {
-		utils.Error(fmt.Errorf("[Client:%s CommId:%s] error in sending SMS: %v", data.Client, data.CommId, err))
+		utils.ErrorCtx(ctx, fmt.Errorf("error in sending SMS: %v", err))
 		// If processing failed, don't delete message - let it retry after visibility timeout
 		// However, if isMessageProcessed is true (partial success), we should delete to prevent duplicates
 		if isMessageProcessed {
 			deleted, delErr = deleteMessage(ctx, sqsClient, queueURL, msg, data)
 			if !deleted {
-				utils.Error(fmt.Errorf("failed to delete message after partial SMS processing: %v", delErr))
+				utils.ErrorCtx(ctx, fmt.Errorf("failed to delete message after partial SMS processing: %v", delErr))
 			}
 		}
 		return isMessageProcessed, deleted
@@ -435,13 +437,14 @@ func handleSMS(ctx context.Context, data sdkModels.CommApiRequestBody, dbMappedD
 	if isMessageProcessed {
 		deleted, err = deleteMessage(ctx, sqsClient, queueURL, msg, data)
 		if !deleted {
-			utils.Error(fmt.Errorf("failed to delete message after successful SMS processing: %v", err))
+			utils.ErrorCtx(ctx, fmt.Errorf("failed to delete message after successful SMS processing: %v", err))
 		}
 	}
 
-	if err := database.InsertData(config.Configs.SmsOutputTable, database.DBtechWrite, dbMappedData); err != nil {
-		utils.Error(fmt.Errorf("error inserting data into sms output table for mobile %s: %v", data.Mobile, err))
+	if err := channelHelper.InsertOutput(ctx, config.Configs.SmsOutputTable, data.CommId, dbMappedData); err != nil {
+		utils.ErrorCtx(ctx, fmt.Errorf("error inserting data into sms output table for mobile %s: %v", data.Mobile, err))
 	}
+	webhookService.Emit(data, dbMappedData)
 
 	return isMessageProcessed, deleted
 }
@@ -456,15 +459,15 @@ func handleEmail(ctx context.Context, data sdkModels.CommApiRequestBody, dbMappe
 	var deleted bool
 	var delErr error
 	AssignVendor(&data)
-	isMessageProcessed, dbMappedData, err := email.SendEmailByProcess(data)
+	isMessageProcessed, dbMappedData, err := email.SendEmailByProcess(context.WithoutCancel(ctx), data)
 	if err != nil {
-		utils.Error(fmt.Errorf("[Client:%s CommId:%s] error in sending Email: %v", data.Client, data.CommId, err))
+		utils.ErrorCtx(ctx, fmt.Errorf("error in sending Email: %v", err))
 		// If processing failed, don't delete message - let it retry after visibility timeout
 		// However, if isMessageProcessed is true (partial success), we should delete to prevent duplicates
 		if isMessageProcessed {
 			deleted, delErr = deleteMessage(ctx, sqsClient, queueURL, msg, data)
 			if !deleted {
-				utils.Error(fmt.Errorf("failed to delete message after partial Email processing: %v", delErr))
+				utils.ErrorCtx(ctx, fmt.Errorf("failed to delete message after partial Email processing: %v", delErr))
 			}
 		}
 		return isMessageProcessed, deleted
@@ -473,16 +476,17 @@ func handleEmail(ctx context.Context, data sdkModels.CommApiRequestBody, dbMappe
 	if isMessageProcessed {
 		deleted, err = deleteMessage(ctx, sqsClient, queueURL, msg, data)
 		if !deleted {
-			utils.Error(fmt.Errorf("failed to delete message after successful Email processing: %v", err))
+			utils.ErrorCtx(ctx, fmt.Errorf("failed to delete message after successful Email processing: %v", err))
 		}
 	}
 
 	delete(dbMappedData, "MobileNumber")
 	dbMappedData["Email"] = data.Email
 
-	if err := database.InsertData(config.Configs.EmailOutputTable, database.DBtechWrite, dbMappedData); err != nil {
-		utils.Error(fmt.Errorf("error inserting data into table: %v", err))
+	if err := channelHelper.InsertOutput(ctx, config.Configs.EmailOutputTable, data.CommId, dbMappedData); err != nil {
+		utils.ErrorCtx(ctx, fmt.Errorf("error inserting data into table: %v", err))
 	}
+	webhookService.Emit(data, dbMappedData)
 
 	return isMessageProcessed, deleted
 }
@@ -507,6 +511,7 @@ func deleteMessage(ctx context.Context, sqsClient *sqs.SQS, queueURL string, msg
 			ReceiptHandle: msg.ReceiptHandle,
 		})
 		if err == nil {
+			metrics.MessagesDeleted.WithLabelValues(strings.ToLower(data.Client), strings.ToUpper(data.Channel)).Inc()
 			utils.Info(fmt.Sprintf("[Client:%s CommId:%s] Message successfully deleted from SQS on attempt %d", data.Client, data.CommId, i))
 			return true, nil
 		}
@@ -531,11 +536,17 @@ func deleteMessage(ctx context.Context, sqsClient *sqs.SQS, queueURL string, msg
 	return false, err
 }
 
+// AssignVendor picks the vendor of the message. A client with vendor accounts of its own for the channel is only
+// routed to those vendors, as its messages cannot go out through another vendor's shared account.
 func AssignVendor(data *sdkModels.CommApiRequestBody) {
-	if data.Client == variables.CreditSea || data.Channel == variables.Email {
+	if data.Channel == variables.Email {
 		data.Vendor = variables.SINCH
 	} else {
 		data.Vendor = GetVendorByClientAndChannel(data.Channel, data.Client, data.CommId)
+		if own := cache.Current().ClientAccountVendors(data.Client, data.Channel); len(own) > 0 && !slices.Contains(own, data.Vendor) {
+			utils.Debug(fmt.Sprintf("client %s has no %s account at %s, using %s", data.Client, data.Channel, data.Vendor, own[0]))
+			data.Vendor = own[0]
+		}
 		utils.Debug(fmt.Sprintf("Assigned vendor: %s for client: %s, channel: %s, commId: %s", data.Vendor, data.Client, data.Channel, data.CommId))
 	}
 }
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/wecredit/communication-sdk/config"
+	"github.com/wecredit/communication-sdk/internal/metrics"
+	"github.com/wecredit/communication-sdk/internal/models/awsModels"
+	"github.com/wecredit/communication-sdk/internal/snsAuth"
+	"github.com/wecredit/communication-sdk/sdk/models/sdkModels"
+	"github.com/wecredit/communication-sdk/sdk/queue"
+	"github.com/wecredit/communication-sdk/sdk/utils"
+	"github.com/wecredit/communication-sdk/sdk/variables"
+)
+
+// newMessageVerifier builds the verifier of consumed messages from the SNS_ and COMM_MESSAGE_ settings.
+func newMessageVerifier() (*snsAuth.Verifier, error) {
+	keys, err := snsAuth.ParseSigningKeys(config.Configs.CommMessageSigningKeys)
+	if err != nil {
+		return nil, fmt.Errorf("invalid COMM_MESSAGE_SIGNING_KEYS: %v", err)
+	}
+
+	hosts := strings.Split(config.Configs.SnsSigningCertHosts, ",")
+	if strings.TrimSpace(config.Configs.SnsSigningCertHosts) == "" {
+		hosts = []string{fmt.Sprintf("sns.%s.amazonaws.com", config.Configs.AWSRegion)}
+	}
+
+	verifySNS := enabled(config.Configs.SnsVerifySignatures)
+	if !verifySNS {
+		utils.Warn("SNS_VERIFY_SIGNATURES is off: SNS signatures of consumed messages are not checked")
+	}
+	if !keys.Configured() {
+		utils.Warn("COMM_MESSAGE_SIGNING_KEYS is not set: consumed messages are accepted without the producer signature")
+	}
+	required, _ := strconv.ParseBool(strings.TrimSpace(config.Configs.CommMessageSignatureRequired))
+	if keys.Configured() && !required {
+		utils.Warn("COMM_MESSAGE_SIGNATURE_REQUIRED is off: consumed messages without a valid producer signature are only logged")
+	}
+	// A message is remembered by its CommId claim for the claim retention, so older ones could be replayed
+	_, retention := claimSettings()
+	return snsAuth.NewVerifier(verifySNS, hosts, keys, required, retention), nil
+}
+
+// enabled reads a boolean setting; anything but a false value keeps the check on.
+func enabled(value string) bool {
+	on, err := strconv.ParseBool(strings.TrimSpace(value))
+	return err != nil || on
+}
+
+// rejectMessage moves a message that failed verification to the error queue and deletes it. A message that could
+// not be verified yet, e.g. because the SNS certificate could not be downloaded, is left for redelivery instead.
+func rejectMessage(ctx context.Context, msg *sqs.Message, queueURL string, snsWrapper awsModels.SnsMessageWrapper, data sdkModels.CommApiRequestBody, err error) {
+	messageId := aws.StringValue(msg.MessageId)
+	if !errors.Is(err, snsAuth.ErrUntrusted) {
+		utils.Error(fmt.Errorf("could not verify SQS message %s, leaving it for redelivery: %v", messageId, err))
+		return
+	}
+
+	metrics.MessagesRejected.Inc()
+	utils.Error(fmt.Errorf("rejected SQS message %s from topic %s claiming CommId %s of client %s: %v", messageId, snsWrapper.TopicArn, data.CommId, data.Client, err))
+	if queueErr := queue.SendMessageWithSubject(queue.SQSClient, snsWrapper, config.Configs.AwsErrorQueueUrl, variables.UntrustedMessage, err.Error()); queueErr != nil {
+		utils.Error(fmt.Errorf("failed to move rejected SQS message %s to the error queue, leaving it for redelivery: %v", messageId, queueErr))
+		return
+	}
+	if deleted, delErr := deleteMessage(ctx, queue.SQSClient, queueURL, msg, data); !deleted {
+		utils.Error(fmt.Errorf("failed to delete rejected SQS message %s: %v", messageId, delErr))
+	}
+}
@@ -0,0 +1,136 @@
+package webhookService
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/wecredit/communication-sdk/config"
+	"github.com/wecredit/communication-sdk/internal/redis"
+	"github.com/wecredit/communication-sdk/sdk/utils"
+)
+
+// publishStatusEvent broadcasts the event to every pod, so status subscribers receive it wherever they are connected.
+func publishStatusEvent(event StatusEvent) {
+	if redis.RDB == nil {
+		return
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		utils.Error(fmt.Errorf("[Client:%s CommId:%s] failed to marshal status event: %v", event.Client, event.CommId, err))
+		return
+	}
+	if err := redis.RDB.Publish(context.Background(), config.Configs.StatusEventsChannel, payload).Err(); err != nil {
+		utils.Error(fmt.Errorf("[Client:%s CommId:%s] failed to publish status event: %v", event.Client, event.CommId, err))
+	}
+}
+
+// subscriberBuffer is the number of events a subscriber may fall behind by before its events are dropped.
+const subscriberBuffer = 64
+
+type statusSubscriber struct {
+	client  string
+	channel string
+	events  chan StatusEvent
+}
+
+// statusHub hands the events of the single Redis subscription of the pod to every status subscriber.
+var statusHub = struct {
+	sync.Mutex
+	running     bool
+	subscribers map[*statusSubscriber]struct{}
+}{subscribers: map[*statusSubscriber]struct{}{}}
+
+// StartStatusEventsSubscriber subscribes the pod to the status events channel once and fans the events out to the
+// streams of SubscribeStatusEvents until ctx is cancelled.
+func StartStatusEventsSubscriber(ctx context.Context) {
+	if redis.RDB == nil {
+		utils.Error(fmt.Errorf("status events subscriber not started: redis client not initialized"))
+		return
+	}
+
+	pubsub := redis.RDB.Subscribe(ctx, config.Configs.StatusEventsChannel)
+	defer pubsub.Close()
+	if _, err := pubsub.Receive(ctx); err != nil {
+		utils.Error(fmt.Errorf("failed to subscribe to status events: %v", err))
+		return
+	}
+
+	statusHub.Lock()
+	statusHub.running = true
+	statusHub.Unlock()
+	defer stopStatusHub()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case message, ok := <-messages:
+			if !ok {
+				return
+			}
+			var event StatusEvent
+			if err := json.Unmarshal([]byte(message.Payload), &event); err != nil {
+				utils.Error(fmt.Errorf("invalid status event %q: %v", message.Payload, err))
+				continue
+			}
+			dispatchStatusEvent(event)
+		}
+	}
+}
+
+// dispatchStatusEvent hands event to its subscribers without waiting, so one slow stream never holds up the others.
+func dispatchStatusEvent(event StatusEvent) {
+	statusHub.Lock()
+	defer statusHub.Unlock()
+	for subscriber := range statusHub.subscribers {
+		if !strings.EqualFold(event.Client, subscriber.client) || (subscriber.channel != "" && !strings.EqualFold(event.Channel, subscriber.channel)) {
+			continue
+		}
+		select {
+		case subscriber.events <- event:
+		default:
+			utils.Warn(fmt.Sprintf("[Client:%s CommId:%s] status subscriber is too slow, dropped the %s event", event.Client, event.CommId, event.Event))
+		}
+	}
+}
+
+// stopStatusHub ends every stream when the subscription of the pod ends.
+func stopStatusHub() {
+	statusHub.Lock()
+	defer statusHub.Unlock()
+	statusHub.running = false
+	for subscriber := range statusHub.subscribers {
+		close(subscriber.events)
+		delete(statusHub.subscribers, subscriber)
+	}
+}
+
+// SubscribeStatusEvents streams the status events of client, optionally limited to one channel, until ctx is cancelled.
+// Events published while nobody is subscribed are not replayed.
+func SubscribeStatusEvents(ctx context.Context, client, channel string) (<-chan StatusEvent, error) {
+	subscriber := &statusSubscriber{client: client, channel: channel, events: make(chan StatusEvent, subscriberBuffer)}
+
+	statusHub.Lock()
+	if !statusHub.running {
+		statusHub.Unlock()
+		return nil, fmt.Errorf("status events subscriber is not running")
+	}
+	statusHub.subscribers[subscriber] = struct{}{}
+	statusHub.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		statusHub.Lock()
+		defer statusHub.Unlock()
+		if _, ok := statusHub.subscribers[subscriber]; ok {
+			close(subscriber.events)
+			delete(statusHub.subscribers, subscriber)
+		}
+	}()
+	return subscriber.events, nil
+}
@@ -0,0 +1,126 @@
+package webhookService
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/wecredit/communication-sdk/config"
+	"github.com/wecredit/communication-sdk/internal/models/apiModels"
+	"github.com/wecredit/communication-sdk/internal/secrets"
+	"github.com/wecredit/communication-sdk/pkg/cache"
+	"github.com/wecredit/communication-sdk/sdk/utils"
+	"gorm.io/gorm"
+)
+
+// openedSecrets holds opened webhook secrets keyed by their ciphertext, which changes whenever the secret does.
+var openedSecrets sync.Map
+
+// SealSecret encrypts secret into the webhook secret columns of the client; the envelope is bound to the client's
+// id. The plaintext column is only kept while WEBHOOK_CLEAR_PLAINTEXT_SECRETS is unset, for pods that still read it.
+func SealSecret(client *apiModels.Client, secret string) error {
+	envelope, err := secrets.Seal([]byte(secret), secretAad(client.Id))
+	if err != nil {
+		return fmt.Errorf("failed to encrypt the webhook secret: %w", err)
+	}
+	client.WebhookSecretEncrypted = envelope.Ciphertext
+	client.WebhookSecretWrappedKey = envelope.WrappedKey
+	client.WebhookSecretKeyId = envelope.KeyId
+	client.WebhookSecret = ""
+	if !clearPlaintextSecrets() {
+		client.WebhookSecret = secret
+	}
+	return nil
+}
+
+// OpenSecret returns the webhook secret of the client, from its envelope or, until it is sealed, its plaintext column.
+func OpenSecret(client apiModels.Client) (string, error) {
+	if client.WebhookSecretEncrypted == "" {
+		return client.WebhookSecret, nil
+	}
+	if opened, ok := openedSecrets.Load(client.WebhookSecretEncrypted); ok {
+		return opened.(string), nil
+	}
+
+	envelope := secrets.Envelope{
+		Ciphertext: client.WebhookSecretEncrypted,
+		WrappedKey: client.WebhookSecretWrappedKey,
+		KeyId:      client.WebhookSecretKeyId,
+	}
+	plaintext, err := secrets.Open(envelope, secretAad(client.Id))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt the webhook secret of client %d: %w", client.Id, err)
+	}
+	openedSecrets.Store(client.WebhookSecretEncrypted, string(plaintext))
+	return string(plaintext), nil
+}
+
+// SealPlaintextSecrets seals the webhook secrets still stored in plaintext, and clears the plaintext of sealed
+// secrets once WEBHOOK_CLEAR_PLAINTEXT_SECRETS is set.
+func SealPlaintextSecrets(db *gorm.DB) error {
+	var rows []apiModels.Client
+	err := db.Table(config.Configs.ClientsTable).
+		Where("WebhookSecret IS NOT NULL AND WebhookSecret <> '' AND (WebhookSecretEncrypted IS NULL OR WebhookSecretEncrypted = '')").
+		Find(&rows).Error
+	if err != nil {
+		return fmt.Errorf("failed to read plaintext webhook secrets: %w", err)
+	}
+
+	sealed := int64(0)
+	for _, row := range rows {
+		secret := row.WebhookSecret
+		if err := SealSecret(&row, secret); err != nil {
+			utils.Error(fmt.Errorf("failed to seal the webhook secret of client %d: %v", row.Id, err))
+			continue
+		}
+		// Only a row still holding the plaintext that was sealed is updated
+		result := db.Table(config.Configs.ClientsTable).
+			Where("Id = ? AND WebhookSecret = ? AND (WebhookSecretEncrypted IS NULL OR WebhookSecretEncrypted = '')", row.Id, secret).
+			Updates(map[string]interface{}{
+				"WebhookSecret":           row.WebhookSecret,
+				"WebhookSecretEncrypted":  row.WebhookSecretEncrypted,
+				"WebhookSecretWrappedKey": row.WebhookSecretWrappedKey,
+				"WebhookSecretKeyId":      row.WebhookSecretKeyId,
+				"UpdatedOn":               utils.IstNow(),
+			})
+		if result.Error != nil {
+			utils.Error(fmt.Errorf("failed to seal the webhook secret of client %d: %v", row.Id, result.Error))
+			continue
+		}
+		sealed += result.RowsAffected
+	}
+	if len(rows) > 0 {
+		utils.Info(fmt.Sprintf("Sealed %d of %d plaintext webhook secrets", sealed, len(rows)))
+	}
+
+	cleared := int64(0)
+	if clearPlaintextSecrets() {
+		result := db.Table(config.Configs.ClientsTable).
+			Where("WebhookSecretEncrypted IS NOT NULL AND WebhookSecretEncrypted <> '' AND WebhookSecret IS NOT NULL AND WebhookSecret <> ''").
+			Updates(map[string]interface{}{
+				"WebhookSecret": nil,
+				"UpdatedOn":     utils.IstNow(),
+			})
+		if result.Error != nil {
+			return fmt.Errorf("failed to clear plaintext webhook secrets: %w", result.Error)
+		}
+		cleared = result.RowsAffected
+		if cleared > 0 {
+			utils.Info(fmt.Sprintf("Cleared the plaintext webhook secret of %d clients", cleared))
+		}
+	}
+
+	if sealed > 0 || cleared > 0 {
+		cache.Refresh(cache.ClientsData, db)
+	}
+	return nil
+}
+
+func clearPlaintextSecrets() bool {
+	clear, _ := strconv.ParseBool(config.Configs.WebhookClearPlaintextSecrets)
+	return clear
+}
+
+func secretAad(clientId int) []byte {
+	return []byte("webhook-secret\x00" + strconv.Itoa(clientId))
+}
@@ -0,0 +1,402 @@
+package webhookService
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wecredit/communication-sdk/config"
+	"github.com/wecredit/communication-sdk/internal/database"
+	"github.com/wecredit/communication-sdk/internal/models/apiModels"
+	"github.com/wecredit/communication-sdk/pkg/cache"
+	"github.com/wecredit/communication-sdk/sdk/models/sdkModels"
+	"github.com/wecredit/communication-sdk/sdk/utils"
+	"github.com/wecredit/communication-sdk/sdk/variables"
+	"gorm.io/gorm"
+)
+
+const (
+	SignatureHeader  = "X-Comm-Signature"
+	TimestampHeader  = "X-Comm-Timestamp"
+	EventHeader      = "X-Comm-Event"
+	DeliveryIdHeader = "X-Comm-Delivery-Id"
+
+	baseBackoff     = 30 * time.Second
+	maxBackoff      = time.Hour
+	inFlightTimeout = 5 * time.Minute
+	deliveryBatch   = 50
+)
+
+// StatusEvent is the body posted to a client's webhook when a message reaches a final state
+type StatusEvent struct {
+	Event           string  `json:"event"`
+	CommId          string  `json:"commId"`
+	Client          string  `json:"client"`
+	Channel         string  `json:"channel"`
+	ProcessName     string  `json:"processName"`
+	Stage           float64 `json:"stage"`
+	Vendor          string  `json:"vendor,omitempty"`
+	TransactionId   string  `json:"transactionId,omitempty"`
+	ResponseMessage string  `json:"responseMessage,omitempty"`
+	Timestamp       string  `json:"timestamp"`
+}
+
+type WebhookService struct {
+	DB *gorm.DB
+}
+
+func NewWebhookService(db *gorm.DB) *WebhookService {
+	return &WebhookService{DB: db}
+}
+
+// wake lets Emit trigger an immediate delivery pass instead of waiting for the next poll
+var wake = make(chan struct{}, 1)
+
+// NewStatusEvent builds a status event from the request and the output row written for it
+func NewStatusEvent(data sdkModels.CommApiRequestBody, dbMappedData map[string]interface{}) StatusEvent {
+	event := StatusEvent{
+		Event:       variables.WebhookEventFailed,
+		CommId:      data.CommId,
+		Client:      data.Client,
+		Channel:     data.Channel,
+		ProcessName: data.ProcessName,
+		Stage:       data.Stage,
+		Vendor:      data.Vendor,
+		Timestamp:   time.Now().UTC().Format(time.RFC3339),
+	}
+
+	switch isSent := dbMappedData["IsSent"].(type) {
+	case bool:
+		if isSent {
+			event.Event = variables.WebhookEventSent
+		}
+	case int:
+		if isSent == 1 {
+			event.Event = variables.WebhookEventSent
+		}
+	case int64:
+		if isSent == 1 {
+			event.Event = variables.WebhookEventSent
+		}
+	}
+
+	if vendor, ok := dbMappedData["Vendor"].(string); ok && vendor != "" {
+		event.Vendor = vendor
+	}
+	if transactionId, ok := dbMappedData["TransactionId"].(string); ok {
+		event.TransactionId = transactionId
+	}
+	if responseMessage, ok := dbMappedData["ResponseMessage"].(string); ok {
+		event.ResponseMessage = responseMessage
+	}
+	return event
+}
+
+// Emit publishes the event to status subscribers and records a delivery for it if the client has subscribed to it.
+// It never returns an error to the caller's send path; failures are only logged.
+func Emit(data sdkModels.CommApiRequestBody, dbMappedData map[string]interface{}) {
+	event := NewStatusEvent(data, dbMappedData)
+	publishStatusEvent(event)
+
+	if database.DBtechWrite == nil {
+		return
+	}
+	if err := NewWebhookService(database.DBtechWrite).Enqueue(event); err != nil {
+		utils.Error(fmt.Errorf("[Client:%s CommId:%s] failed to enqueue webhook event: %v", data.Client, data.CommId, err))
+	}
+}
+
+// Enqueue stores a pending delivery for the event when the client has a webhook configured for it
+func (s *WebhookService) Enqueue(event StatusEvent) error {
+	clientData, subscribed := clientWebhook(event.Client, event.Channel, event.Event)
+	if !subscribed {
+		return nil
+	}
+	url := clientData.WebhookUrl
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook event: %w", err)
+	}
+
+	now := utils.IstNow()
+	delivery := apiModels.WebhookDelivery{
+		CommId:        event.CommId,
+		Client:        event.Client,
+		Channel:       event.Channel,
+		EventType:     event.Event,
+		Url:           url,
+		Payload:       string(payload),
+		Status:        variables.WebhookPending,
+		NextAttemptOn: now,
+		CreatedOn:     now,
+	}
+	if err := s.DB.Table(config.Configs.WebhookDeliveryTable).Create(&delivery).Error; err != nil {
+		return fmt.Errorf("failed to insert webhook delivery: %w", err)
+	}
+
+	select {
+	case wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// clientWebhook returns the client row holding the webhook of a client and whether it subscribes to the event
+func clientWebhook(client, channel, event string) (apiModels.Client, bool) {
+	clientData, ok := cache.Current().Client(strings.ToLower(client), strings.ToUpper(channel))
+	if !ok || strings.TrimSpace(clientData.WebhookUrl) == "" {
+		return apiModels.Client{}, false
+	}
+
+	events := clientData.WebhookEvents
+	if strings.TrimSpace(events) == "" {
+		return clientData, true // no filter means every event
+	}
+	for _, e := range strings.Split(events, ",") {
+		if strings.EqualFold(strings.TrimSpace(e), event) {
+			return clientData, true
+		}
+	}
+	return clientData, false
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of "<timestamp>.<payload>" using the client's secret
+func Sign(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// StartDeliveryWorker delivers pending webhooks until the context is cancelled
+func StartDeliveryWorker(ctx context.Context, db *gorm.DB) {
+	pollInterval := time.Duration(atoiOrDefault(config.Configs.WebhookPollIntervalSeconds, 5)) * time.Second
+	s := NewWebhookService(db)
+
+	utils.Info(fmt.Sprintf("Starting webhook delivery worker with poll interval %s", pollInterval))
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			utils.Warn("Context cancelled. Stopping webhook delivery worker.")
+			return
+		case <-ticker.C:
+		case <-wake:
+		}
+		s.deliverDue(ctx)
+	}
+}
+
+func (s *WebhookService) deliverDue(ctx context.Context) {
+	now := utils.IstNow()
+
+	var deliveries []apiModels.WebhookDelivery
+	err := s.DB.Table(config.Configs.WebhookDeliveryTable).
+		Where("(Status = ? AND NextAttemptOn <= ?) OR (Status = ? AND UpdatedOn <= ?)",
+			variables.WebhookPending, now, variables.WebhookInFlight, now.Add(-inFlightTimeout)).
+		Order("NextAttemptOn").
+		Limit(deliveryBatch).
+		Find(&deliveries).Error
+	if err != nil {
+		utils.Error(fmt.Errorf("failed to fetch due webhook deliveries: %v", err))
+		return
+	}
+
+	for _, delivery := range deliveries {
+		if ctx.Err() != nil {
+			return
+		}
+		if !s.claim(delivery) {
+			continue // picked up by another pod
+		}
+		s.deliver(ctx, delivery)
+	}
+}
+
+// claim moves a delivery to IN_FLIGHT only if nobody else changed it since it was read
+func (s *WebhookService) claim(delivery apiModels.WebhookDelivery) bool {
+	result := s.DB.Table(config.Configs.WebhookDeliveryTable).
+		Where("Id = ? AND Status = ? AND Attempts = ?", delivery.Id, delivery.Status, delivery.Attempts).
+		Updates(map[string]interface{}{
+			"Status":    variables.WebhookInFlight,
+			"UpdatedOn": utils.IstNow(),
+		})
+	if result.Error != nil {
+		utils.Error(fmt.Errorf("failed to claim webhook delivery %d: %v", delivery.Id, result.Error))
+		return false
+	}
+	return result.RowsAffected == 1
+}
+
+// deliver posts the delivery to the webhook the client has now, so a removed webhook or unsubscribed event is not
+// posted and a changed url is honoured. A payload is never sent without a secret to sign it with.
+func (s *WebhookService) deliver(ctx context.Context, delivery apiModels.WebhookDelivery) {
+	clientData, subscribed := clientWebhook(delivery.Client, delivery.Channel, delivery.EventType)
+	if !subscribed {
+		s.record(delivery, 0, errors.New("client no longer has a webhook subscribed to the event"), true)
+		return
+	}
+	secret, err := OpenSecret(clientData)
+	if err == nil && secret == "" {
+		err = errors.New("client has no webhook secret")
+	}
+	if err != nil {
+		s.record(delivery, 0, err, false)
+		return
+	}
+
+	delivery.Url = clientData.WebhookUrl
+	statusCode, err := post(ctx, delivery, secret)
+	s.record(delivery, statusCode, err, false)
+}
+
+// record stores the outcome of an attempt: delivered, retried later, or FAILED once permanent or out of attempts.
+func (s *WebhookService) record(delivery apiModels.WebhookDelivery, statusCode int, err error, permanent bool) {
+	attempts := delivery.Attempts + 1
+	now := utils.IstNow()
+
+	updates := map[string]interface{}{
+		"Url":            delivery.Url,
+		"Attempts":       attempts,
+		"LastStatusCode": statusCode,
+		"UpdatedOn":      now,
+	}
+
+	if err == nil {
+		updates["Status"] = variables.WebhookDelivered
+		updates["LastError"] = ""
+		updates["DeliveredOn"] = now
+		utils.Info(fmt.Sprintf("[Client:%s CommId:%s] webhook %s delivered on attempt %d", delivery.Client, delivery.CommId, delivery.EventType, attempts))
+	} else {
+		updates["LastError"] = err.Error()
+		if permanent || attempts >= atoiOrDefault(config.Configs.WebhookMaxAttempts, 8) {
+			updates["Status"] = variables.WebhookFailed
+			utils.Error(fmt.Errorf("[Client:%s CommId:%s] webhook delivery %d failed permanently after %d attempts: %v", delivery.Client, delivery.CommId, delivery.Id, attempts, err))
+		} else {
+			updates["Status"] = variables.WebhookPending
+			updates["NextAttemptOn"] = now.Add(backoff(attempts))
+			utils.Warn(fmt.Sprintf("[Client:%s CommId:%s] webhook delivery %d attempt %d failed, retrying in %s: %v", delivery.Client, delivery.CommId, delivery.Id, attempts, backoff(attempts), err))
+		}
+	}
+
+	if err := s.DB.Table(config.Configs.WebhookDeliveryTable).Where("Id = ?", delivery.Id).Updates(updates).Error; err != nil {
+		utils.Error(fmt.Errorf("failed to update webhook delivery %d: %v", delivery.Id, err))
+	}
+}
+
+func post(ctx context.Context, delivery apiModels.WebhookDelivery, secret string) (int, error) {
+	timeout := time.Duration(atoiOrDefault(config.Configs.WebhookTimeoutSeconds, 10)) * time.Second
+	reqCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	payload := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodPost, delivery.Url, bytes.NewReader(payload))
+	if err != nil {
+		return 0, fmt.Errorf("error creating webhook request: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(DeliveryIdHeader, strconv.Itoa(delivery.Id))
+	req.Header.Set(SignatureHeader, "sha256="+Sign(secret, timestamp, payload))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("error sending webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook responded with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff doubles the wait after every failed attempt, capped at maxBackoff
+func backoff(attempts int) time.Duration {
+	wait := baseBackoff
+	for i := 1; i < attempts; i++ {
+		wait *= 2
+		if wait >= maxBackoff {
+			return maxBackoff
+		}
+	}
+	return wait
+}
+
+func atoiOrDefault(value string, defaultValue int) int {
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed <= 0 {
+		return defaultValue
+	}
+	return parsed
+}
+
+// GetDeliveries returns the latest deliveries matching the given filters
+func (s *WebhookService) GetDeliveries(commId, client, status string) ([]apiModels.WebhookDelivery, error) {
+	query := s.DB.Table(config.Configs.WebhookDeliveryTable)
+	if commId != "" {
+		query = query.Where("CommId = ?", commId)
+	}
+	if client != "" {
+		query = query.Where("Client = ?", strings.ToLower(client))
+	}
+	if status != "" {
+		query = query.Where("Status = ?", strings.ToUpper(status))
+	}
+
+	var deliveries []apiModels.WebhookDelivery
+	if err := query.Order("Id DESC").Limit(200).Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// Redeliver resets a delivery so the worker sends it again with a fresh attempt budget
+func (s *WebhookService) Redeliver(id int) error {
+	result := s.DB.Table(config.Configs.WebhookDeliveryTable).
+		Where("Id = ? AND Status <> ?", id, variables.WebhookInFlight).
+		Updates(map[string]interface{}{
+			"Status":        variables.WebhookPending,
+			"Attempts":      0,
+			"LastError":     "",
+			"NextAttemptOn": utils.IstNow(),
+			"UpdatedOn":     utils.IstNow(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		var count int64
+		if err := s.DB.Table(config.Configs.WebhookDeliveryTable).Where("Id = ?", id).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return errors.New("delivery is currently in flight")
+	}
+
+	select {
+	case wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package snsAuth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureAttribute is the SNS message attribute holding the HMAC of a message published by the SDK.
+const SignatureAttribute = "CommSignature"
+
+// SignedAtAttribute is the SNS message attribute holding the Unix time in seconds the message was signed at.
+// It is part of the HMAC, so a captured message cannot be replayed once it is older than the replay window.
+const SignedAtAttribute = "CommSignedAt"
+
+// minKeyLength is the shortest HMAC key accepted, the size of the SHA-256 output.
+const minKeyLength = 32
+
+// SigningKeys are the HMAC keys shared by the publishers and the consumer, parsed from id:base64 pairs.
+// The first key signs; every key verifies, so a new key can be rolled out before the old one is dropped.
+type SigningKeys struct {
+	current string
+	keys    map[string][]byte
+}
+
+// ParseSigningKeys parses comma separated id:base64 pairs of keys of at least 32 bytes, e.g. "k2:...,k1:...".
+func ParseSigningKeys(value string) (SigningKeys, error) {
+	parsed := SigningKeys{keys: map[string][]byte{}}
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		id, encoded, ok := strings.Cut(pair, ":")
+		if !ok || id == "" {
+			return SigningKeys{}, fmt.Errorf("invalid signing key %q: expected id:base64", id)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) < minKeyLength {
+			return SigningKeys{}, fmt.Errorf("invalid signing key %s: expected at least %d base64 encoded bytes", id, minKeyLength)
+		}
+		if parsed.current == "" {
+			parsed.current = id
+		}
+		parsed.keys[id] = key
+	}
+	return parsed, nil
+}
+
+// Configured reports whether any key was given.
+func (k SigningKeys) Configured() bool {
+	return k.current != ""
+}
+
+// Sign returns the signature of the message published with subject as keyId:base64, or "" when no key is configured.
+// The subject is signed too, so a message cannot be moved to the priority subscription, and so is signedAt, which
+// the publisher sends as the SignedAtAttribute formatted by FormatSignedAt.
+func (k SigningKeys) Sign(subject, message string, signedAt time.Time) string {
+	if !k.Configured() {
+		return ""
+	}
+	return k.current + ":" + base64.StdEncoding.EncodeToString(mac(k.keys[k.current], subject, FormatSignedAt(signedAt), message))
+}
+
+// FormatSignedAt formats the SignedAtAttribute value of a message signed at t.
+func FormatSignedAt(t time.Time) string {
+	return strconv.FormatInt(t.Unix(), 10)
+}
+
+// Verify checks a signature made by Sign with any of the keys; signedAt is the SignedAtAttribute value.
+func (k SigningKeys) Verify(subject, message, signedAt, signature string) error {
+	id, encoded, ok := strings.Cut(signature, ":")
+	if !ok {
+		return fmt.Errorf("%w: malformed %s", ErrUntrusted, SignatureAttribute)
+	}
+	key, ok := k.keys[id]
+	if !ok {
+		return fmt.Errorf("%w: %s signed with unknown key %q", ErrUntrusted, SignatureAttribute, id)
+	}
+	sum, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || !hmac.Equal(sum, mac(key, subject, signedAt, message)) {
+		return fmt.Errorf("%w: %s does not match the message", ErrUntrusted, SignatureAttribute)
+	}
+	return nil
+}
+
+func mac(key []byte, subject, signedAt, message string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(subject))
+	h.Write([]byte{'\n'})
+	h.Write([]byte(signedAt))
+	h.Write([]byte{'\n'})
+	h.Write([]byte(message))
+	return h.Sum(nil)
+}
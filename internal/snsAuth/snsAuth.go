@@ -0,0 +1,256 @@
+// Package snsAuth authenticates the messages the consumer reads from SQS. The SNS signature proves a message was
+// delivered by SNS, and the HMAC the SDK adds as a message attribute proves it was published by one of our producers.
+package snsAuth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wecredit/communication-sdk/internal/models/awsModels"
+	"github.com/wecredit/communication-sdk/sdk/utils"
+	"golang.org/x/sync/singleflight"
+)
+
+// ErrUntrusted is returned for a message that is not authentic; it must be rejected, not retried.
+var ErrUntrusted = errors.New("untrusted message")
+
+const (
+	certCacheTTL = 24 * time.Hour
+	certTimeout  = 10 * time.Second
+	maxCertSize  = 64 * 1024
+	maxClockSkew = 5 * time.Minute // how far in the future a producer's clock may sign a message
+)
+
+// Verifier checks the SNS signature and the HMAC of every message. Signing certificates are only fetched from the
+// allowed hosts and are cached, so verification normally costs no request.
+type Verifier struct {
+	verifySNS        bool
+	allowedHosts     map[string]bool
+	keys             SigningKeys
+	requireSignature bool
+	maxAge           time.Duration
+
+	client *http.Client
+	mu     sync.RWMutex
+	certs  map[string]cachedCert
+	fetch  singleflight.Group
+}
+
+type cachedCert struct {
+	cert      *x509.Certificate
+	fetchedAt time.Time
+}
+
+// NewVerifier returns a verifier accepting signing certificates from certHosts only; verifySNS is only turned off
+// for queues that are not fed by SNS, such as a local emulator. With keys configured, the HMAC of every message is
+// checked, along with its age against maxAge; with requireSignature set, a message that is unsigned, wrongly signed
+// or too old is rejected, otherwise it is only logged, while producers roll out their keys.
+func NewVerifier(verifySNS bool, certHosts []string, keys SigningKeys, requireSignature bool, maxAge time.Duration) *Verifier {
+	allowed := make(map[string]bool, len(certHosts))
+	for _, host := range certHosts {
+		if host = strings.ToLower(strings.TrimSpace(host)); host != "" {
+			allowed[host] = true
+		}
+	}
+	return &Verifier{
+		verifySNS:        verifySNS,
+		allowedHosts:     allowed,
+		keys:             keys,
+		requireSignature: requireSignature,
+		maxAge:           maxAge,
+		client:           &http.Client{Timeout: certTimeout},
+		certs:            map[string]cachedCert{},
+	}
+}
+
+// Verify checks the message. Errors wrapping ErrUntrusted mean the message is not authentic; any other error,
+// such as a certificate that could not be downloaded, means it could not be checked yet.
+func (v *Verifier) Verify(ctx context.Context, message awsModels.SnsMessageWrapper) error {
+	if v.verifySNS {
+		if err := v.verifySNSSignature(ctx, message); err != nil {
+			return err
+		}
+	}
+
+	if err := v.verifyProducerSignature(message, time.Now()); err != nil {
+		if v.requireSignature {
+			return err
+		}
+		utils.Warn(fmt.Sprintf("accepting SQS message %s as COMM_MESSAGE_SIGNATURE_REQUIRED is off: %v", message.MessageId, err))
+	}
+	return nil
+}
+
+// verifyProducerSignature checks the HMAC of the message and that it was signed within maxAge of now. Replays
+// within the window carry a CommId the consumer has already claimed, so they are not sent again.
+func (v *Verifier) verifyProducerSignature(message awsModels.SnsMessageWrapper, now time.Time) error {
+	if !v.keys.Configured() {
+		return nil
+	}
+	attribute, signed := message.MessageAttributes[SignatureAttribute]
+	if !signed {
+		return fmt.Errorf("%w: no %s attribute", ErrUntrusted, SignatureAttribute)
+	}
+	signedAt := message.MessageAttributes[SignedAtAttribute].Value
+	if signedAt == "" {
+		return fmt.Errorf("%w: no %s attribute", ErrUntrusted, SignedAtAttribute)
+	}
+
+	subject := message.MessageAttributes["SubjectKey"].Value
+	if err := v.keys.Verify(subject, message.Message, signedAt, attribute.Value); err != nil {
+		return err
+	}
+
+	seconds, err := strconv.ParseInt(signedAt, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: malformed %s %q", ErrUntrusted, SignedAtAttribute, signedAt)
+	}
+	age := now.Sub(time.Unix(seconds, 0))
+	switch {
+	case age > v.maxAge:
+		return fmt.Errorf("%w: signed %s ago, outside the replay window of %s", ErrUntrusted, age.Round(time.Second), v.maxAge)
+	case age < -maxClockSkew:
+		return fmt.Errorf("%w: signed %s in the future", ErrUntrusted, (-age).Round(time.Second))
+	}
+	return nil
+}
+
+func (v *Verifier) verifySNSSignature(ctx context.Context, message awsModels.SnsMessageWrapper) error {
+	var hash crypto.Hash
+	switch message.SignatureVersion {
+	case "1":
+		hash = crypto.SHA1
+	case "2":
+		hash = crypto.SHA256
+	case "":
+		return fmt.Errorf("%w: message is not signed by SNS", ErrUntrusted)
+	default:
+		return fmt.Errorf("%w: unsupported SNS SignatureVersion %q", ErrUntrusted, message.SignatureVersion)
+	}
+	if message.Type != "Notification" {
+		return fmt.Errorf("%w: unexpected SNS message type %q", ErrUntrusted, message.Type)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(message.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: malformed SNS signature", ErrUntrusted)
+	}
+	cert, err := v.certificate(ctx, message.SigningCertURL)
+	if err != nil {
+		return err
+	}
+	publicKey, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return fmt.Errorf("%w: SNS signing certificate has no RSA key", ErrUntrusted)
+	}
+
+	var digest []byte
+	if hash == crypto.SHA1 {
+		sum := sha1.Sum([]byte(stringToSign(message)))
+		digest = sum[:]
+	} else {
+		sum := sha256.Sum256([]byte(stringToSign(message)))
+		digest = sum[:]
+	}
+	if err := rsa.VerifyPKCS1v15(publicKey, hash, digest, signature); err != nil {
+		return fmt.Errorf("%w: SNS signature does not match the message", ErrUntrusted)
+	}
+	return nil
+}
+
+// stringToSign is the canonical form SNS signs a notification in; Subject is only part of it when it was set.
+func stringToSign(message awsModels.SnsMessageWrapper) string {
+	var b strings.Builder
+	field := func(name, value string) {
+		b.WriteString(name)
+		b.WriteByte('\n')
+		b.WriteString(value)
+		b.WriteByte('\n')
+	}
+	field("Message", message.Message)
+	field("MessageId", message.MessageId)
+	if message.Subject != "" {
+		field("Subject", message.Subject)
+	}
+	field("Timestamp", message.Timestamp)
+	field("TopicArn", message.TopicArn)
+	field("Type", message.Type)
+	return b.String()
+}
+
+// certificate returns the signing certificate at rawURL, from the cache while it is fresh.
+func (v *Verifier) certificate(ctx context.Context, rawURL string) (*x509.Certificate, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Scheme != "https" || !v.allowedHosts[strings.ToLower(parsed.Hostname())] || !strings.HasSuffix(parsed.Path, ".pem") {
+		return nil, fmt.Errorf("%w: SigningCertURL %q is not an allowed SNS certificate", ErrUntrusted, rawURL)
+	}
+
+	v.mu.RLock()
+	cached, ok := v.certs[rawURL]
+	v.mu.RUnlock()
+	if ok && time.Since(cached.fetchedAt) < certCacheTTL && time.Now().Before(cached.cert.NotAfter) {
+		return cached.cert, nil
+	}
+
+	result, err, _ := v.fetch.Do(rawURL, func() (interface{}, error) {
+		cert, err := v.download(ctx, rawURL)
+		if err != nil {
+			return nil, err
+		}
+		v.mu.Lock()
+		v.certs[rawURL] = cachedCert{cert: cert, fetchedAt: time.Now()}
+		v.mu.Unlock()
+		return cert, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*x509.Certificate), nil
+}
+
+func (v *Verifier) download(ctx context.Context, certURL string) (*x509.Certificate, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, certURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SNS certificate request: %v", err)
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download SNS certificate %s: %v", certURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download SNS certificate %s: status %d", certURL, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCertSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SNS certificate %s: %v", certURL, err)
+	}
+	block, _ := pem.Decode(body)
+	if block == nil {
+		return nil, fmt.Errorf("%w: SNS certificate %s is not PEM encoded", ErrUntrusted, certURL)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid SNS certificate %s: %v", ErrUntrusted, certURL, err)
+	}
+	if now := time.Now(); now.Before(cert.NotBefore) || now.After(cert.NotAfter) {
+		return nil, fmt.Errorf("%w: SNS certificate %s is not valid now", ErrUntrusted, certURL)
+	}
+	return cert, nil
+}
@@ -0,0 +1,331 @@
+package snsAuth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wecredit/communication-sdk/internal/models/awsModels"
+)
+
+// signingServer serves a self-signed SNS signing certificate at /cert.pem and signs notifications with its key.
+type signingServer struct {
+	*httptest.Server
+	key *rsa.PrivateKey
+}
+
+func newSigningServer(t *testing.T) *signingServer {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "sns.test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(certPEM)
+	}))
+	t.Cleanup(server.Close)
+	return &signingServer{Server: server, key: key}
+}
+
+func (s *signingServer) verifier(hosts ...string) *Verifier {
+	v := NewVerifier(true, hosts, SigningKeys{}, false, time.Hour)
+	v.client = s.Client()
+	return v
+}
+
+func (s *signingServer) sign(t *testing.T, message awsModels.SnsMessageWrapper) awsModels.SnsMessageWrapper {
+	t.Helper()
+	var digest []byte
+	hash := crypto.SHA256
+	if message.SignatureVersion == "1" {
+		hash = crypto.SHA1
+		sum := sha1.Sum([]byte(stringToSign(message)))
+		digest = sum[:]
+	} else {
+		sum := sha256.Sum256([]byte(stringToSign(message)))
+		digest = sum[:]
+	}
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, hash, digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	message.Signature = base64.StdEncoding.EncodeToString(signature)
+	return message
+}
+
+func notification(version, certURL string) awsModels.SnsMessageWrapper {
+	return awsModels.SnsMessageWrapper{
+		Type:             "Notification",
+		MessageId:        "b6b3c4e8",
+		TopicArn:         "arn:aws:sns:ap-south-1:123456789012:comm",
+		Message:          `{"CommId":"c1"}`,
+		Timestamp:        "2026-10-19T10:00:00.000Z",
+		SignatureVersion: version,
+		SigningCertURL:   certURL,
+	}
+}
+
+func TestVerifySNSSignature(t *testing.T) {
+	server := newSigningServer(t)
+	host := strings.TrimPrefix(server.URL, "https://")
+	hostname := host[:strings.LastIndex(host, ":")]
+	certURL := server.URL + "/cert.pem"
+
+	tests := []struct {
+		name      string
+		hosts     []string
+		message   func() awsModels.SnsMessageWrapper
+		untrusted bool
+	}{
+		{
+			name:    "signature version 1",
+			hosts:   []string{hostname},
+			message: func() awsModels.SnsMessageWrapper { return server.sign(t, notification("1", certURL)) },
+		},
+		{
+			name:    "signature version 2",
+			hosts:   []string{hostname},
+			message: func() awsModels.SnsMessageWrapper { return server.sign(t, notification("2", certURL)) },
+		},
+		{
+			name:  "tampered message",
+			hosts: []string{hostname},
+			message: func() awsModels.SnsMessageWrapper {
+				message := server.sign(t, notification("2", certURL))
+				message.Message = `{"CommId":"c2"}`
+				return message
+			},
+			untrusted: true,
+		},
+		{
+			name:      "certificate host not allowed",
+			hosts:     []string{"sns.ap-south-1.amazonaws.com"},
+			message:   func() awsModels.SnsMessageWrapper { return server.sign(t, notification("2", certURL)) },
+			untrusted: true,
+		},
+		{
+			name:  "certificate over http",
+			hosts: []string{hostname},
+			message: func() awsModels.SnsMessageWrapper {
+				return server.sign(t, notification("2", "http://"+host+"/cert.pem"))
+			},
+			untrusted: true,
+		},
+		{
+			name:      "certificate not a pem file",
+			hosts:     []string{hostname},
+			message:   func() awsModels.SnsMessageWrapper { return server.sign(t, notification("2", server.URL+"/cert")) },
+			untrusted: true,
+		},
+		{
+			name:      "not signed by SNS",
+			hosts:     []string{hostname},
+			message:   func() awsModels.SnsMessageWrapper { return notification("", certURL) },
+			untrusted: true,
+		},
+		{
+			name:  "not a notification",
+			hosts: []string{hostname},
+			message: func() awsModels.SnsMessageWrapper {
+				message := notification("2", certURL)
+				message.Type = "SubscriptionConfirmation"
+				return server.sign(t, message)
+			},
+			untrusted: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := server.verifier(tt.hosts...).Verify(context.Background(), tt.message())
+			if tt.untrusted && !errors.Is(err, ErrUntrusted) {
+				t.Fatalf("expected ErrUntrusted, got %v", err)
+			}
+			if !tt.untrusted && err != nil {
+				t.Fatalf("expected the message to verify, got %v", err)
+			}
+		})
+	}
+}
+
+func testKey(fill byte) string {
+	return base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(fill), minKeyLength)))
+}
+
+func TestVerifyProducerSignature(t *testing.T) {
+	oldKeys, err := ParseSigningKeys("k1:" + testKey('a'))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotatedKeys, err := ParseSigningKeys("k2:" + testKey('b') + ",k1:" + testKey('a'))
+	if err != nil {
+		t.Fatal(err)
+	}
+	unknownKeys, err := ParseSigningKeys("k3:" + testKey('c'))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	signed := func(keys SigningKeys, subject string, signedAt time.Time) awsModels.SnsMessageWrapper {
+		message := notification("", "")
+		message.MessageAttributes = map[string]awsModels.SnsMessageAttribute{
+			"SubjectKey":       {Type: "String", Value: subject},
+			SignatureAttribute: {Type: "String", Value: keys.Sign(subject, message.Message, signedAt)},
+			SignedAtAttribute:  {Type: "String", Value: FormatSignedAt(signedAt)},
+		}
+		return message
+	}
+
+	tests := []struct {
+		name      string
+		message   func() awsModels.SnsMessageWrapper
+		required  bool
+		untrusted bool
+	}{
+		{
+			name:     "signed",
+			message:  func() awsModels.SnsMessageWrapper { return signed(rotatedKeys, "sms", now) },
+			required: true,
+		},
+		{
+			name:     "signed with the previous key during rotation",
+			message:  func() awsModels.SnsMessageWrapper { return signed(oldKeys, "sms", now) },
+			required: true,
+		},
+		{
+			name:      "signed with an unknown key",
+			message:   func() awsModels.SnsMessageWrapper { return signed(unknownKeys, "sms", now) },
+			required:  true,
+			untrusted: true,
+		},
+		{
+			name: "moved to another subject",
+			message: func() awsModels.SnsMessageWrapper {
+				message := signed(rotatedKeys, "sms", now)
+				message.MessageAttributes["SubjectKey"] = awsModels.SnsMessageAttribute{Type: "String", Value: "priority"}
+				return message
+			},
+			required:  true,
+			untrusted: true,
+		},
+		{
+			name: "signing time changed",
+			message: func() awsModels.SnsMessageWrapper {
+				message := signed(rotatedKeys, "sms", now.Add(-2*time.Hour))
+				message.MessageAttributes[SignedAtAttribute] = awsModels.SnsMessageAttribute{Type: "String", Value: FormatSignedAt(now)}
+				return message
+			},
+			required:  true,
+			untrusted: true,
+		},
+		{
+			name:      "unsigned",
+			message:   func() awsModels.SnsMessageWrapper { return notification("", "") },
+			required:  true,
+			untrusted: true,
+		},
+		{
+			name:    "unsigned while signatures are not required",
+			message: func() awsModels.SnsMessageWrapper { return notification("", "") },
+		},
+		{
+			name:    "signed with an unknown key while signatures are not required",
+			message: func() awsModels.SnsMessageWrapper { return signed(unknownKeys, "sms", now) },
+		},
+		{
+			name: "no signing time",
+			message: func() awsModels.SnsMessageWrapper {
+				message := signed(rotatedKeys, "sms", now)
+				delete(message.MessageAttributes, SignedAtAttribute)
+				return message
+			},
+			required:  true,
+			untrusted: true,
+		},
+		{
+			name:      "replayed after the replay window",
+			message:   func() awsModels.SnsMessageWrapper { return signed(rotatedKeys, "sms", now.Add(-2*time.Hour)) },
+			required:  true,
+			untrusted: true,
+		},
+		{
+			name:      "signed in the future",
+			message:   func() awsModels.SnsMessageWrapper { return signed(rotatedKeys, "sms", now.Add(time.Hour)) },
+			required:  true,
+			untrusted: true,
+		},
+		{
+			name:     "signed by a clock slightly ahead",
+			message:  func() awsModels.SnsMessageWrapper { return signed(rotatedKeys, "sms", now.Add(time.Minute)) },
+			required: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewVerifier(false, nil, rotatedKeys, tt.required, time.Hour)
+			err := v.Verify(context.Background(), tt.message())
+			if tt.untrusted && !errors.Is(err, ErrUntrusted) {
+				t.Fatalf("expected ErrUntrusted, got %v", err)
+			}
+			if !tt.untrusted && err != nil {
+				t.Fatalf("expected the message to be accepted, got %v", err)
+			}
+		})
+	}
+}
+
+func TestVerifyWithoutKeys(t *testing.T) {
+	v := NewVerifier(false, nil, SigningKeys{}, true, time.Hour)
+	if err := v.Verify(context.Background(), notification("", "")); err != nil {
+		t.Fatalf("expected unsigned messages to be accepted without keys, got %v", err)
+	}
+}
+
+func TestParseSigningKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{name: "empty", value: ""},
+		{name: "two keys", value: "k2:" + testKey('b') + ", k1:" + testKey('a')},
+		{name: "missing id", value: testKey('a'), wantErr: true},
+		{name: "short key", value: "k1:" + base64.StdEncoding.EncodeToString([]byte("short")), wantErr: true},
+		{name: "not base64", value: "k1:not base64", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseSigningKeys(tt.value); (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSigningKeys() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+// Package tracing follows a message with OpenTelemetry spans from SDK Send through SNS, SQS and the consumer
+// to the vendor call. The W3C trace context travels in the HTTP headers of the ingestion endpoint and in the
+// SNS message attributes, next to SubjectKey.
+//
+// Spans go to the global tracer provider: the consumer installs one with Setup, and SDK callers that trace
+// install their own. Until then every span is a no-op.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/wecredit/communication-sdk/internal/models/awsModels"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/wecredit/communication-sdk"
+
+// Exporters accepted by Setup
+const (
+	ExporterNone   = "none"
+	ExporterOtlp   = "otlp"
+	ExporterStdout = "stdout"
+)
+
+// Attributes set on the spans of a message
+const (
+	AttrCommId  = attribute.Key("comm.id")
+	AttrClient  = attribute.Key("comm.client")
+	AttrChannel = attribute.Key("comm.channel")
+	AttrStage   = attribute.Key("comm.stage")
+	AttrVendor  = attribute.Key("comm.vendor")
+)
+
+// propagator is fixed rather than the global one, so the context is carried whatever the caller installed.
+var propagator = propagation.TraceContext{}
+
+// Setup installs the global tracer provider with the exporter: otlp sends spans over OTLP/HTTP as configured by
+// the OTEL_EXPORTER_OTLP_* environment variables, stdout prints them for local testing, and none or an empty
+// exporter leaves tracing off. sampleRatio applies to traces started here; a sampled parent is always followed.
+// The returned function flushes the spans not yet exported.
+func Setup(ctx context.Context, exporter, serviceName string, sampleRatio float64) (func(context.Context) error, error) {
+	var spanExporter sdktrace.SpanExporter
+	var err error
+	switch strings.ToLower(strings.TrimSpace(exporter)) {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterOtlp:
+		spanExporter, err = otlptracehttp.New(ctx)
+	case ExporterStdout:
+		spanExporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout), stdouttrace.WithPrettyPrint())
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s trace exporter: %w", exporter, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(spanExporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Start starts a span named name as a child of the span in ctx.
+func Start(ctx context.Context, name string, kind trace.SpanKind, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithSpanKind(kind), trace.WithAttributes(attrs...))
+}
+
+// End ends the span, marking it failed when err is not nil.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// InjectHeaders adds the trace context of ctx to the headers of an outgoing request.
+func InjectHeaders(ctx context.Context, headers map[string]string) {
+	propagator.Inject(ctx, propagation.MapCarrier(headers))
+}
+
+// ExtractHeaders returns ctx with the trace context of an incoming request.
+func ExtractHeaders(ctx context.Context, headers http.Header) context.Context {
+	return propagator.Extract(ctx, propagation.HeaderCarrier(headers))
+}
+
+// InjectSNS adds the trace context of ctx to the attributes of an SNS message.
+func InjectSNS(ctx context.Context, attributes map[string]*sns.MessageAttributeValue) {
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(ctx, carrier)
+	for key, value := range carrier {
+		attributes[key] = &sns.MessageAttributeValue{
+			DataType:    aws.String("String"),
+			StringValue: aws.String(value),
+		}
+	}
+}
+
+// ExtractSNS returns the span context the SNS message was published in; it is invalid when the message carries none.
+func ExtractSNS(attributes map[string]awsModels.SnsMessageAttribute) trace.SpanContext {
+	carrier := propagation.MapCarrier{}
+	for key, value := range attributes {
+		carrier[key] = value.Value
+	}
+	return trace.SpanContextFromContext(propagator.Extract(context.Background(), carrier))
+}
+
+// Carrier returns the trace context of ctx, to be stored with a message published later.
+func Carrier(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(ctx, carrier)
+	return carrier
+}
+
+// FromCarrier returns ctx with the trace context stored by Carrier.
+func FromCarrier(ctx context.Context, carrier map[string]string) context.Context {
+	return propagator.Extract(ctx, propagation.MapCarrier(carrier))
+}
+
+// MessageAttributes returns the attributes that identify a message on its spans.
+func MessageAttributes(commId, client, channel string, stage float64) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		AttrCommId.String(commId),
+		AttrClient.String(client),
+		AttrChannel.String(channel),
+		AttrStage.Float64(stage),
+	}
+}
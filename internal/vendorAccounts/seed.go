@@ -0,0 +1,79 @@
+package vendorAccounts
+
+import (
+	"fmt"
+
+	"github.com/wecredit/communication-sdk/config"
+	"github.com/wecredit/communication-sdk/internal/models/apiModels"
+	"github.com/wecredit/communication-sdk/sdk/utils"
+	"github.com/wecredit/communication-sdk/sdk/variables"
+	"gorm.io/gorm"
+)
+
+// legacyAccounts are the client accounts that were configured through environment variables, or in code, before
+// the VendorAccounts table existed. Accounts whose variables are not set are not seeded.
+func legacyAccounts() []apiModels.VendorAccount {
+	c := config.Configs
+	return []apiModels.VendorAccount{
+		{
+			Client:      variables.CreditSea,
+			Channel:     variables.SMS,
+			Vendor:      variables.SINCH,
+			AppId:       c.CreditSeaSinchSmsApiAppID,
+			Sender:      c.CreditSeaSinchSmsApiSender,
+			Credentials: &apiModels.VendorCredentials{Username: c.CreditSeaSinchSmsApiUserName, Password: c.CreditSeaSinchSmsApiPassword},
+		},
+		{
+			Client:      variables.CreditSea,
+			Channel:     variables.WhatsApp,
+			Vendor:      variables.SINCH,
+			AppId:       "creditseapd",
+			Credentials: &apiModels.VendorCredentials{Username: c.CreditSeaSinchWhatsappUsername, Password: c.CreditSeaSinchWhatsappPassword},
+		},
+		{
+			// CreditSea emails went out under its name through the shared Sinch token
+			Client:      variables.CreditSea,
+			Channel:     variables.Email,
+			Vendor:      variables.SINCH,
+			SenderName:  "CreditSea",
+			ReplyTo:     "help@creditsea.com",
+			Credentials: &apiModels.VendorCredentials{Token: c.SinchEmailApiToken},
+		},
+	}
+}
+
+// SeedLegacyAccounts stores the legacy client accounts that have no active row yet, so a client keeps sending
+// under its own account once it is resolved from the table. It returns the number of rows written.
+func SeedLegacyAccounts(db *gorm.DB) (int, error) {
+	table := config.Configs.VendorAccountTable
+	seeded := 0
+	for _, account := range legacyAccounts() {
+		credentials := account.Credentials
+		if credentials.Token == "" && (credentials.Username == "" || credentials.Password == "") {
+			continue
+		}
+
+		var existing int64
+		err := db.Table(table).Where("Client = ? AND Channel = ? AND Vendor = ? AND Status = ?", account.Client, account.Channel, account.Vendor, variables.Active).
+			Count(&existing).Error
+		if err != nil {
+			return seeded, err
+		}
+		if existing > 0 {
+			continue
+		}
+
+		if err := Seal(&account, *account.Credentials); err != nil {
+			return seeded, fmt.Errorf("failed to encrypt the %s %s account of %s: %w", account.Vendor, account.Channel, account.Client, err)
+		}
+		account.Credentials = nil
+		account.Status = int(variables.Active)
+		account.CreatedOn = utils.IstNow()
+		if err := db.Table(table).Create(&account).Error; err != nil {
+			return seeded, err
+		}
+		utils.Info(fmt.Sprintf("seeded the %s %s account of %s from its environment variables", account.Vendor, account.Channel, account.Client))
+		seeded++
+	}
+	return seeded, nil
+}
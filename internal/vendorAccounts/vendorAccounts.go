@@ -0,0 +1,146 @@
+// Package vendorAccounts resolves the vendor account a message is sent through.
+package vendorAccounts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/wecredit/communication-sdk/config"
+	"github.com/wecredit/communication-sdk/internal/models/apiModels"
+	"github.com/wecredit/communication-sdk/internal/secrets"
+	"github.com/wecredit/communication-sdk/pkg/cache"
+	"github.com/wecredit/communication-sdk/sdk/variables"
+)
+
+// ErrNoAccount is returned when neither the client nor the vendor has an account for the channel, or when the client
+// has accounts of its own for the channel but not at the vendor.
+var ErrNoAccount = errors.New("no vendor account configured")
+
+// Account is a vendor account with its credentials decrypted. It must never be logged.
+type Account struct {
+	Id         int    // 0 for the account configured through environment variables
+	Client     string // "" for the vendor's shared account
+	AppId      string
+	Sender     string
+	SenderName string
+	ReplyTo    string
+	apiModels.VendorCredentials
+}
+
+// decrypted holds opened credentials by decryptedKey.
+var decrypted sync.Map
+
+// decryptedKey names an envelope together with the row it is bound to. The ciphertext changes whenever the
+// credentials do; the AAD keeps an envelope copied into another row from using the credentials opened for the
+// first one without being opened again.
+type decryptedKey struct {
+	aad        string
+	keyId      string
+	wrappedKey string
+	ciphertext string
+}
+
+// Resolve returns the account client sends channel messages through at vendor: the client's own account,
+// else the vendor's shared account row, else the shared account configured through environment variables.
+// A client with accounts of its own for the channel never falls back to a shared account: its messages must not
+// go out under another sender.
+func Resolve(client, channel, vendor string) (Account, error) {
+	channel = strings.ToUpper(strings.TrimSpace(channel))
+	vendor = strings.ToUpper(strings.TrimSpace(vendor))
+
+	snapshot := cache.Current()
+	if own := snapshot.ClientAccountVendors(client, channel); len(own) > 0 && !slices.Contains(own, vendor) {
+		return Account{}, fmt.Errorf("%w: client %s has %s accounts at %s only, not at %s", ErrNoAccount, client, channel, strings.Join(own, ", "), vendor)
+	}
+
+	row, ok := snapshot.VendorAccount(client, channel, vendor)
+	if !ok {
+		if account, ok := fromEnv(channel, vendor); ok {
+			return account, nil
+		}
+		return Account{}, fmt.Errorf("%w: client %s, channel %s, vendor %s", ErrNoAccount, client, channel, vendor)
+	}
+
+	credentials, err := Decrypt(row)
+	if err != nil {
+		return Account{}, fmt.Errorf("failed to decrypt vendor account %d: %w", row.Id, err)
+	}
+	return Account{
+		Id:                row.Id,
+		Client:            row.Client,
+		AppId:             row.AppId,
+		Sender:            row.Sender,
+		SenderName:        row.SenderName,
+		ReplyTo:           row.ReplyTo,
+		VendorCredentials: credentials,
+	}, nil
+}
+
+// Seal encrypts credentials into the row; the envelope is bound to the row's client, channel and vendor.
+func Seal(row *apiModels.VendorAccount, credentials apiModels.VendorCredentials) error {
+	plaintext, err := json.Marshal(credentials)
+	if err != nil {
+		return err
+	}
+	envelope, err := secrets.Seal(plaintext, aad(*row))
+	if err != nil {
+		return err
+	}
+	row.EncryptedCredentials = envelope.Ciphertext
+	row.WrappedKey = envelope.WrappedKey
+	row.KeyId = envelope.KeyId
+	return nil
+}
+
+// Decrypt opens the credentials of the row.
+func Decrypt(row apiModels.VendorAccount) (apiModels.VendorCredentials, error) {
+	key := decryptedKey{aad: string(aad(row)), keyId: row.KeyId, wrappedKey: row.WrappedKey, ciphertext: row.EncryptedCredentials}
+	if cached, ok := decrypted.Load(key); ok {
+		return cached.(apiModels.VendorCredentials), nil
+	}
+
+	envelope := secrets.Envelope{Ciphertext: row.EncryptedCredentials, WrappedKey: row.WrappedKey, KeyId: row.KeyId}
+	plaintext, err := secrets.Open(envelope, []byte(key.aad))
+	if err != nil {
+		return apiModels.VendorCredentials{}, err
+	}
+	var credentials apiModels.VendorCredentials
+	if err := json.Unmarshal(plaintext, &credentials); err != nil {
+		return apiModels.VendorCredentials{}, fmt.Errorf("invalid credentials: %w", err)
+	}
+	decrypted.Store(key, credentials)
+	return credentials, nil
+}
+
+func aad(row apiModels.VendorAccount) []byte {
+	client := strings.ToLower(strings.TrimSpace(row.Client))
+	channel := strings.ToUpper(strings.TrimSpace(row.Channel))
+	vendor := strings.ToUpper(strings.TrimSpace(row.Vendor))
+	return []byte(cache.VendorAccountCacheKey(client, channel, vendor))
+}
+
+// fromEnv returns the shared account configured through environment variables, which serves every client
+// without an account row until the shared account is moved into the table as well.
+func fromEnv(channel, vendor string) (Account, bool) {
+	c := config.Configs
+	switch {
+	case channel == variables.SMS && vendor == variables.SINCH:
+		return Account{AppId: c.SinchSmsApiAppID, Sender: c.SinchSmsApiSender, VendorCredentials: apiModels.VendorCredentials{Username: c.SinchSmsApiUserName, Password: c.SinchSmsApiPassword}}, true
+	case channel == variables.SMS && vendor == variables.TIMES:
+		return Account{Sender: c.TimesSmsApiSender, VendorCredentials: apiModels.VendorCredentials{Username: c.TimesSmsApiUserName, Password: c.TimesSmsApiPassword}}, true
+	case channel == variables.WhatsApp && vendor == variables.SINCH:
+		return Account{AppId: c.SinchWhatsappAppId, VendorCredentials: apiModels.VendorCredentials{Username: c.SinchWhatsappUserName, Password: c.SinchWhatsappPassword}}, true
+	case channel == variables.RCS && vendor == variables.SINCH:
+		// RCS authenticates with the Sinch OAuth account of WhatsApp
+		return Account{VendorCredentials: apiModels.VendorCredentials{Username: c.SinchWhatsappUserName, Password: c.SinchWhatsappPassword}}, true
+	case channel == variables.WhatsApp && vendor == variables.TIMES:
+		return Account{VendorCredentials: apiModels.VendorCredentials{Token: c.TimesWpAPIToken}}, true
+	case channel == variables.Email && vendor == variables.SINCH:
+		return Account{VendorCredentials: apiModels.VendorCredentials{Token: c.SinchEmailApiToken}}, true
+	}
+	return Account{}, false
+}
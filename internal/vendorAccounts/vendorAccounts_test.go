@@ -0,0 +1,39 @@
+package vendorAccounts
+
+import (
+	"encoding/base64"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/wecredit/communication-sdk/config"
+	"github.com/wecredit/communication-sdk/internal/models/apiModels"
+)
+
+func TestMain(m *testing.M) {
+	// The master keys are read once, on first use
+	config.Configs.SecretsMasterKeys = "k1:" + base64.StdEncoding.EncodeToString([]byte(strings.Repeat("a", 32)))
+	os.Exit(m.Run())
+}
+
+func TestDecrypt(t *testing.T) {
+	row := apiModels.VendorAccount{Client: "acme", Channel: "SMS", Vendor: "SINCH"}
+	if err := Seal(&row, apiModels.VendorCredentials{Username: "acme-user", Password: "s3cret"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Twice, so the cached credentials answer the same as the envelope
+	for i := 0; i < 2; i++ {
+		credentials, err := Decrypt(row)
+		if err != nil || credentials.Username != "acme-user" || credentials.Password != "s3cret" {
+			t.Fatalf("Decrypt() = %+v, %v", credentials, err)
+		}
+	}
+
+	// The envelope of acme copied into the row of another client must not open, even once it is cached
+	copied := row
+	copied.Client = "other"
+	if _, err := Decrypt(copied); err == nil {
+		t.Fatal("expected the credentials not to decrypt for another client")
+	}
+}
@@ -0,0 +1,150 @@
+// Package vendorAudit keeps a log of every request sent to a vendor API, so a disputed send can be looked up by CommId.
+// Calls are queued by Record and written in batches by StartWriter, so the send path never waits on the database.
+package vendorAudit
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/wecredit/communication-sdk/config"
+	"github.com/wecredit/communication-sdk/internal/models/apiModels"
+	"github.com/wecredit/communication-sdk/internal/redact"
+	"github.com/wecredit/communication-sdk/sdk/utils"
+	"gorm.io/gorm"
+)
+
+const (
+	queueSize     = 2048
+	batchSize     = 100
+	flushInterval = time.Second
+	// maxBodyLength caps each stored body; vendor payloads are far smaller
+	maxBodyLength = 16 * 1024
+
+	purgeBatchSize = 5000
+	purgePause     = 100 * time.Millisecond
+)
+
+// Call is one request to a vendor API as sent and answered, before redaction.
+type Call struct {
+	CommId       string
+	Vendor       string
+	Method       string
+	URL          string
+	StatusCode   int
+	Latency      time.Duration
+	Err          error
+	RequestBody  []byte
+	ResponseBody []byte
+	At           time.Time // IST; set by Record when zero
+}
+
+var queue = make(chan Call, queueSize)
+
+// Record queues the call for the audit log. When the writer falls behind the call is dropped with a warning.
+func Record(call Call) {
+	if call.At.IsZero() {
+		call.At = utils.IstNow()
+	}
+	select {
+	case queue <- call:
+	default:
+		utils.Warn(fmt.Sprintf("vendor audit queue is full, dropping %s call for CommId %s", call.Vendor, call.CommId))
+	}
+}
+
+// StartWriter writes queued calls to the audit table until the context is cancelled.
+func StartWriter(ctx context.Context, db *gorm.DB) {
+	utils.Info("Starting vendor audit writer")
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]apiModels.VendorCallAudit, 0, batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := db.Table(config.Configs.VendorAuditTable).Create(&batch).Error; err != nil {
+			utils.Error(fmt.Errorf("failed to write %d vendor audit rows: %v", len(batch), err))
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			flush()
+			utils.Warn("Context cancelled. Stopping vendor audit writer.")
+			return
+		case call := <-queue:
+			batch = append(batch, toAudit(call))
+			if len(batch) >= batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// Purge deletes the audit rows older than VENDOR_AUDIT_RETENTION_DAYS, purgeBatchSize rows at a time, so the
+// table is never locked by one long delete and replicas keep up.
+func Purge(db *gorm.DB) (int64, error) {
+	days, err := strconv.Atoi(config.Configs.VendorAuditRetentionDays)
+	if err != nil || days <= 0 {
+		return 0, fmt.Errorf("invalid VENDOR_AUDIT_RETENTION_DAYS %q", config.Configs.VendorAuditRetentionDays)
+	}
+
+	cutoff := utils.IstNow().AddDate(0, 0, -days)
+	query := fmt.Sprintf("DELETE FROM %s WHERE CreatedOn < ? LIMIT ?", config.Configs.VendorAuditTable)
+	var purged int64
+	for {
+		result := db.Exec(query, cutoff, purgeBatchSize)
+		if result.Error != nil {
+			return purged, fmt.Errorf("failed to purge vendor audit rows: %w", result.Error)
+		}
+		purged += result.RowsAffected
+		if result.RowsAffected < purgeBatchSize {
+			return purged, nil
+		}
+		time.Sleep(purgePause)
+	}
+}
+
+func toAudit(call Call) apiModels.VendorCallAudit {
+	audit := apiModels.VendorCallAudit{
+		CommId:       call.CommId,
+		Vendor:       call.Vendor,
+		Method:       call.Method,
+		Endpoint:     endpoint(call.URL),
+		StatusCode:   call.StatusCode,
+		LatencyMs:    call.Latency.Milliseconds(),
+		RequestBody:  capped(redact.Body(call.RequestBody)),
+		ResponseBody: capped(redact.Body(call.ResponseBody)),
+		CreatedOn:    call.At,
+	}
+	if call.Err != nil {
+		audit.Error = capped(redact.Text(call.Err.Error()))
+	}
+	return audit
+}
+
+// endpoint drops the query string and user info, which can carry credentials.
+func endpoint(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	parsed.RawQuery = ""
+	parsed.User = nil
+	return parsed.String()
+}
+
+func capped(body string) string {
+	if len(body) > maxBodyLength {
+		return body[:maxBodyLength] + "..."
+	}
+	return body
+}
@@ -58,18 +58,20 @@ func main() {
 	// Loop through each stage and send email
 	for _, stage := range stages {
 		request := &sdkModels.CommApiRequestBody{
-			Mobile:            "7570897034",
-			Email:             "nikhil@wecredit.co.in",
-			Channel:           "WHATSAPP",
-			ProcessName:       "CREDITSEA",
-			Stage:             stage,
-			IsPriority:        true,
-			EmiAmount:         "25000",
-			CustomerName:      "Nikhil",
-			LoanId:            "1234616232324",
-			ApplicationNumber: "2696944656976",
-			DueDate:           "2025-10-20",
-			Description:       fmt.Sprintf("TEST for stage %.2f", stage),
+			Mobile:      "7570897034",
+			Email:       "nikhil@wecredit.co.in",
+			Channel:     "WHATSAPP",
+			ProcessName: "CREDITSEA",
+			Stage:       stage,
+			IsPriority:  true,
+			Variables: map[string]string{
+				"EmiAmount":         "25000",
+				"CustomerName":      "Nikhil",
+				"LoanId":            "1234616232324",
+				"ApplicationNumber": "2696944656976",
+				"DueDate":           "2025-10-20",
+			},
+			Description: fmt.Sprintf("TEST for stage %.2f", stage),
 		}
 
 		response, err := client.Send(request)
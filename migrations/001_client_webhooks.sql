@@ -0,0 +1,27 @@
+-- Status webhooks of clients and their delivery queue.
+
+ALTER TABLE ${CLIENTS_TABLE}
+    ADD COLUMN WebhookUrl    VARCHAR(2048) NULL,
+    ADD COLUMN WebhookSecret VARCHAR(512)  NULL,
+    ADD COLUMN WebhookEvents VARCHAR(255)  NULL;
+
+CREATE TABLE IF NOT EXISTS WebhookDeliveries (
+    Id             BIGINT        NOT NULL AUTO_INCREMENT,
+    CommId         VARCHAR(64)   NOT NULL,
+    Client         VARCHAR(100)  NOT NULL,
+    Channel        VARCHAR(20)   NOT NULL,
+    EventType      VARCHAR(20)   NOT NULL,
+    Url            VARCHAR(2048) NOT NULL,
+    Payload        TEXT          NOT NULL,
+    Status         VARCHAR(20)   NOT NULL, -- PENDING, IN_FLIGHT, DELIVERED, FAILED
+    Attempts       INT           NOT NULL DEFAULT 0,
+    LastStatusCode INT           NULL,
+    LastError      TEXT          NULL,
+    NextAttemptOn  DATETIME      NOT NULL,
+    DeliveredOn    DATETIME      NULL,
+    CreatedOn      DATETIME      NOT NULL,
+    UpdatedOn      DATETIME      NULL,
+    PRIMARY KEY (Id),
+    KEY idx_webhook_deliveries_due (Status, NextAttemptOn),
+    KEY idx_webhook_deliveries_comm (CommId)
+);
@@ -0,0 +1,32 @@
+-- Versions of each template; the ACTIVE version is copied into the template row the consumer reads.
+
+CREATE TABLE IF NOT EXISTS TemplateVersions (
+    Id                BIGINT        NOT NULL AUTO_INCREMENT,
+    TemplateId        BIGINT        NOT NULL,
+    Version           INT           NOT NULL,
+    State             VARCHAR(20)   NOT NULL, -- DRAFT, APPROVED, ACTIVE, SUPERSEDED
+    Client            VARCHAR(100)  NULL,
+    Channel           VARCHAR(20)   NOT NULL,
+    Process           VARCHAR(100)  NOT NULL,
+    Stage             DOUBLE        NOT NULL,
+    Vendor            VARCHAR(20)   NOT NULL,
+    TemplateName      VARCHAR(255)  NOT NULL,
+    ImageId           VARCHAR(255)  NULL,
+    ImageUrl          VARCHAR(2048) NULL,
+    DltTemplateId     BIGINT        NULL,
+    TemplateText      TEXT          NULL,
+    Link              VARCHAR(2048) NULL,
+    TemplateCategory  BIGINT        NULL,
+    TemplateVariables VARCHAR(1024) NULL,
+    Subject           VARCHAR(512)  NULL,
+    FromEmail         VARCHAR(255)  NULL,
+    Notes             TEXT          NULL,
+    ActivateOn        DATETIME      NULL,
+    ApprovedOn        DATETIME      NULL,
+    ActivatedOn       DATETIME      NULL,
+    CreatedOn         DATETIME      NOT NULL,
+    UpdatedOn         DATETIME      NULL,
+    PRIMARY KEY (Id),
+    UNIQUE KEY uq_template_versions_version (TemplateId, Version),
+    KEY idx_template_versions_scheduled (State, ActivateOn)
+);
@@ -0,0 +1,9 @@
+-- Template fallback policy of each client and the resolution trace stored with each output row.
+
+ALTER TABLE ${CLIENTS_TABLE}
+    ADD COLUMN TemplateFallbackPolicy VARCHAR(255) NULL; -- e.g. EXACT,SIBLING_STAGE,OTHER_VENDOR
+
+ALTER TABLE ${SMS_OUTPUT_TABLE}      ADD COLUMN TemplateResolution VARCHAR(512) NULL;
+ALTER TABLE ${WHATSAPP_OUTPUT_TABLE} ADD COLUMN TemplateResolution VARCHAR(512) NULL;
+ALTER TABLE ${RCS_OUTPUT_TABLE}      ADD COLUMN TemplateResolution VARCHAR(512) NULL;
+ALTER TABLE ${EMAIL_OUTPUT_TABLE}    ADD COLUMN TemplateResolution VARCHAR(512) NULL;
@@ -0,0 +1,21 @@
+-- Outbox of SDK clients created WithOutbox. It lives in the producer's database, next to its input tables;
+-- ${OUTBOX_TABLE} is the table name passed to WithOutbox.
+
+CREATE TABLE IF NOT EXISTS ${OUTBOX_TABLE} (
+    Id            BIGINT       NOT NULL AUTO_INCREMENT,
+    CommId        VARCHAR(64)  NOT NULL,
+    RedisKey      VARCHAR(255) NULL,
+    TopicArn      VARCHAR(512) NOT NULL,
+    Subject       VARCHAR(255) NULL,
+    Payload       MEDIUMTEXT   NOT NULL,
+    Status        VARCHAR(20)  NOT NULL, -- PENDING, IN_FLIGHT, SENT, FAILED
+    Attempts      INT          NOT NULL DEFAULT 0,
+    LastError     TEXT         NULL,
+    NextAttemptOn DATETIME     NOT NULL,
+    SentOn        DATETIME     NULL,
+    CreatedOn     DATETIME     NOT NULL,
+    UpdatedOn     DATETIME     NULL,
+    PRIMARY KEY (Id),
+    KEY idx_outbox_due (Status, NextAttemptOn),
+    KEY idx_outbox_comm (CommId)
+);
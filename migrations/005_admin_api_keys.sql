@@ -0,0 +1,14 @@
+-- API keys of the admin routes; only the SHA-256 hex digest of a key is stored.
+
+CREATE TABLE IF NOT EXISTS AdminApiKeys (
+    Id        BIGINT       NOT NULL AUTO_INCREMENT,
+    Name      VARCHAR(100) NOT NULL,
+    KeyHash   CHAR(64)     NOT NULL,
+    Role      VARCHAR(32)  NOT NULL, -- viewer, template-editor or ops-admin
+    Client    VARCHAR(100) NULL,     -- set to restrict the key to one client's templates
+    Status    TINYINT      NOT NULL DEFAULT 1,
+    CreatedOn DATETIME     NOT NULL,
+    UpdatedOn DATETIME     NULL,
+    PRIMARY KEY (Id),
+    UNIQUE KEY uq_admin_api_keys_hash (KeyHash)
+);
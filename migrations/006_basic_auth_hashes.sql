@@ -0,0 +1,10 @@
+-- Hashed, rotatable basic auth secrets. The plaintext password column stays until every pod hashes on start;
+-- it is cleared by the consumer once AUTH_CLEAR_PLAINTEXT_PASSWORDS is set.
+
+ALTER TABLE ${BASIC_AUTH_TABLE}
+    ADD COLUMN passwordHash VARCHAR(100) NULL,
+    ADD COLUMN expiresOn    DATETIME     NULL,
+    ADD COLUMN revokedOn    DATETIME     NULL,
+    ADD COLUMN updatedOn    DATETIME     NULL,
+    MODIFY COLUMN password  VARCHAR(255) NULL,
+    ADD KEY idx_basic_auth_username (username);
@@ -0,0 +1,19 @@
+-- Vendor accounts of each client, credentials envelope encrypted. An empty Client is the vendor's shared account.
+
+CREATE TABLE IF NOT EXISTS VendorAccounts (
+    Id                   BIGINT       NOT NULL AUTO_INCREMENT,
+    Client               VARCHAR(100) NOT NULL DEFAULT '',
+    Channel              VARCHAR(20)  NOT NULL,
+    Vendor               VARCHAR(20)  NOT NULL,
+    AppId                VARCHAR(100) NULL,
+    Sender               VARCHAR(255) NULL,
+    EncryptedCredentials TEXT         NOT NULL,
+    WrappedKey           VARCHAR(255) NOT NULL,
+    KeyId                VARCHAR(64)  NOT NULL,
+    Status               TINYINT      NOT NULL DEFAULT 1,
+    CreatedOn            DATETIME     NOT NULL,
+    UpdatedOn            DATETIME     NULL,
+    PRIMARY KEY (Id),
+    KEY idx_vendor_accounts_lookup (Client, Channel, Vendor, Status),
+    KEY idx_vendor_accounts_key (KeyId)
+);
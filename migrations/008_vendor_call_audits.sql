@@ -0,0 +1,18 @@
+-- Redacted log of every vendor API call, purged after VENDOR_AUDIT_RETENTION_DAYS.
+
+CREATE TABLE IF NOT EXISTS VendorCallAudits (
+    Id           BIGINT        NOT NULL AUTO_INCREMENT,
+    CommId       VARCHAR(64)   NULL,
+    Vendor       VARCHAR(20)   NOT NULL,
+    Method       VARCHAR(10)   NOT NULL,
+    Endpoint     VARCHAR(2048) NOT NULL,
+    StatusCode   INT           NOT NULL DEFAULT 0,
+    LatencyMs    BIGINT        NOT NULL DEFAULT 0,
+    Error        TEXT          NULL,
+    RequestBody  MEDIUMTEXT    NULL,
+    ResponseBody MEDIUMTEXT    NULL,
+    CreatedOn    DATETIME      NOT NULL,
+    PRIMARY KEY (Id),
+    KEY idx_vendor_call_audits_comm (CommId),
+    KEY idx_vendor_call_audits_created (CreatedOn)
+);
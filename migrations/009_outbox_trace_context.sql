@@ -0,0 +1,4 @@
+-- W3C trace context of the Send that wrote each outbox row, so the relay continues its trace.
+
+ALTER TABLE ${OUTBOX_TABLE}
+    ADD COLUMN TraceContext VARCHAR(1024) NULL;
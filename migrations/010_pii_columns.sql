@@ -0,0 +1,25 @@
+-- Envelopes and keyed hashes of the personal data columns, written by pii.ProtectRow with PII_ENCRYPTION_ENABLED.
+-- The original columns keep a masked value.
+
+ALTER TABLE ${SDK_SMS_INPUT_TABLE}
+    ADD COLUMN MobileEncrypted TEXT     NULL,
+    ADD COLUMN MobileHash      CHAR(64) NULL;
+ALTER TABLE ${SDK_WHATSAPP_INPUT_TABLE}
+    ADD COLUMN MobileEncrypted TEXT     NULL,
+    ADD COLUMN MobileHash      CHAR(64) NULL;
+ALTER TABLE ${SDK_RCS_INPUT_TABLE}
+    ADD COLUMN MobileEncrypted TEXT     NULL,
+    ADD COLUMN MobileHash      CHAR(64) NULL;
+ALTER TABLE ${SDK_EMAIL_INPUT_TABLE}
+    ADD COLUMN MobileEncrypted TEXT     NULL,
+    ADD COLUMN MobileHash      CHAR(64) NULL;
+
+ALTER TABLE ${SMS_OUTPUT_TABLE}
+    ADD COLUMN MobileNumberEncrypted TEXT     NULL,
+    ADD COLUMN MobileNumberHash      CHAR(64) NULL;
+ALTER TABLE ${WHATSAPP_OUTPUT_TABLE}
+    ADD COLUMN MobileNumberEncrypted TEXT     NULL,
+    ADD COLUMN MobileNumberHash      CHAR(64) NULL;
+ALTER TABLE ${EMAIL_OUTPUT_TABLE}
+    ADD COLUMN EmailEncrypted TEXT     NULL,
+    ADD COLUMN EmailHash      CHAR(64) NULL;
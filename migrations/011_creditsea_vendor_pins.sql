@@ -0,0 +1,10 @@
+-- CreditSea was pinned to Sinch in code on every channel; pin it through client vendor rows instead.
+-- Its Sinch SMS and WhatsApp accounts are seeded into VendorAccounts by the consumer at start from the
+-- deprecated CREDITSEA_SINCH_SMS_API_* and SINCH_CREDITSEA_API_* variables, which can be removed afterwards.
+
+INSERT INTO ${VENDORS_TABLE} (Name, Channel, Client, Status, IsHealthy, Weight, CreatedOn)
+SELECT 'SINCH', pinned.Channel, 'creditsea', 1, 1, 100, NOW()
+FROM (SELECT 'SMS' AS Channel UNION ALL SELECT 'WHATSAPP' UNION ALL SELECT 'RCS') AS pinned
+WHERE NOT EXISTS (
+    SELECT 1 FROM ${VENDORS_TABLE} v WHERE v.Client = 'creditsea' AND v.Channel = pinned.Channel
+);
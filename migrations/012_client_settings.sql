@@ -0,0 +1,19 @@
+-- Client settings that were hard-coded for CreditSea: its daily WhatsApp limit, read from the retired
+-- CREDITSEA_WHATSAPP_MAX_COUNT variable, and its template fallback policy, which never switches vendor.
+-- The daily count restarts in Redis under per-client keys once the new pods run.
+
+ALTER TABLE ${CLIENTS_TABLE}
+    ADD COLUMN DailyLimit INT NOT NULL DEFAULT 0; -- WhatsApp messages per IST day; 0 = unlimited
+
+UPDATE ${CLIENTS_TABLE}
+SET DailyLimit = ${CREDITSEA_WHATSAPP_MAX_COUNT}
+WHERE Name = 'creditsea' AND Channel = 'WHATSAPP';
+
+UPDATE ${CLIENTS_TABLE}
+SET TemplateFallbackPolicy = 'EXACT,SIBLING_STAGE'
+WHERE Name = 'creditsea' AND (TemplateFallbackPolicy IS NULL OR TemplateFallbackPolicy = '');
+
+-- Sender name and reply-to address of email accounts; CreditSea's are seeded with its email account.
+ALTER TABLE VendorAccounts
+    ADD COLUMN SenderName VARCHAR(255) NULL,
+    ADD COLUMN ReplyTo    VARCHAR(255) NULL;
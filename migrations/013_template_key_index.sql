@@ -0,0 +1,5 @@
+-- Index of the template key. A template is only made live while the rows of its key are locked, and the index
+-- keeps that lock to the key rather than the whole table.
+
+ALTER TABLE ${TEMPLATE_TABLE}
+    ADD KEY idx_templates_key (Process, Stage, Client, Channel, Vendor);
@@ -0,0 +1,7 @@
+-- Webhook secrets envelope encrypted like vendor credentials. A consumer pod seals the plaintext secrets into these
+-- columns when it starts, and clears WebhookSecret once WEBHOOK_CLEAR_PLAINTEXT_SECRETS is set.
+
+ALTER TABLE ${CLIENTS_TABLE}
+    ADD COLUMN WebhookSecretEncrypted  TEXT         NULL,
+    ADD COLUMN WebhookSecretWrappedKey VARCHAR(255) NULL,
+    ADD COLUMN WebhookSecretKeyId      VARCHAR(64)  NULL;
@@ -0,0 +1,7 @@
+-- The resolution trace grows with every fallback step tried and can exceed the VARCHAR(512) of 003, which would
+-- fail the insert of the output row.
+
+ALTER TABLE ${SMS_OUTPUT_TABLE}      MODIFY COLUMN TemplateResolution TEXT NULL;
+ALTER TABLE ${WHATSAPP_OUTPUT_TABLE} MODIFY COLUMN TemplateResolution TEXT NULL;
+ALTER TABLE ${RCS_OUTPUT_TABLE}      MODIFY COLUMN TemplateResolution TEXT NULL;
+ALTER TABLE ${EMAIL_OUTPUT_TABLE}    MODIFY COLUMN TemplateResolution TEXT NULL;
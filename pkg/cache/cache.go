@@ -2,271 +2,430 @@ package cache
 
 import (
 	"fmt"
-	"log"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/dgraph-io/ristretto"
-
 	"github.com/wecredit/communication-sdk/config"
-	"github.com/wecredit/communication-sdk/internal/database"
+	"github.com/wecredit/communication-sdk/internal/models/apiModels"
 	"github.com/wecredit/communication-sdk/sdk/utils"
 	"github.com/wecredit/communication-sdk/sdk/variables"
 	"gorm.io/gorm"
 )
 
-// Cache structure with Ristretto cache store
-type Cache struct {
-	store *ristretto.Cache
+// ConfigSnapshot is a typed, read-only view of the configuration tables.
+// A published snapshot is never modified: reloads build a new one and swap it in atomically,
+// so a reader holding a snapshot sees one consistent set of templates, vendors, clients and routing slots.
+type ConfigSnapshot struct {
+	Auth           []apiModels.Userbasicauth
+	Vendors        map[string]apiModels.Vendor          // keyed by VendorCacheKey
+	Clients        map[string]apiModels.Client          // keyed by ClientCacheKey
+	Templates      map[string]apiModels.Templatedetails // keyed by TemplateCacheKey; inactive duplicates are parked under an Id suffix
+	VendorSlots    map[string]map[string][100]string    // channel -> client -> vendor for each of the 100 hash slots
+	AdminKeys      map[string]apiModels.AdminApiKey     // active admin API keys, keyed by KeyHash
+	VendorAccounts map[string]apiModels.VendorAccount   // active vendor accounts, keyed by VendorAccountCacheKey; credentials stay encrypted
+	LoadedOn       map[string]time.Time                 // dataset key -> when the dataset in this snapshot was loaded
+
+	fingerprints     map[string]string // dataset key -> table fingerprint taken before the dataset was read
+	vendorIds        map[int]string
+	clientIds        map[int]string
+	templateIds      map[int]string
+	adminKeyIds      map[int]string
+	vendorAccountIds map[int]string
 }
 
 var (
-	appCache *Cache    // Global cache variable
-	once     sync.Once // Ensure initialization happens only once
+	current  atomic.Pointer[ConfigSnapshot]
+	reloadMu sync.Mutex // serialises swaps so concurrent reloads of different datasets cannot drop each other
 )
 
-// Global cache
-var ChannelVendorSlots = map[string]map[string][100]string{} // for fast weighted lookup
-// var channelActiveVendors = map[string][]Vendor{}  // optional if needed elsewhere
+func init() {
+	current.Store(&ConfigSnapshot{})
+}
 
-type Vendor struct {
-	Name    string
-	Channel string
-	Client  string
-	Status  int
-	Weight  int64
+// Current returns the snapshot in use. It is never nil; datasets that have not loaded yet are empty.
+func Current() *ConfigSnapshot {
+	return current.Load()
 }
 
-// InitializeCache initializes the global cache instance
-func InitializeCache() {
-	once.Do(func() { // Singleton pattern to ensure only one instance is created
-		cache, err := ristretto.NewCache(&ristretto.Config{
-			NumCounters: 1e4,     // Number of keys to track for eviction
-			MaxCost:     1 << 20, // 1 MB cache size
-			BufferItems: 64,      // Number of keys per Get buffer
-		})
-		if err != nil {
-			log.Fatalf("Failed to create cache: %v", err)
-		}
-		appCache = &Cache{store: cache}
-		// log.Println("Cache initialized successfully.")
-	})
+// Loaded reports whether the dataset has been loaded into the snapshot at least once.
+func (s *ConfigSnapshot) Loaded(key string) bool {
+	_, ok := s.LoadedOn[key]
+	return ok
 }
 
-// GetCache returns the global cache instance
-func GetCache() *Cache {
-	if appCache == nil {
-		log.Println("Cache not initialized. Please call InitializeCache first.")
-		return nil
+// Client returns the client row for name and channel.
+func (s *ConfigSnapshot) Client(name, channel string) (apiModels.Client, bool) {
+	client, ok := s.Clients[ClientCacheKey(name, channel)]
+	return client, ok
+}
+
+// VendorForClient returns the vendor row configured for the client, falling back to the vendor's shared row.
+func (s *ConfigSnapshot) VendorForClient(name, channel, client string) (apiModels.Vendor, bool) {
+	channel = strings.ToUpper(strings.TrimSpace(channel))
+	client = strings.ToLower(strings.TrimSpace(client))
+
+	if vendor, ok := s.Vendors[VendorCacheKey(name, channel, client)]; ok {
+		return vendor, true
 	}
-	return appCache
+	vendor, ok := s.Vendors[VendorCacheKey(name, channel, "")]
+	return vendor, ok
 }
 
-// Set stores a list of maps in the cache with a specific key
-func (c *Cache) Set(key string, data interface{}) bool {
-	// Use a fixed cost; adjust based on your application's needs
-	return c.store.Set(key, data, 1)
+// ClientById returns the client row with the given Id.
+func (s *ConfigSnapshot) ClientById(id uint) (apiModels.Client, bool) {
+	client, ok := s.Clients[s.clientIds[int(id)]]
+	return client, ok
 }
 
-// Get fetches the data from the cache for a given key
-func (c *Cache) Get(key string) ([]map[string]interface{}, bool) {
-	value, found := c.store.Get(key)
-	if !found {
-		return nil, false
-	}
-	// Type assertion to []map[string]interface{}
-	if result, ok := value.([]map[string]interface{}); ok {
-		return result, true
-	}
-	return nil, false
+// VendorById returns the vendor row with the given Id.
+func (s *ConfigSnapshot) VendorById(id uint) (apiModels.Vendor, bool) {
+	vendor, ok := s.Vendors[s.vendorIds[int(id)]]
+	return vendor, ok
 }
 
-func storeDataIntoCache(key, tableName string, db *gorm.DB) error {
-	utils.Info(fmt.Sprintf("Initializing cache for key: %s (table: %s)", key, tableName))
+// TemplateById returns the template row with the given Id, including parked inactive rows.
+func (s *ConfigSnapshot) TemplateById(id uint) (apiModels.Templatedetails, bool) {
+	template, ok := s.Templates[s.templateIds[int(id)]]
+	return template, ok
+}
 
-	// Fetch data
-	data, err := database.GetDataFromTable(tableName, db)
-	if err != nil {
-		utils.Error(fmt.Errorf("cache init failed while fetching data (table: %s, key: %s): %v", tableName, key, err))
-		return fmt.Errorf("failed to fetch data for cache: %w", err)
+// AdminKey returns the active admin API key with the given SHA-256 hex digest.
+func (s *ConfigSnapshot) AdminKey(keyHash string) (apiModels.AdminApiKey, bool) {
+	key, ok := s.AdminKeys[keyHash]
+	return key, ok
+}
+
+// VendorAccount returns the active account of the client at the vendor, falling back to the vendor's shared account.
+func (s *ConfigSnapshot) VendorAccount(client, channel, vendor string) (apiModels.VendorAccount, bool) {
+	client = strings.ToLower(strings.TrimSpace(client))
+	channel = strings.ToUpper(strings.TrimSpace(channel))
+	vendor = strings.ToUpper(strings.TrimSpace(vendor))
+
+	if account, ok := s.VendorAccounts[VendorAccountCacheKey(client, channel, vendor)]; ok {
+		return account, true
 	}
+	account, ok := s.VendorAccounts[VendorAccountCacheKey("", channel, vendor)]
+	return account, ok
+}
 
-	if len(data) == 0 {
-		utils.Warn(fmt.Sprintf("cache init: table %s returned 0 records (key: %s)", tableName, key))
+// ClientAccountVendors returns, in name order, the vendors at which the client has an active account of its own for
+// the channel; none for a client that only sends through the vendors' shared accounts.
+func (s *ConfigSnapshot) ClientAccountVendors(client, channel string) []string {
+	client = strings.ToLower(strings.TrimSpace(client))
+	channel = strings.ToUpper(strings.TrimSpace(channel))
+	if client == "" {
+		return nil
 	}
 
-	// Try setting cache with retry
-	const maxRetries = 3
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		if GetCache().Set(key, data) {
-			utils.Info(fmt.Sprintf("Cache initialized successfully for key: %s (records: %d)", key, len(data)))
-			return nil
+	var vendors []string
+	for _, account := range s.VendorAccounts {
+		if account.Client == client && account.Channel == channel {
+			vendors = append(vendors, account.Vendor)
 		}
-
-		utils.Warn(fmt.Sprintf("attempt %d/%d failed to set cache for key: %s", attempt, maxRetries, key))
-		time.Sleep(time.Duration(attempt) * time.Second)
 	}
+	sort.Strings(vendors)
+	return vendors
+}
 
-	err = fmt.Errorf("failed to set cache for key %s after %d attempts", key, maxRetries)
-	utils.Error(err)
-	return err
+// InitializeCache publishes an empty snapshot; datasets are filled in by ReloadDataset.
+func InitializeCache() {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+	if Current().LoadedOn == nil {
+		current.Store(&ConfigSnapshot{LoadedOn: map[string]time.Time{}})
+	}
 }
 
-func StoreMappedDataIntoCache(key, tableName, columnNameToBeUsedAsKey, suffixColumnName string, db *gorm.DB) {
-	data, err := database.GetDataFromTable(tableName, db)
-	if err != nil {
-		utils.Error(fmt.Errorf("failed to fetch initial data for cache: %v", err))
-		return
+// ReloadDataset reloads a single dataset from its table and swaps it into a new snapshot.
+// If the table cannot be read, the last good snapshot stays in use; rows that fail validation are skipped and logged,
+// so one bad row does not hold back the rest of the dataset.
+func ReloadDataset(key string, db *gorm.DB) error {
+	// Taken before the rows are read, so a change made during the load is picked up by the next refresh
+	fp, fpErr := fingerprint(key, db)
+	if fpErr != nil {
+		utils.Warn(fmt.Sprintf("could not fingerprint %s, the next refresh will reload it: %v", key, fpErr))
 	}
 
-	utils.Info(fmt.Sprintf("fetched %d entries from DB", len(data)))
+	var apply func(*ConfigSnapshot)
+	var err error
+
+	switch key {
+	case AuthDetails:
+		apply, err = loadAuth(db)
+	case VendorsData:
+		apply, err = loadVendors(db)
+	case ClientsData:
+		apply, err = loadClients(db)
+	case TemplateDetailsData:
+		apply, err = loadTemplates(db)
+	case AdminKeysData:
+		apply, err = loadAdminKeys(db)
+	case VendorAccountsData:
+		apply, err = loadVendorAccounts(db)
+	default:
+		return fmt.Errorf("unknown cache key: %s", key)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load %s, keeping the last good snapshot: %w", key, err)
+	}
 
-	mappedData := make(map[string]map[string]interface{})
-	idIndex := make(map[uint]string) // Map Id to cache key
+	previous, next := swap(key, fp, apply)
+	logDiff(key, previous, next)
+	return nil
+}
 
-	for _, row := range data {
-		keyVal, ok := row[columnNameToBeUsedAsKey]
-		if !ok {
-			utils.Warn(fmt.Sprintf("skipped a row: column '%s' missing", columnNameToBeUsedAsKey))
-			continue
-		}
+// swap publishes a copy of the current snapshot with one dataset replaced and returns both snapshots.
+func swap(key, fp string, apply func(*ConfigSnapshot)) (*ConfigSnapshot, *ConfigSnapshot) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
 
-		keyStr := fmt.Sprintf("%s:%v", columnNameToBeUsedAsKey, keyVal)
-		if suffixColumnName != "" {
-			if suffixVal, ok := row[suffixColumnName]; ok && tableName == config.Configs.TemplateDetailsTable {
-				// stageFloat, _ := strconv.ParseFloat(string(suffixVal.([]uint8)), 64)
-				// stageFloat, _ := strconv.ParseFloat(suffixVal.(string), 64)
-
-				// Stage is now already parsed as float64 from database function
-				stageFloat := suffixVal.(float64)
-				keyStr = fmt.Sprintf("%s|%s:%.2f", keyStr, suffixColumnName, stageFloat)
-			} else if suffixVal, ok := row[suffixColumnName]; ok {
-				keyStr = fmt.Sprintf("%s|%s:%v", keyStr, suffixColumnName, suffixVal)
-			} else {
-				utils.Warn(fmt.Sprintf("suffix column '%s' missing for a row", suffixColumnName))
-			}
-		}
+	previous := Current()
+	next := *previous
+	next.LoadedOn = make(map[string]time.Time, len(previous.LoadedOn)+1)
+	for k, v := range previous.LoadedOn {
+		next.LoadedOn[k] = v
+	}
+	next.fingerprints = make(map[string]string, len(previous.fingerprints)+1)
+	for k, v := range previous.fingerprints {
+		next.fingerprints[k] = v
+	}
+	apply(&next)
+	next.LoadedOn[key] = time.Now()
+	next.fingerprints[key] = fp
 
-		if tableName == config.Configs.TemplateDetailsTable {
-			keyStr = fmt.Sprintf("%s|Client:%v|Channel:%v|Vendor:%v", keyStr, row["Client"], row["Channel"], row["Vendor"])
-		}
+	current.Store(&next)
+	return previous, &next
+}
 
-		if tableName == config.Configs.VendorTable {
-			clientStr := strings.ToLower(strings.TrimSpace(row["Client"].(string)))
-			keyStr = fmt.Sprintf("%s|Client:%s", keyStr, clientStr)
-		}
+func loadAuth(db *gorm.DB) (func(*ConfigSnapshot), error) {
+	var rows []apiModels.Userbasicauth
+	if err := db.Table(config.Configs.BasicAuthTableName).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		utils.Warn(fmt.Sprintf("cache load: table %s returned 0 records (key: %s)", config.Configs.BasicAuthTableName, AuthDetails))
+	}
 
-		mappedData[keyStr] = row
+	utils.Info(fmt.Sprintf("Cache loaded for key: %s (records: %d)", AuthDetails, len(rows)))
+	return func(s *ConfigSnapshot) {
+		s.Auth = rows
+	}, nil
+}
 
-		// Build Id index
-		if id, ok := row["Id"].(int64); ok {
-			idIndex[uint(id)] = keyStr
-		}
+func loadClients(db *gorm.DB) (func(*ConfigSnapshot), error) {
+	var rows []apiModels.Client
+	if err := db.Table(config.Configs.ClientsTable).Order("Id").Find(&rows).Error; err != nil {
+		return nil, err
 	}
 
-	if !GetCache().Set(key, mappedData) {
-		utils.Error(fmt.Errorf("failed to set data in cache for key: %v", key))
-		return
+	clients := make(map[string]apiModels.Client, len(rows))
+	ids := make(map[int]string, len(rows))
+	for _, client := range rows {
+		if client.Name == "" || client.Channel == "" {
+			utils.Warn(fmt.Sprintf("skipped client Id %d: Name or Channel missing", client.Id))
+			continue
+		}
+		key := ClientCacheKey(client.Name, client.Channel)
+		if existing, ok := clients[key]; ok {
+			utils.Warn(fmt.Sprintf("skipped client Id %d: shares key %s with client Id %d", client.Id, key, existing.Id))
+			continue
+		}
+		clients[key] = client
+		ids[client.Id] = key
 	}
 
-	// Store Id index
-	idIndexKey := key + ":IdIndex"
-	if !GetCache().Set(idIndexKey, idIndex) {
-		utils.Error(fmt.Errorf("failed to set Id index in cache for key: %v", idIndexKey))
-		return
-	}
+	utils.Info(fmt.Sprintf("Cache loaded for key: %s (records: %d)", ClientsData, len(clients)))
+	return func(s *ConfigSnapshot) {
+		s.Clients = clients
+		s.clientIds = ids
+	}, nil
+}
 
-	if key == VendorsData {
-		TransformAndCacheVendors(mappedData)
+func loadVendors(db *gorm.DB) (func(*ConfigSnapshot), error) {
+	var rows []apiModels.Vendor
+	if err := db.Table(config.Configs.VendorTable).Order("Id").Find(&rows).Error; err != nil {
+		return nil, err
 	}
-	utils.Info(fmt.Sprintf("Cache initialized successfully for key: %s", key))
-}
 
-func TransformAndCacheVendors(raw map[string]map[string]interface{}) {
-	temp := make(map[string]map[string][]Vendor)
-	// Step 1: Group by channel & client with only active vendors
-	for _, row := range raw {
-		status := (row["Status"].(int64))
-		if status != variables.Active {
+	vendors := make(map[string]apiModels.Vendor, len(rows))
+	ids := make(map[int]string, len(rows))
+	for _, vendor := range rows {
+		if vendor.Name == "" || vendor.Channel == "" {
+			utils.Warn(fmt.Sprintf("skipped vendor Id %d: Name or Channel missing", vendor.Id))
 			continue
 		}
-
-		name := strings.ToUpper(strings.TrimSpace(row["Name"].(string)))
-		channel := strings.ToUpper(strings.TrimSpace(row["Channel"].(string)))
-		client := strings.ToLower(strings.TrimSpace(row["Client"].(string)))
-		weight := row["Weight"].(int64)
-		if weight <= 0 {
+		if vendor.Weight < 0 || vendor.Weight > 100 {
+			utils.Warn(fmt.Sprintf("skipped vendor Id %d: weight %d outside 0-100", vendor.Id, vendor.Weight))
 			continue
 		}
-
-		if _, ok := temp[channel]; !ok {
-			temp[channel] = make(map[string][]Vendor)
+		vendor.Client = strings.ToLower(strings.TrimSpace(vendor.Client))
+		key := VendorCacheKey(vendor.Name, vendor.Channel, vendor.Client)
+		if existing, ok := vendors[key]; ok {
+			utils.Warn(fmt.Sprintf("skipped vendor Id %d: shares key %s with vendor Id %d", vendor.Id, key, existing.Id))
+			continue
 		}
+		vendors[key] = vendor
+		ids[vendor.Id] = key
+	}
+	slots := buildVendorSlots(vendors)
+
+	utils.Info(fmt.Sprintf("Cache loaded for key: %s (records: %d)", VendorsData, len(vendors)))
+	return func(s *ConfigSnapshot) {
+		s.Vendors = vendors
+		s.vendorIds = ids
+		s.VendorSlots = slots
+	}, nil
+}
 
-		v := Vendor{
-			Name:    name,
-			Channel: channel,
-			Client:  client,
-			Status:  1,
-			Weight:  weight,
+// buildVendorSlots pre-computes, for each channel and client, which active vendor serves each of the 100 hash slots.
+// Vendors are laid out in name order so every pod routes the same key to the same vendor.
+func buildVendorSlots(vendors map[string]apiModels.Vendor) map[string]map[string][100]string {
+	grouped := make(map[string]map[string][]apiModels.Vendor)
+	for _, vendor := range vendors {
+		if int64(vendor.Status) != variables.Active || vendor.Weight <= 0 {
+			continue
 		}
-		temp[channel][client] = append(temp[channel][client], v)
+		channel := strings.ToUpper(strings.TrimSpace(vendor.Channel))
+		if _, ok := grouped[channel]; !ok {
+			grouped[channel] = make(map[string][]apiModels.Vendor)
+		}
+		grouped[channel][vendor.Client] = append(grouped[channel][vendor.Client], vendor)
 	}
 
-	// Step 2: Pre-compute vendor slots for each channel & client
-	final := make(map[string]map[string][100]string)
-	for channel, clientVendors := range temp {
-		if _, ok := final[channel]; !ok {
-			final[channel] = make(map[string][100]string)
-		}
-		for client, vendors := range clientVendors {
+	slotsByChannel := make(map[string]map[string][100]string, len(grouped))
+	for channel, clientVendors := range grouped {
+		slotsByChannel[channel] = make(map[string][100]string, len(clientVendors))
+		for client, active := range clientVendors {
+			sort.Slice(active, func(i, j int) bool { return active[i].Name < active[j].Name })
+
 			var slots [100]string
-			var pos int64 = 0
-			for _, v := range vendors {
-				end := pos + v.Weight
+			pos := 0
+			for _, vendor := range active {
+				end := pos + vendor.Weight
 				if end > 100 {
+					utils.Warn(fmt.Sprintf("active vendor weights exceed 100 for channel %s and client %q; %s is truncated", channel, client, vendor.Name))
 					end = 100
 				}
 				for i := pos; i < end; i++ {
-					slots[i] = v.Name
+					slots[i] = strings.ToUpper(strings.TrimSpace(vendor.Name))
 				}
 				pos = end
 				if pos >= 100 {
 					break
 				}
 			}
-			final[channel][client] = slots
+			slotsByChannel[channel][client] = slots
 		}
 	}
+	return slotsByChannel
+}
 
-	// Step 3: Store in global vars
-	ChannelVendorSlots = final
+func loadTemplates(db *gorm.DB) (func(*ConfigSnapshot), error) {
+	var rows []apiModels.Templatedetails
+	if err := db.Table(config.Configs.TemplateDetailsTable).Order("id").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	templates := make(map[string]apiModels.Templatedetails, len(rows))
+	ids := make(map[int]string, len(rows))
+	for _, template := range rows {
+		key := TemplateCacheKey(template.Process, template.Stage, template.Client, template.Channel, template.Vendor)
+
+		// Several template rows can share a key; the active one keeps the key and the others are parked under
+		// an Id suffixed key so they stay reachable by Id without ever being matched for sending
+		if existing, ok := templates[key]; ok {
+			switch {
+			case template.IsActive && existing.IsActive:
+				// The older template keeps serving; the newer one is only reachable by Id until one is deactivated
+				utils.Warn(fmt.Sprintf("parked template Id %d: template Id %d is active for %s too", template.Id, existing.Id, key))
+				key = parkedTemplateKey(key, template.Id)
+			case template.IsActive:
+				parked := parkedTemplateKey(key, existing.Id)
+				templates[parked] = existing
+				ids[existing.Id] = parked
+			default:
+				key = parkedTemplateKey(key, template.Id)
+			}
+		}
+		templates[key] = template
+		ids[template.Id] = key
+	}
+
+	utils.Info(fmt.Sprintf("Cache loaded for key: %s (records: %d)", TemplateDetailsData, len(templates)))
+	return func(s *ConfigSnapshot) {
+		s.Templates = templates
+		s.templateIds = ids
+	}, nil
 }
 
-// Get fetches the data from the cache for a given key
-func (c *Cache) GetMappedData(key string) (map[string]map[string]interface{}, bool) {
-	value, found := c.store.Get(key)
-	if !found {
-		fmt.Println("Cache key not found:", key)
-		return nil, false
+func loadAdminKeys(db *gorm.DB) (func(*ConfigSnapshot), error) {
+	var rows []apiModels.AdminApiKey
+	if err := db.Table(config.Configs.AdminApiKeyTable).Where("Status = ?", variables.Active).Order("Id").Find(&rows).Error; err != nil {
+		return nil, err
 	}
 
-	// Convert slice to map using lender names as keys
-	mappedData := value.(map[string]map[string]interface{})
+	keys := make(map[string]apiModels.AdminApiKey, len(rows))
+	ids := make(map[int]string, len(rows))
+	for _, key := range rows {
+		key.KeyHash = strings.ToLower(strings.TrimSpace(key.KeyHash))
+		key.Client = strings.ToLower(strings.TrimSpace(key.Client))
+		switch {
+		case key.KeyHash == "":
+			utils.Warn(fmt.Sprintf("skipped admin API key Id %d: KeyHash missing", key.Id))
+			continue
+		case key.Role != variables.RoleViewer && key.Role != variables.RoleTemplateEditor && key.Role != variables.RoleOpsAdmin:
+			utils.Warn(fmt.Sprintf("skipped admin API key Id %d: unknown role %q", key.Id, key.Role))
+			continue
+		case key.Role == variables.RoleOpsAdmin && key.Client != "":
+			utils.Warn(fmt.Sprintf("skipped admin API key Id %d: an ops-admin key cannot be scoped to a client", key.Id))
+			continue
+		}
+		if existing, ok := keys[key.KeyHash]; ok {
+			utils.Warn(fmt.Sprintf("skipped admin API key Id %d: shares its hash with key Id %d", key.Id, existing.Id))
+			continue
+		}
+		keys[key.KeyHash] = key
+		ids[key.Id] = key.KeyHash
+	}
 
-	return mappedData, true
+	utils.Info(fmt.Sprintf("Cache loaded for key: %s (records: %d)", AdminKeysData, len(keys)))
+	return func(s *ConfigSnapshot) {
+		s.AdminKeys = keys
+		s.adminKeyIds = ids
+	}, nil
 }
 
-func (c *Cache) GetMappedIdData(key string) (map[uint]string, bool) {
-	value, found := c.store.Get(key)
-	if !found {
-		utils.Error(fmt.Errorf("Cache key not found: %s", key))
-		return nil, false
+func loadVendorAccounts(db *gorm.DB) (func(*ConfigSnapshot), error) {
+	var rows []apiModels.VendorAccount
+	if err := db.Table(config.Configs.VendorAccountTable).Where("Status = ?", variables.Active).Order("Id").Find(&rows).Error; err != nil {
+		return nil, err
 	}
 
-	// Convert slice to map using lender names as keys
-	mappedData := value.(map[uint]string)
+	accounts := make(map[string]apiModels.VendorAccount, len(rows))
+	ids := make(map[int]string, len(rows))
+	for _, account := range rows {
+		account.Client = strings.ToLower(strings.TrimSpace(account.Client))
+		account.Channel = strings.ToUpper(strings.TrimSpace(account.Channel))
+		account.Vendor = strings.ToUpper(strings.TrimSpace(account.Vendor))
+		if account.Channel == "" || account.Vendor == "" {
+			utils.Warn(fmt.Sprintf("skipped vendor account Id %d: Channel or Vendor missing", account.Id))
+			continue
+		}
+		key := VendorAccountCacheKey(account.Client, account.Channel, account.Vendor)
+		if existing, ok := accounts[key]; ok {
+			utils.Warn(fmt.Sprintf("skipped vendor account Id %d: shares key %s with vendor account Id %d", account.Id, key, existing.Id))
+			continue
+		}
+		accounts[key] = account
+		ids[account.Id] = key
+	}
 
-	return mappedData, true
+	utils.Info(fmt.Sprintf("Cache loaded for key: %s (records: %d)", VendorAccountsData, len(accounts)))
+	return func(s *ConfigSnapshot) {
+		s.VendorAccounts = accounts
+		s.vendorAccountIds = ids
+	}, nil
 }
@@ -0,0 +1,170 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/wecredit/communication-sdk/config"
+	"github.com/wecredit/communication-sdk/internal/database"
+	"github.com/wecredit/communication-sdk/internal/redis"
+	"github.com/wecredit/communication-sdk/sdk/utils"
+	"gorm.io/gorm"
+)
+
+// ChangeEvent is published on the invalidation channel whenever a pod changes a cached dataset.
+type ChangeEvent struct {
+	Key         string    `json:"key"`
+	Version     int64     `json:"version"`
+	Origin      string    `json:"origin"`
+	PublishedOn time.Time `json:"publishedOn"`
+}
+
+var (
+	versionsMu sync.RWMutex
+	versions   = map[string]int64{} // dataset key -> version of the snapshot this pod holds
+
+	podId = func() string {
+		host, _ := os.Hostname()
+		return fmt.Sprintf("%s-%d", host, os.Getpid())
+	}()
+)
+
+// invalidatedKeys are the datasets that can be reloaded through change events.
+var invalidatedKeys = []string{AuthDetails, VendorsData, ClientsData, TemplateDetailsData, AdminKeysData, VendorAccountsData}
+
+// Refresh reloads key in this pod and tells every other pod to do the same.
+// It is called after admin writes; db should be the connection the write went through.
+func Refresh(key string, db *gorm.DB) {
+	if err := ReloadDataset(key, db); err != nil {
+		utils.Error(fmt.Errorf("failed to reload cache for key %s: %v", key, err))
+		return
+	}
+
+	if err := publishChange(context.Background(), key); err != nil {
+		utils.Error(fmt.Errorf("failed to publish cache change for key %s: %v", key, err))
+	}
+}
+
+func publishChange(ctx context.Context, key string) error {
+	if redis.RDB == nil {
+		return fmt.Errorf("redis client not initialized")
+	}
+
+	version, err := redis.RDB.Incr(ctx, redis.CacheVersionKey(key)).Result()
+	if err != nil {
+		return err
+	}
+	setVersion(key, version)
+
+	event := ChangeEvent{Key: key, Version: version, Origin: podId, PublishedOn: time.Now().UTC()}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	if err := redis.RDB.Publish(ctx, config.Configs.CacheInvalidationChannel, payload).Err(); err != nil {
+		return err
+	}
+	utils.Debug(fmt.Sprintf("Published cache change for key %s at version %d", key, version))
+	return nil
+}
+
+// StartInvalidationSubscriber reloads datasets when other pods publish changes, until ctx is cancelled.
+func StartInvalidationSubscriber(ctx context.Context) {
+	if redis.RDB == nil {
+		utils.Error(fmt.Errorf("cache invalidation subscriber not started: redis client not initialized"))
+		return
+	}
+
+	pubsub := redis.RDB.Subscribe(ctx, config.Configs.CacheInvalidationChannel)
+	defer pubsub.Close()
+	if _, err := pubsub.Receive(ctx); err != nil {
+		utils.Error(fmt.Errorf("failed to subscribe to cache invalidation channel: %v", err))
+		return
+	}
+
+	// Changes published between the startup load and the subscription would otherwise be missed; like change
+	// events, they are read from the primary, which the replica may not have caught up with yet
+	syncVersions(ctx)
+	for _, key := range invalidatedKeys {
+		if err := ReloadDataset(key, database.DBtechWrite); err != nil {
+			utils.Error(fmt.Errorf("failed to reload cache for key %s after subscribing: %v", key, err))
+		}
+	}
+	utils.Info(fmt.Sprintf("Subscribed to cache invalidation channel %s as %s", config.Configs.CacheInvalidationChannel, podId))
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case message, ok := <-messages:
+			if !ok {
+				return
+			}
+			handleChangeEvent(message.Payload)
+		}
+	}
+}
+
+func handleChangeEvent(payload string) {
+	var event ChangeEvent
+	if err := json.Unmarshal([]byte(payload), &event); err != nil {
+		utils.Error(fmt.Errorf("invalid cache change event %q: %v", payload, err))
+		return
+	}
+
+	// The publishing pod has already reloaded, and an older event must not roll a newer snapshot back
+	if event.Origin == podId || event.Version <= Version(event.Key) {
+		return
+	}
+
+	// The event follows a write to the primary, which the replica may not have applied yet
+	if err := ReloadDataset(event.Key, database.DBtechWrite); err != nil {
+		utils.Error(fmt.Errorf("failed to reload cache for key %s on change event: %v", event.Key, err))
+		return
+	}
+	setVersion(event.Key, event.Version)
+	utils.Info(fmt.Sprintf("Cache reloaded for key %s at version %d (published by %s)", event.Key, event.Version, event.Origin))
+}
+
+// syncVersions records the published versions of the snapshots loaded at startup.
+func syncVersions(ctx context.Context) {
+	for _, key := range invalidatedKeys {
+		version, err := redis.RDB.Get(ctx, redis.CacheVersionKey(key)).Int64()
+		if err != nil {
+			continue // no change published yet
+		}
+		setVersion(key, version)
+	}
+}
+
+func setVersion(key string, version int64) {
+	versionsMu.Lock()
+	defer versionsMu.Unlock()
+	if version > versions[key] {
+		versions[key] = version
+	}
+}
+
+// Version returns the version of the snapshot this pod holds for key.
+func Version(key string) int64 {
+	versionsMu.RLock()
+	defer versionsMu.RUnlock()
+	return versions[key]
+}
+
+// Versions returns the snapshot version held for every invalidated dataset.
+func Versions() map[string]int64 {
+	versionsMu.RLock()
+	defer versionsMu.RUnlock()
+	result := make(map[string]int64, len(invalidatedKeys))
+	for _, key := range invalidatedKeys {
+		result[key] = versions[key]
+	}
+	return result
+}
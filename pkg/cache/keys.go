@@ -8,6 +8,8 @@ var (
 	VendorsData         string = "vendorsData"
 	ClientsData         string = "clientsData"
 	TemplateDetailsData string = "templateDetailsData"
+	AdminKeysData       string = "adminKeysData"
+	VendorAccountsData  string = "vendorAccountsData"
 	ActiveVendors       string = "activeVendors"
 	RcsTemplateAppData  string = "rcsTemplateAppData"
 )
@@ -19,3 +21,28 @@ func GetRankKey(subLenderId int) string {
 func GetVendorKey(vendorName, channelName string) string {
 	return fmt.Sprintf("%s_%s", vendorName, channelName)
 }
+
+// ClientCacheKey is the key of a client row in ConfigSnapshot.Clients.
+func ClientCacheKey(name, channel string) string {
+	return fmt.Sprintf("Name:%s|Channel:%s", name, channel)
+}
+
+// VendorCacheKey is the key of a vendor row in ConfigSnapshot.Vendors; an empty client is the vendor's shared row.
+func VendorCacheKey(name, channel, client string) string {
+	return fmt.Sprintf("Name:%s|Channel:%s|Client:%s", name, channel, client)
+}
+
+// VendorAccountCacheKey is the key of a vendor account in ConfigSnapshot.VendorAccounts; an empty client is the shared account.
+func VendorAccountCacheKey(client, channel, vendor string) string {
+	return fmt.Sprintf("Client:%s|Channel:%s|Vendor:%s", client, channel, vendor)
+}
+
+// TemplateCacheKey is the key of the active template in ConfigSnapshot.Templates.
+func TemplateCacheKey(process string, stage float64, client, channel, vendor string) string {
+	return fmt.Sprintf("Process:%s|Stage:%.2f|Client:%s|Channel:%s|Vendor:%s", process, stage, client, channel, vendor)
+}
+
+// parkedTemplateKey keeps an inactive template that shares a key with another one reachable by Id.
+func parkedTemplateKey(key string, id int) string {
+	return fmt.Sprintf("%s|Id:%d", key, id)
+}
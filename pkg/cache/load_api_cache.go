@@ -8,12 +8,14 @@ import (
 
 func LoadApiDataIntoCache(config models.Config) {
 	// Initializing Cache Items
-	utils.Info("Initializing Ristretto cache...")
+	utils.Info("Initializing config snapshot...")
 
 	// Initialize the global cache
 	InitializeCache()
 
 	// Store auth data into cache
-	storeDataIntoCache(AuthDetails, config.BasicAuthTableName, database.DBtechRead)
+	if err := ReloadDataset(AuthDetails, database.DBtechRead); err != nil {
+		utils.Error(err)
+	}
 
 }
@@ -8,27 +8,15 @@ import (
 
 func LoadConsumerDataIntoCache(config models.Config) {
 	// Initializing Cache Items
-	utils.Info("Initializing Ristretto cache...")
+	utils.Info("Initializing config snapshot...")
 
 	// Initialize the global cache
 	InitializeCache()
 
-	// Store auth data into cache
-	storeDataIntoCache(AuthDetails, config.BasicAuthTableName, database.DBtechRead)
-
-	// Store Vendors Data into cache
-	StoreMappedDataIntoCache(VendorsData, config.VendorTable, "Name", "Channel", database.DBtechRead)
-
-	StoreMappedDataIntoCache(ClientsData, config.ClientsTable, "Name", "Channel", database.DBtechRead)
-
-	StoreMappedDataIntoCache(TemplateDetailsData, config.TemplateDetailsTable, "Process", "Stage", database.DBtechRead)
-
-	// storeDataIntoCache(ActiveVendors, config.VendorTable, database.DBtechRead)
-
-	// Store auth data into cache
-	// StoreMappedDataIntoCache(RcsTemplateAppData, config.RcsTemplateAppIdTable, "AppId", "", database.DBtechRead)
-
-	// Store Vendors Data into cache
-	// StoreMappedDataIntoCache(RcsTemplateAppData, config.RcsTemplateAppIdTable, "AppId", "", database.DBtech)
-
+	// Auth, vendors (with their routing slots), clients, templates, admin API keys and vendor accounts
+	for _, key := range []string{AuthDetails, VendorsData, ClientsData, TemplateDetailsData, AdminKeysData, VendorAccountsData} {
+		if err := ReloadDataset(key, database.DBtechRead); err != nil {
+			utils.Error(err)
+		}
+	}
 }
@@ -0,0 +1,337 @@
+package cache
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wecredit/communication-sdk/config"
+	"github.com/wecredit/communication-sdk/internal/database"
+	"github.com/wecredit/communication-sdk/internal/models/apiModels"
+	"github.com/wecredit/communication-sdk/sdk/utils"
+	"gorm.io/gorm"
+)
+
+// DatasetFreshness reports how current a cached dataset is, as shown in /health.
+type DatasetFreshness struct {
+	Records             int        `json:"records"`
+	LoadedOn            *time.Time `json:"loadedOn,omitempty"`
+	CheckedOn           *time.Time `json:"checkedOn,omitempty"`
+	AgeSeconds          int64      `json:"ageSeconds"`
+	RefreshSeconds      int        `json:"refreshSeconds,omitempty"`
+	ConsecutiveFailures int        `json:"consecutiveFailures,omitempty"`
+	Stale               bool       `json:"stale"`
+	LastError           string     `json:"lastError,omitempty"`
+}
+
+type refreshStatus struct {
+	interval  time.Duration
+	startedOn time.Time
+	checkedOn time.Time
+	lastError string
+	failures  int // consecutive failed checks
+}
+
+var (
+	refreshMu sync.RWMutex
+	refreshes = map[string]*refreshStatus{} // dataset key -> state of its refresh loop
+)
+
+// fingerprintColumns are the change tracking columns of each table; a table without UpdatedOn is reloaded on every refresh.
+var fingerprintColumns = map[string]string{
+	VendorsData:         "COUNT(*), COALESCE(MAX(Id), 0), MAX(COALESCE(UpdatedOn, CreatedOn))",
+	ClientsData:         "COUNT(*), COALESCE(MAX(Id), 0), MAX(COALESCE(UpdatedOn, CreatedOn))",
+	TemplateDetailsData: "COUNT(*), COALESCE(MAX(Id), 0), MAX(COALESCE(UpdatedOn, CreatedOn))",
+	AdminKeysData:       "COUNT(*), COALESCE(MAX(Id), 0), MAX(COALESCE(UpdatedOn, CreatedOn))",
+	VendorAccountsData:  "COUNT(*), COALESCE(MAX(Id), 0), MAX(COALESCE(UpdatedOn, CreatedOn))",
+}
+
+func tableForKey(key string) string {
+	switch key {
+	case AuthDetails:
+		return config.Configs.BasicAuthTableName
+	case VendorsData:
+		return config.Configs.VendorTable
+	case ClientsData:
+		return config.Configs.ClientsTable
+	case TemplateDetailsData:
+		return config.Configs.TemplateDetailsTable
+	case AdminKeysData:
+		return config.Configs.AdminApiKeyTable
+	case VendorAccountsData:
+		return config.Configs.VendorAccountTable
+	}
+	return ""
+}
+
+// fingerprint summarises a table by row count, highest Id and latest UpdatedOn, so inserts, deletes and
+// updates that set UpdatedOn change it. An empty fingerprint means changes cannot be detected for the table.
+func fingerprint(key string, db *gorm.DB) (string, error) {
+	columns, ok := fingerprintColumns[key]
+	if !ok {
+		return "", nil
+	}
+
+	var count, maxId int64
+	var lastModified sql.NullString
+	row := db.Raw(fmt.Sprintf("SELECT %s FROM %s", columns, tableForKey(key))).Row()
+	if err := row.Scan(&count, &maxId, &lastModified); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d/%d/%s", count, maxId, lastModified.String), nil
+}
+
+// refreshIntervals returns the configured refresh interval of each dataset.
+func refreshIntervals() map[string]int {
+	fallback := secondsOrDefault(config.Configs.CacheRefreshSeconds, 300)
+	return map[string]int{
+		AuthDetails:         secondsOrDefault(config.Configs.AuthRefreshSeconds, fallback),
+		VendorsData:         secondsOrDefault(config.Configs.VendorsRefreshSeconds, fallback),
+		ClientsData:         secondsOrDefault(config.Configs.ClientsRefreshSeconds, fallback),
+		TemplateDetailsData: secondsOrDefault(config.Configs.TemplatesRefreshSeconds, fallback),
+		AdminKeysData:       secondsOrDefault(config.Configs.AdminKeysRefreshSeconds, fallback),
+		VendorAccountsData:  secondsOrDefault(config.Configs.VendorAccountsRefreshSeconds, fallback),
+	}
+}
+
+func secondsOrDefault(value string, defaultSeconds int) int {
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds <= 0 {
+		return defaultSeconds
+	}
+	return seconds
+}
+
+// StartPeriodicRefresh refreshes every dataset with a configured table on its own interval until ctx is cancelled,
+// so direct database edits and change events a pod missed still reach the snapshot, and a dataset that failed to
+// load at startup is loaded once the database recovers. Tables are read from the primary, as change events are.
+func StartPeriodicRefresh(ctx context.Context) {
+	for key, seconds := range refreshIntervals() {
+		if tableForKey(key) == "" {
+			continue
+		}
+		interval := time.Duration(seconds) * time.Second
+
+		refreshMu.Lock()
+		refreshes[key] = &refreshStatus{interval: interval, startedOn: time.Now()}
+		refreshMu.Unlock()
+
+		utils.Info(fmt.Sprintf("Starting cache refresh for key %s every %s", key, interval))
+		go refreshLoop(ctx, key, interval)
+	}
+}
+
+func refreshLoop(ctx context.Context, key string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := refreshIfChanged(key, database.DBtechWrite)
+			if err != nil {
+				utils.Error(fmt.Errorf("periodic cache refresh failed for key %s: %v", key, err))
+			}
+			recordCheck(key, err)
+		}
+	}
+}
+
+// refreshIfChanged reloads the dataset when its table fingerprint differs from the one the snapshot was loaded with.
+func refreshIfChanged(key string, db *gorm.DB) error {
+	fp, err := fingerprint(key, db)
+	if err != nil {
+		return err
+	}
+	if fp != "" && fp == Current().fingerprints[key] {
+		return nil
+	}
+	return ReloadDataset(key, db)
+}
+
+func recordCheck(key string, err error) {
+	refreshMu.Lock()
+	defer refreshMu.Unlock()
+
+	status, ok := refreshes[key]
+	if !ok {
+		return
+	}
+	status.checkedOn = time.Now()
+	if err != nil {
+		status.lastError = err.Error()
+		status.failures++
+		return
+	}
+	status.lastError = ""
+	status.failures = 0
+}
+
+// Freshness reports every loaded or refreshed dataset. A refreshed dataset is stale once
+// CACHE_REFRESH_FAILURE_THRESHOLD checks in a row failed, or no check has succeeded for one interval more than that,
+// so a single failed query does not take the pod out of service.
+func Freshness() map[string]DatasetFreshness {
+	snapshot := Current()
+	now := time.Now()
+	threshold := secondsOrDefault(config.Configs.CacheRefreshFailureThreshold, 3)
+
+	refreshMu.RLock()
+	defer refreshMu.RUnlock()
+
+	result := make(map[string]DatasetFreshness, len(invalidatedKeys))
+	for _, key := range invalidatedKeys {
+		loadedOn, loaded := snapshot.LoadedOn[key]
+		status, refreshed := refreshes[key]
+		if !loaded && !refreshed {
+			continue
+		}
+
+		freshness := DatasetFreshness{Records: snapshot.records(key)}
+		if loaded {
+			freshness.LoadedOn = &loadedOn
+			freshness.AgeSeconds = int64(now.Sub(loadedOn).Seconds())
+		}
+
+		if refreshed {
+			freshness.RefreshSeconds = int(status.interval.Seconds())
+			freshness.LastError = status.lastError
+			freshness.ConsecutiveFailures = status.failures
+
+			// A dataset that never loaded has been missing since the start of its refresh loop
+			lastGood := loadedOn
+			if !loaded {
+				lastGood = status.startedOn
+			}
+			if !status.checkedOn.IsZero() {
+				checkedOn := status.checkedOn
+				freshness.CheckedOn = &checkedOn
+				if status.lastError == "" && checkedOn.After(lastGood) {
+					lastGood = checkedOn
+				}
+			}
+			freshness.Stale = status.failures >= threshold || now.Sub(lastGood) > time.Duration(threshold+1)*status.interval
+		}
+
+		result[key] = freshness
+	}
+	return result
+}
+
+func (s *ConfigSnapshot) records(key string) int {
+	switch key {
+	case AuthDetails:
+		return len(s.Auth)
+	case VendorsData:
+		return len(s.Vendors)
+	case ClientsData:
+		return len(s.Clients)
+	case TemplateDetailsData:
+		return len(s.Templates)
+	case AdminKeysData:
+		return len(s.AdminKeys)
+	case VendorAccountsData:
+		return len(s.VendorAccounts)
+	}
+	return 0
+}
+
+// logDiff logs which rows of the dataset were added, updated or removed by a reload.
+func logDiff(key string, previous, next *ConfigSnapshot) {
+	if !previous.Loaded(key) {
+		return // initial load
+	}
+
+	var added, updated, removed []string
+	switch key {
+	case AuthDetails:
+		// Only usernames are logged; credentials never are
+		added, updated, removed = diffRows(authById(previous.Auth), authById(next.Auth), func(a apiModels.Userbasicauth) string { return a.Username })
+	case VendorsData:
+		added, updated, removed = diffRows(byId(previous.Vendors, previous.vendorIds), byId(next.Vendors, next.vendorIds), func(v apiModels.Vendor) string {
+			return VendorCacheKey(v.Name, v.Channel, v.Client)
+		})
+	case ClientsData:
+		added, updated, removed = diffRows(byId(previous.Clients, previous.clientIds), byId(next.Clients, next.clientIds), func(c apiModels.Client) string {
+			return ClientCacheKey(c.Name, c.Channel)
+		})
+	case TemplateDetailsData:
+		added, updated, removed = diffRows(byId(previous.Templates, previous.templateIds), byId(next.Templates, next.templateIds), func(t apiModels.Templatedetails) string {
+			return TemplateCacheKey(t.Process, t.Stage, t.Client, t.Channel, t.Vendor)
+		})
+	case AdminKeysData:
+		// Only names and roles are logged; key hashes never are
+		added, updated, removed = diffRows(byId(previous.AdminKeys, previous.adminKeyIds), byId(next.AdminKeys, next.adminKeyIds), func(k apiModels.AdminApiKey) string {
+			return fmt.Sprintf("%s (%s)", k.Name, k.Role)
+		})
+	case VendorAccountsData:
+		// Only the account identity is logged; credentials never are
+		added, updated, removed = diffRows(byId(previous.VendorAccounts, previous.vendorAccountIds), byId(next.VendorAccounts, next.vendorAccountIds), func(a apiModels.VendorAccount) string {
+			return VendorAccountCacheKey(a.Client, a.Channel, a.Vendor)
+		})
+	}
+
+	if len(added)+len(updated)+len(removed) == 0 {
+		utils.Debug(fmt.Sprintf("Cache reloaded for key %s with no changes", key))
+		return
+	}
+	utils.Info(fmt.Sprintf("Cache changed for key %s: added [%s], updated [%s], removed [%s]",
+		key, strings.Join(added, ", "), strings.Join(updated, ", "), strings.Join(removed, ", ")))
+}
+
+func byId[T any](rows map[string]T, ids map[int]string) map[int]T {
+	result := make(map[int]T, len(ids))
+	for id, key := range ids {
+		if row, ok := rows[key]; ok {
+			result[id] = row
+		}
+	}
+	return result
+}
+
+func authById(rows []apiModels.Userbasicauth) map[int]apiModels.Userbasicauth {
+	result := make(map[int]apiModels.Userbasicauth, len(rows))
+	for _, row := range rows {
+		result[row.Id] = row
+	}
+	return result
+}
+
+// diffRows compares two versions of a dataset by Id and describes each changed row as "Id <id> <label>".
+func diffRows[T any](before, after map[int]T, label func(T) string) (added, updated, removed []string) {
+	describe := func(id int, row T) string {
+		return fmt.Sprintf("Id %d %s", id, label(row))
+	}
+
+	for _, id := range sortedIds(after) {
+		old, ok := before[id]
+		switch {
+		case !ok:
+			added = append(added, describe(id, after[id]))
+		case !reflect.DeepEqual(old, after[id]):
+			updated = append(updated, describe(id, after[id]))
+		}
+	}
+	for _, id := range sortedIds(before) {
+		if _, ok := after[id]; !ok {
+			removed = append(removed, describe(id, before[id]))
+		}
+	}
+	return added, updated, removed
+}
+
+func sortedIds[T any](rows map[int]T) []int {
+	ids := make([]int, 0, len(rows))
+	for id := range rows {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFreshnessAfterRepeatedFailures(t *testing.T) {
+	refreshMu.Lock()
+	previous := refreshes
+	refreshes = map[string]*refreshStatus{VendorsData: {interval: time.Hour, startedOn: time.Now()}}
+	refreshMu.Unlock()
+	t.Cleanup(func() {
+		refreshMu.Lock()
+		refreshes = previous
+		refreshMu.Unlock()
+	})
+
+	failure := errors.New("connection refused")
+	for i := 1; i <= 3; i++ {
+		recordCheck(VendorsData, failure)
+		freshness := Freshness()[VendorsData]
+		if freshness.ConsecutiveFailures != i {
+			t.Fatalf("after %d failures: ConsecutiveFailures = %d", i, freshness.ConsecutiveFailures)
+		}
+		// The dataset never loaded, so it is stale once the default threshold of 3 is reached
+		if want := i >= 3; freshness.Stale != want {
+			t.Fatalf("after %d failures: Stale = %v, want %v", i, freshness.Stale, want)
+		}
+	}
+
+	recordCheck(VendorsData, nil)
+	if freshness := Freshness()[VendorsData]; freshness.ConsecutiveFailures != 0 || freshness.LastError != "" || freshness.Stale {
+		t.Fatalf("expected a successful check to reset the failures, got %+v", freshness)
+	}
+}
+
+func TestFreshnessOfDatasetNeverLoaded(t *testing.T) {
+	refreshMu.Lock()
+	previous := refreshes
+	refreshes = map[string]*refreshStatus{VendorsData: {interval: time.Minute, startedOn: time.Now().Add(-5 * time.Minute)}}
+	refreshMu.Unlock()
+	t.Cleanup(func() {
+		refreshMu.Lock()
+		refreshes = previous
+		refreshMu.Unlock()
+	})
+
+	if freshness := Freshness()[VendorsData]; !freshness.Stale {
+		t.Fatalf("expected a dataset missing for more than (threshold+1) intervals to be stale, got %+v", freshness)
+	}
+}
@@ -0,0 +1,817 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: communication/v1/communication.proto
+
+package commpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Message struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Mobile      string                 `protobuf:"bytes,1,opt,name=mobile,proto3" json:"mobile,omitempty"`
+	Email       string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	Channel     string                 `protobuf:"bytes,3,opt,name=channel,proto3" json:"channel,omitempty"`
+	ProcessName string                 `protobuf:"bytes,4,opt,name=process_name,json=processName,proto3" json:"process_name,omitempty"`
+	Stage       float64                `protobuf:"fixed64,5,opt,name=stage,proto3" json:"stage,omitempty"`
+	IsPriority  bool                   `protobuf:"varint,6,opt,name=is_priority,json=isPriority,proto3" json:"is_priority,omitempty"`
+	// Template variables, keyed by the names in the template's TemplateVariables.
+	Variables     map[string]string `protobuf:"bytes,7,rep,name=variables,proto3" json:"variables,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Description   string            `protobuf:"bytes,8,opt,name=description,proto3" json:"description,omitempty"`
+	PaymentLink   string            `protobuf:"bytes,9,opt,name=payment_link,json=paymentLink,proto3" json:"payment_link,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Message) Reset() {
+	*x = Message{}
+	mi := &file_communication_v1_communication_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Message) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Message) ProtoMessage() {}
+
+func (x *Message) ProtoReflect() protoreflect.Message {
+	mi := &file_communication_v1_communication_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Message.ProtoReflect.Descriptor instead.
+func (*Message) Descriptor() ([]byte, []int) {
+	return file_communication_v1_communication_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Message) GetMobile() string {
+	if x != nil {
+		return x.Mobile
+	}
+	return ""
+}
+
+func (x *Message) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *Message) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+func (x *Message) GetProcessName() string {
+	if x != nil {
+		return x.ProcessName
+	}
+	return ""
+}
+
+func (x *Message) GetStage() float64 {
+	if x != nil {
+		return x.Stage
+	}
+	return 0
+}
+
+func (x *Message) GetIsPriority() bool {
+	if x != nil {
+		return x.IsPriority
+	}
+	return false
+}
+
+func (x *Message) GetVariables() map[string]string {
+	if x != nil {
+		return x.Variables
+	}
+	return nil
+}
+
+func (x *Message) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Message) GetPaymentLink() string {
+	if x != nil {
+		return x.PaymentLink
+	}
+	return ""
+}
+
+type SendRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       *Message               `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendRequest) Reset() {
+	*x = SendRequest{}
+	mi := &file_communication_v1_communication_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendRequest) ProtoMessage() {}
+
+func (x *SendRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_communication_v1_communication_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendRequest.ProtoReflect.Descriptor instead.
+func (*SendRequest) Descriptor() ([]byte, []int) {
+	return file_communication_v1_communication_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SendRequest) GetMessage() *Message {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+type SendResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CommId        string                 `protobuf:"bytes,1,opt,name=comm_id,json=commId,proto3" json:"comm_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendResponse) Reset() {
+	*x = SendResponse{}
+	mi := &file_communication_v1_communication_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendResponse) ProtoMessage() {}
+
+func (x *SendResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_communication_v1_communication_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendResponse.ProtoReflect.Descriptor instead.
+func (*SendResponse) Descriptor() ([]byte, []int) {
+	return file_communication_v1_communication_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SendResponse) GetCommId() string {
+	if x != nil {
+		return x.CommId
+	}
+	return ""
+}
+
+type SendBatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Messages      []*Message             `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendBatchRequest) Reset() {
+	*x = SendBatchRequest{}
+	mi := &file_communication_v1_communication_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendBatchRequest) ProtoMessage() {}
+
+func (x *SendBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_communication_v1_communication_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendBatchRequest.ProtoReflect.Descriptor instead.
+func (*SendBatchRequest) Descriptor() ([]byte, []int) {
+	return file_communication_v1_communication_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SendBatchRequest) GetMessages() []*Message {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+type SendBatchResult struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Index   int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	CommId  string                 `protobuf:"bytes,2,opt,name=comm_id,json=commId,proto3" json:"comm_id,omitempty"`
+	Success bool                   `protobuf:"varint,3,opt,name=success,proto3" json:"success,omitempty"`
+	// gRPC status code of the message: OK, INVALID_ARGUMENT, ALREADY_EXISTS, PERMISSION_DENIED, UNAVAILABLE or INTERNAL.
+	// UNAVAILABLE means the duplicate check could not be made and the message was not sent; it can be retried.
+	Code          int32  `protobuf:"varint,4,opt,name=code,proto3" json:"code,omitempty"`
+	Error         string `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendBatchResult) Reset() {
+	*x = SendBatchResult{}
+	mi := &file_communication_v1_communication_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendBatchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendBatchResult) ProtoMessage() {}
+
+func (x *SendBatchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_communication_v1_communication_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendBatchResult.ProtoReflect.Descriptor instead.
+func (*SendBatchResult) Descriptor() ([]byte, []int) {
+	return file_communication_v1_communication_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SendBatchResult) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *SendBatchResult) GetCommId() string {
+	if x != nil {
+		return x.CommId
+	}
+	return ""
+}
+
+func (x *SendBatchResult) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *SendBatchResult) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *SendBatchResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type SendBatchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*SendBatchResult     `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendBatchResponse) Reset() {
+	*x = SendBatchResponse{}
+	mi := &file_communication_v1_communication_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendBatchResponse) ProtoMessage() {}
+
+func (x *SendBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_communication_v1_communication_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendBatchResponse.ProtoReflect.Descriptor instead.
+func (*SendBatchResponse) Descriptor() ([]byte, []int) {
+	return file_communication_v1_communication_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SendBatchResponse) GetResults() []*SendBatchResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type GetStatusRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	CommId string                 `protobuf:"bytes,1,opt,name=comm_id,json=commId,proto3" json:"comm_id,omitempty"`
+	// Channel the message was sent on; every channel is searched when empty.
+	Channel       string `protobuf:"bytes,2,opt,name=channel,proto3" json:"channel,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatusRequest) Reset() {
+	*x = GetStatusRequest{}
+	mi := &file_communication_v1_communication_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatusRequest) ProtoMessage() {}
+
+func (x *GetStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_communication_v1_communication_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetStatusRequest) Descriptor() ([]byte, []int) {
+	return file_communication_v1_communication_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetStatusRequest) GetCommId() string {
+	if x != nil {
+		return x.CommId
+	}
+	return ""
+}
+
+func (x *GetStatusRequest) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+type GetStatusResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	CommId  string                 `protobuf:"bytes,1,opt,name=comm_id,json=commId,proto3" json:"comm_id,omitempty"`
+	Channel string                 `protobuf:"bytes,2,opt,name=channel,proto3" json:"channel,omitempty"`
+	// QUEUED until the message is processed, then SENT or FAILED.
+	Status          string `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Vendor          string `protobuf:"bytes,4,opt,name=vendor,proto3" json:"vendor,omitempty"`
+	TransactionId   string `protobuf:"bytes,5,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	ResponseMessage string `protobuf:"bytes,6,opt,name=response_message,json=responseMessage,proto3" json:"response_message,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GetStatusResponse) Reset() {
+	*x = GetStatusResponse{}
+	mi := &file_communication_v1_communication_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatusResponse) ProtoMessage() {}
+
+func (x *GetStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_communication_v1_communication_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatusResponse.ProtoReflect.Descriptor instead.
+func (*GetStatusResponse) Descriptor() ([]byte, []int) {
+	return file_communication_v1_communication_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetStatusResponse) GetCommId() string {
+	if x != nil {
+		return x.CommId
+	}
+	return ""
+}
+
+func (x *GetStatusResponse) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+func (x *GetStatusResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *GetStatusResponse) GetVendor() string {
+	if x != nil {
+		return x.Vendor
+	}
+	return ""
+}
+
+func (x *GetStatusResponse) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *GetStatusResponse) GetResponseMessage() string {
+	if x != nil {
+		return x.ResponseMessage
+	}
+	return ""
+}
+
+type SubscribeStatusRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only events of this channel are streamed when set.
+	Channel       string `protobuf:"bytes,1,opt,name=channel,proto3" json:"channel,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeStatusRequest) Reset() {
+	*x = SubscribeStatusRequest{}
+	mi := &file_communication_v1_communication_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeStatusRequest) ProtoMessage() {}
+
+func (x *SubscribeStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_communication_v1_communication_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeStatusRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeStatusRequest) Descriptor() ([]byte, []int) {
+	return file_communication_v1_communication_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SubscribeStatusRequest) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+type StatusEvent struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Event           string                 `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	CommId          string                 `protobuf:"bytes,2,opt,name=comm_id,json=commId,proto3" json:"comm_id,omitempty"`
+	Client          string                 `protobuf:"bytes,3,opt,name=client,proto3" json:"client,omitempty"`
+	Channel         string                 `protobuf:"bytes,4,opt,name=channel,proto3" json:"channel,omitempty"`
+	ProcessName     string                 `protobuf:"bytes,5,opt,name=process_name,json=processName,proto3" json:"process_name,omitempty"`
+	Stage           float64                `protobuf:"fixed64,6,opt,name=stage,proto3" json:"stage,omitempty"`
+	Vendor          string                 `protobuf:"bytes,7,opt,name=vendor,proto3" json:"vendor,omitempty"`
+	TransactionId   string                 `protobuf:"bytes,8,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	ResponseMessage string                 `protobuf:"bytes,9,opt,name=response_message,json=responseMessage,proto3" json:"response_message,omitempty"`
+	Timestamp       string                 `protobuf:"bytes,10,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *StatusEvent) Reset() {
+	*x = StatusEvent{}
+	mi := &file_communication_v1_communication_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatusEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusEvent) ProtoMessage() {}
+
+func (x *StatusEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_communication_v1_communication_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusEvent.ProtoReflect.Descriptor instead.
+func (*StatusEvent) Descriptor() ([]byte, []int) {
+	return file_communication_v1_communication_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *StatusEvent) GetEvent() string {
+	if x != nil {
+		return x.Event
+	}
+	return ""
+}
+
+func (x *StatusEvent) GetCommId() string {
+	if x != nil {
+		return x.CommId
+	}
+	return ""
+}
+
+func (x *StatusEvent) GetClient() string {
+	if x != nil {
+		return x.Client
+	}
+	return ""
+}
+
+func (x *StatusEvent) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+func (x *StatusEvent) GetProcessName() string {
+	if x != nil {
+		return x.ProcessName
+	}
+	return ""
+}
+
+func (x *StatusEvent) GetStage() float64 {
+	if x != nil {
+		return x.Stage
+	}
+	return 0
+}
+
+func (x *StatusEvent) GetVendor() string {
+	if x != nil {
+		return x.Vendor
+	}
+	return ""
+}
+
+func (x *StatusEvent) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *StatusEvent) GetResponseMessage() string {
+	if x != nil {
+		return x.ResponseMessage
+	}
+	return ""
+}
+
+func (x *StatusEvent) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+var File_communication_v1_communication_proto protoreflect.FileDescriptor
+
+const file_communication_v1_communication_proto_rawDesc = "" +
+	"\n" +
+	"$communication/v1/communication.proto\x12\x10communication.v1\"\xf6\x02\n" +
+	"\aMessage\x12\x16\n" +
+	"\x06mobile\x18\x01 \x01(\tR\x06mobile\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x18\n" +
+	"\achannel\x18\x03 \x01(\tR\achannel\x12!\n" +
+	"\fprocess_name\x18\x04 \x01(\tR\vprocessName\x12\x14\n" +
+	"\x05stage\x18\x05 \x01(\x01R\x05stage\x12\x1f\n" +
+	"\vis_priority\x18\x06 \x01(\bR\n" +
+	"isPriority\x12F\n" +
+	"\tvariables\x18\a \x03(\v2(.communication.v1.Message.VariablesEntryR\tvariables\x12 \n" +
+	"\vdescription\x18\b \x01(\tR\vdescription\x12!\n" +
+	"\fpayment_link\x18\t \x01(\tR\vpaymentLink\x1a<\n" +
+	"\x0eVariablesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"B\n" +
+	"\vSendRequest\x123\n" +
+	"\amessage\x18\x01 \x01(\v2\x19.communication.v1.MessageR\amessage\"'\n" +
+	"\fSendResponse\x12\x17\n" +
+	"\acomm_id\x18\x01 \x01(\tR\x06commId\"I\n" +
+	"\x10SendBatchRequest\x125\n" +
+	"\bmessages\x18\x01 \x03(\v2\x19.communication.v1.MessageR\bmessages\"\x84\x01\n" +
+	"\x0fSendBatchResult\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12\x17\n" +
+	"\acomm_id\x18\x02 \x01(\tR\x06commId\x12\x18\n" +
+	"\asuccess\x18\x03 \x01(\bR\asuccess\x12\x12\n" +
+	"\x04code\x18\x04 \x01(\x05R\x04code\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\"P\n" +
+	"\x11SendBatchResponse\x12;\n" +
+	"\aresults\x18\x01 \x03(\v2!.communication.v1.SendBatchResultR\aresults\"E\n" +
+	"\x10GetStatusRequest\x12\x17\n" +
+	"\acomm_id\x18\x01 \x01(\tR\x06commId\x12\x18\n" +
+	"\achannel\x18\x02 \x01(\tR\achannel\"\xc8\x01\n" +
+	"\x11GetStatusResponse\x12\x17\n" +
+	"\acomm_id\x18\x01 \x01(\tR\x06commId\x12\x18\n" +
+	"\achannel\x18\x02 \x01(\tR\achannel\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x16\n" +
+	"\x06vendor\x18\x04 \x01(\tR\x06vendor\x12%\n" +
+	"\x0etransaction_id\x18\x05 \x01(\tR\rtransactionId\x12)\n" +
+	"\x10response_message\x18\x06 \x01(\tR\x0fresponseMessage\"2\n" +
+	"\x16SubscribeStatusRequest\x12\x18\n" +
+	"\achannel\x18\x01 \x01(\tR\achannel\"\xaf\x02\n" +
+	"\vStatusEvent\x12\x14\n" +
+	"\x05event\x18\x01 \x01(\tR\x05event\x12\x17\n" +
+	"\acomm_id\x18\x02 \x01(\tR\x06commId\x12\x16\n" +
+	"\x06client\x18\x03 \x01(\tR\x06client\x12\x18\n" +
+	"\achannel\x18\x04 \x01(\tR\achannel\x12!\n" +
+	"\fprocess_name\x18\x05 \x01(\tR\vprocessName\x12\x14\n" +
+	"\x05stage\x18\x06 \x01(\x01R\x05stage\x12\x16\n" +
+	"\x06vendor\x18\a \x01(\tR\x06vendor\x12%\n" +
+	"\x0etransaction_id\x18\b \x01(\tR\rtransactionId\x12)\n" +
+	"\x10response_message\x18\t \x01(\tR\x0fresponseMessage\x12\x1c\n" +
+	"\ttimestamp\x18\n" +
+	" \x01(\tR\ttimestamp2\xe7\x02\n" +
+	"\x14CommunicationService\x12E\n" +
+	"\x04Send\x12\x1d.communication.v1.SendRequest\x1a\x1e.communication.v1.SendResponse\x12T\n" +
+	"\tSendBatch\x12\".communication.v1.SendBatchRequest\x1a#.communication.v1.SendBatchResponse\x12T\n" +
+	"\tGetStatus\x12\".communication.v1.GetStatusRequest\x1a#.communication.v1.GetStatusResponse\x12\\\n" +
+	"\x0fSubscribeStatus\x12(.communication.v1.SubscribeStatusRequest\x1a\x1d.communication.v1.StatusEvent0\x01B9Z7github.com/wecredit/communication-sdk/pkg/commpb;commpbb\x06proto3"
+
+var (
+	file_communication_v1_communication_proto_rawDescOnce sync.Once
+	file_communication_v1_communication_proto_rawDescData []byte
+)
+
+func file_communication_v1_communication_proto_rawDescGZIP() []byte {
+	file_communication_v1_communication_proto_rawDescOnce.Do(func() {
+		file_communication_v1_communication_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_communication_v1_communication_proto_rawDesc), len(file_communication_v1_communication_proto_rawDesc)))
+	})
+	return file_communication_v1_communication_proto_rawDescData
+}
+
+var file_communication_v1_communication_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_communication_v1_communication_proto_goTypes = []any{
+	(*Message)(nil),                // 0: communication.v1.Message
+	(*SendRequest)(nil),            // 1: communication.v1.SendRequest
+	(*SendResponse)(nil),           // 2: communication.v1.SendResponse
+	(*SendBatchRequest)(nil),       // 3: communication.v1.SendBatchRequest
+	(*SendBatchResult)(nil),        // 4: communication.v1.SendBatchResult
+	(*SendBatchResponse)(nil),      // 5: communication.v1.SendBatchResponse
+	(*GetStatusRequest)(nil),       // 6: communication.v1.GetStatusRequest
+	(*GetStatusResponse)(nil),      // 7: communication.v1.GetStatusResponse
+	(*SubscribeStatusRequest)(nil), // 8: communication.v1.SubscribeStatusRequest
+	(*StatusEvent)(nil),            // 9: communication.v1.StatusEvent
+	nil,                            // 10: communication.v1.Message.VariablesEntry
+}
+var file_communication_v1_communication_proto_depIdxs = []int32{
+	10, // 0: communication.v1.Message.variables:type_name -> communication.v1.Message.VariablesEntry
+	0,  // 1: communication.v1.SendRequest.message:type_name -> communication.v1.Message
+	0,  // 2: communication.v1.SendBatchRequest.messages:type_name -> communication.v1.Message
+	4,  // 3: communication.v1.SendBatchResponse.results:type_name -> communication.v1.SendBatchResult
+	1,  // 4: communication.v1.CommunicationService.Send:input_type -> communication.v1.SendRequest
+	3,  // 5: communication.v1.CommunicationService.SendBatch:input_type -> communication.v1.SendBatchRequest
+	6,  // 6: communication.v1.CommunicationService.GetStatus:input_type -> communication.v1.GetStatusRequest
+	8,  // 7: communication.v1.CommunicationService.SubscribeStatus:input_type -> communication.v1.SubscribeStatusRequest
+	2,  // 8: communication.v1.CommunicationService.Send:output_type -> communication.v1.SendResponse
+	5,  // 9: communication.v1.CommunicationService.SendBatch:output_type -> communication.v1.SendBatchResponse
+	7,  // 10: communication.v1.CommunicationService.GetStatus:output_type -> communication.v1.GetStatusResponse
+	9,  // 11: communication.v1.CommunicationService.SubscribeStatus:output_type -> communication.v1.StatusEvent
+	8,  // [8:12] is the sub-list for method output_type
+	4,  // [4:8] is the sub-list for method input_type
+	4,  // [4:4] is the sub-list for extension type_name
+	4,  // [4:4] is the sub-list for extension extendee
+	0,  // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_communication_v1_communication_proto_init() }
+func file_communication_v1_communication_proto_init() {
+	if File_communication_v1_communication_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_communication_v1_communication_proto_rawDesc), len(file_communication_v1_communication_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_communication_v1_communication_proto_goTypes,
+		DependencyIndexes: file_communication_v1_communication_proto_depIdxs,
+		MessageInfos:      file_communication_v1_communication_proto_msgTypes,
+	}.Build()
+	File_communication_v1_communication_proto = out.File
+	file_communication_v1_communication_proto_goTypes = nil
+	file_communication_v1_communication_proto_depIdxs = nil
+}
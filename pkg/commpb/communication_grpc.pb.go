@@ -0,0 +1,253 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: communication/v1/communication.proto
+
+package commpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	CommunicationService_Send_FullMethodName            = "/communication.v1.CommunicationService/Send"
+	CommunicationService_SendBatch_FullMethodName       = "/communication.v1.CommunicationService/SendBatch"
+	CommunicationService_GetStatus_FullMethodName       = "/communication.v1.CommunicationService/GetStatus"
+	CommunicationService_SubscribeStatus_FullMethodName = "/communication.v1.CommunicationService/SubscribeStatus"
+)
+
+// CommunicationServiceClient is the client API for CommunicationService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// CommunicationService accepts messages with the same validation and idempotency as the Go SDK and
+// POST /v1/communications. Every call is authenticated with basic auth in the "authorization" metadata.
+type CommunicationServiceClient interface {
+	// Send accepts one message and returns its CommId.
+	Send(ctx context.Context, in *SendRequest, opts ...grpc.CallOption) (*SendResponse, error)
+	// SendBatch accepts each message independently; results are in request order.
+	SendBatch(ctx context.Context, in *SendBatchRequest, opts ...grpc.CallOption) (*SendBatchResponse, error)
+	// GetStatus returns the delivery status of a message sent by the authenticated client.
+	GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*GetStatusResponse, error)
+	// SubscribeStatus streams the final status of the authenticated client's messages as they are processed.
+	SubscribeStatus(ctx context.Context, in *SubscribeStatusRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StatusEvent], error)
+}
+
+type communicationServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCommunicationServiceClient(cc grpc.ClientConnInterface) CommunicationServiceClient {
+	return &communicationServiceClient{cc}
+}
+
+func (c *communicationServiceClient) Send(ctx context.Context, in *SendRequest, opts ...grpc.CallOption) (*SendResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SendResponse)
+	err := c.cc.Invoke(ctx, CommunicationService_Send_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *communicationServiceClient) SendBatch(ctx context.Context, in *SendBatchRequest, opts ...grpc.CallOption) (*SendBatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SendBatchResponse)
+	err := c.cc.Invoke(ctx, CommunicationService_SendBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *communicationServiceClient) GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*GetStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetStatusResponse)
+	err := c.cc.Invoke(ctx, CommunicationService_GetStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *communicationServiceClient) SubscribeStatus(ctx context.Context, in *SubscribeStatusRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StatusEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CommunicationService_ServiceDesc.Streams[0], CommunicationService_SubscribeStatus_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeStatusRequest, StatusEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CommunicationService_SubscribeStatusClient = grpc.ServerStreamingClient[StatusEvent]
+
+// CommunicationServiceServer is the server API for CommunicationService service.
+// All implementations must embed UnimplementedCommunicationServiceServer
+// for forward compatibility.
+//
+// CommunicationService accepts messages with the same validation and idempotency as the Go SDK and
+// POST /v1/communications. Every call is authenticated with basic auth in the "authorization" metadata.
+type CommunicationServiceServer interface {
+	// Send accepts one message and returns its CommId.
+	Send(context.Context, *SendRequest) (*SendResponse, error)
+	// SendBatch accepts each message independently; results are in request order.
+	SendBatch(context.Context, *SendBatchRequest) (*SendBatchResponse, error)
+	// GetStatus returns the delivery status of a message sent by the authenticated client.
+	GetStatus(context.Context, *GetStatusRequest) (*GetStatusResponse, error)
+	// SubscribeStatus streams the final status of the authenticated client's messages as they are processed.
+	SubscribeStatus(*SubscribeStatusRequest, grpc.ServerStreamingServer[StatusEvent]) error
+	mustEmbedUnimplementedCommunicationServiceServer()
+}
+
+// UnimplementedCommunicationServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCommunicationServiceServer struct{}
+
+func (UnimplementedCommunicationServiceServer) Send(context.Context, *SendRequest) (*SendResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Send not implemented")
+}
+func (UnimplementedCommunicationServiceServer) SendBatch(context.Context, *SendBatchRequest) (*SendBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SendBatch not implemented")
+}
+func (UnimplementedCommunicationServiceServer) GetStatus(context.Context, *GetStatusRequest) (*GetStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatus not implemented")
+}
+func (UnimplementedCommunicationServiceServer) SubscribeStatus(*SubscribeStatusRequest, grpc.ServerStreamingServer[StatusEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeStatus not implemented")
+}
+func (UnimplementedCommunicationServiceServer) mustEmbedUnimplementedCommunicationServiceServer() {}
+func (UnimplementedCommunicationServiceServer) testEmbeddedByValue()                              {}
+
+// UnsafeCommunicationServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CommunicationServiceServer will
+// result in compilation errors.
+type UnsafeCommunicationServiceServer interface {
+	mustEmbedUnimplementedCommunicationServiceServer()
+}
+
+func RegisterCommunicationServiceServer(s grpc.ServiceRegistrar, srv CommunicationServiceServer) {
+	// If the following call pancis, it indicates UnimplementedCommunicationServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&CommunicationService_ServiceDesc, srv)
+}
+
+func _CommunicationService_Send_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommunicationServiceServer).Send(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CommunicationService_Send_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommunicationServiceServer).Send(ctx, req.(*SendRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommunicationService_SendBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommunicationServiceServer).SendBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CommunicationService_SendBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommunicationServiceServer).SendBatch(ctx, req.(*SendBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommunicationService_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CommunicationServiceServer).GetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CommunicationService_GetStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CommunicationServiceServer).GetStatus(ctx, req.(*GetStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CommunicationService_SubscribeStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeStatusRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CommunicationServiceServer).SubscribeStatus(m, &grpc.GenericServerStream[SubscribeStatusRequest, StatusEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CommunicationService_SubscribeStatusServer = grpc.ServerStreamingServer[StatusEvent]
+
+// CommunicationService_ServiceDesc is the grpc.ServiceDesc for CommunicationService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CommunicationService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "communication.v1.CommunicationService",
+	HandlerType: (*CommunicationServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Send",
+			Handler:    _CommunicationService_Send_Handler,
+		},
+		{
+			MethodName: "SendBatch",
+			Handler:    _CommunicationService_SendBatch_Handler,
+		},
+		{
+			MethodName: "GetStatus",
+			Handler:    _CommunicationService_GetStatus_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeStatus",
+			Handler:       _CommunicationService_SubscribeStatus_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "communication/v1/communication.proto",
+}
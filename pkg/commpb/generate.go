@@ -0,0 +1,4 @@
+// Package commpb holds the generated messages and gRPC stubs of proto/communication/v1/communication.proto.
+package commpb
+
+//go:generate protoc -I ../../proto --go_out=../.. --go_opt=module=github.com/wecredit/communication-sdk --go-grpc_out=../.. --go-grpc_opt=module=github.com/wecredit/communication-sdk communication/v1/communication.proto
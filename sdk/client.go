@@ -1,12 +1,18 @@
 package sdk
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/service/sns"
 	"github.com/redis/go-redis/v9"
 	redisHelper "github.com/wecredit/communication-sdk/internal/redis"
 	sdkConfig "github.com/wecredit/communication-sdk/sdk/config"
+	"github.com/wecredit/communication-sdk/sdk/models/sdkModels"
+	"github.com/wecredit/communication-sdk/sdk/queue"
+	sdkServices "github.com/wecredit/communication-sdk/sdk/services"
 	"github.com/wecredit/communication-sdk/sdk/utils"
 	"github.com/wecredit/communication-sdk/sdk/variables"
 )
@@ -18,14 +24,18 @@ type CommSdkClient struct {
 	TopicArn     string
 	AwsSnsClient *sns.SNS
 	RedisClient  *redis.Client
-}
 
-func NewSdkClient(username, password, channel, baseUrl string) (*CommSdkClient, error) {
-	snsClient, err := sdkConfig.LoadSDKConfigs()
-	if err != nil {
-		return nil, fmt.Errorf("failed to initialize SDK Client: failed to load configs: %v", err)
-	}
+	baseUrl  string
+	username string
+	password string
+	directDB bool
+	outbox   *sdkServices.Outbox
+	metrics  *sendMetrics
 
+	signingKeys string
+}
+
+func NewSdkClient(username, password, channel, baseUrl string, opts ...Option) (*CommSdkClient, error) {
 	if username == "" || password == "" || channel == "" || baseUrl == "" {
 		return nil, fmt.Errorf("username, password, channel, and baseUrl are required")
 	}
@@ -36,21 +46,50 @@ func NewSdkClient(username, password, channel, baseUrl string) (*CommSdkClient,
 		return nil, fmt.Errorf("client is not authenticated with us for this channel. Wrong Username or password")
 	}
 
-	// fmt.Println("TopicArn: ", topicArn)
+	client := &CommSdkClient{
+		ClientName: userName,
+		isAuthed:   ok,
+		Channel:    channel,
+		baseUrl:    strings.TrimRight(baseUrl, "/"),
+		username:   username,
+		password:   password,
+	}
+	for _, opt := range opts {
+		opt(client)
+	}
+	if client.outbox != nil && (client.outbox.DB == nil || client.outbox.Table == "") {
+		return nil, fmt.Errorf("outbox requires a database and a table name")
+	}
+
+	// Messages go through the ingestion endpoint unless the client writes the input rows itself
+	if !client.directDB {
+		if client.signingKeys != "" {
+			return nil, fmt.Errorf("message signing keys are only used by clients created WithDirectDB or WithOutbox")
+		}
+		return client, nil
+	}
+
+	if client.signingKeys != "" {
+		if err := queue.SetSigningKeys(client.signingKeys); err != nil {
+			return nil, fmt.Errorf("invalid message signing keys: %v", err)
+		}
+	}
+
+	snsClient, err := sdkConfig.LoadSDKConfigs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize SDK Client: failed to load configs: %v", err)
+	}
+
 	// Create redis client from the address
 	redisClient, err := redisHelper.GetSdkRedisClient(redisAddress)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize redis client: %v", err)
 	}
 
-	return &CommSdkClient{
-		ClientName:   userName,
-		isAuthed:     ok,
-		Channel:      channel,
-		TopicArn:     topicArn,
-		AwsSnsClient: snsClient,
-		RedisClient:  redisClient,
-	}, nil
+	client.TopicArn = topicArn
+	client.AwsSnsClient = snsClient
+	client.RedisClient = redisClient
+	return client, nil
 }
 
 func ValidateClient(username, password, channel, baseUrl string) (bool, string, string, string, string) {
@@ -67,13 +106,15 @@ func ValidateClient(username, password, channel, baseUrl string) (bool, string,
 		"password": password,
 	}
 
-	apiResponse, err := utils.ApiHit(variables.PostMethod, apiUrl, apiHeaders, "", "", requestBody, variables.ContentTypeJSON)
-	if err != nil {
+	apiResponse, err := utils.ApiCall(context.Background(), variables.PostMethod, apiUrl, apiHeaders, "", "", requestBody, variables.ContentTypeJSON)
+	if err != nil || apiResponse.StatusCode != http.StatusOK {
 		return false, "", "", "", ""
 	}
 
-	if apiResponse["ApistatusCode"].(int) == 200 {
-		return true, apiResponse["user"].(string), apiResponse["channel"].(string), apiResponse["topicArn"].(string), apiResponse["redisAddress"].(string)
+	var response sdkModels.ValidateClientResponseBody
+	if err := apiResponse.Decode(&response); err != nil {
+		utils.Error(fmt.Errorf("error validating client %s: %v", username, err))
+		return false, "", "", "", ""
 	}
-	return false, "", "", "", ""
+	return true, response.User, response.Channel, response.TopicArn, response.RedisAddress
 }
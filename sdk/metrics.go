@@ -0,0 +1,86 @@
+package sdk
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	sdkServices "github.com/wecredit/communication-sdk/sdk/services"
+	"github.com/wecredit/communication-sdk/sdk/utils"
+)
+
+// Outcomes of Send reported by the comm_sdk_sends_total counter
+const (
+	OutcomeSuccess   = "success"
+	OutcomeInvalid   = "invalid"
+	OutcomeDuplicate = "duplicate"
+	OutcomeError     = "error"
+)
+
+// sendMetrics are the collectors a client created WithMetrics records every Send in.
+type sendMetrics struct {
+	duration *prometheus.HistogramVec
+	sends    *prometheus.CounterVec
+}
+
+// WithMetrics records the latency and outcome of every Send in collectors registered with reg,
+// or with the default Prometheus registry when reg is nil. Clients registered with the same registry share them.
+func WithMetrics(reg prometheus.Registerer) Option {
+	return func(c *CommSdkClient) {
+		if reg == nil {
+			reg = prometheus.DefaultRegisterer
+		}
+		c.metrics = newSendMetrics(reg)
+	}
+}
+
+func newSendMetrics(reg prometheus.Registerer) *sendMetrics {
+	m := &sendMetrics{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "comm_sdk",
+			Name:      "send_duration_seconds",
+			Help:      "Latency of Send, until the message was accepted or rejected.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"channel"}),
+		sends: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "comm_sdk",
+			Name:      "sends_total",
+			Help:      "Messages passed to Send by outcome: success, invalid, duplicate or error.",
+		}, []string{"channel", "outcome"}),
+	}
+	m.duration = register(reg, m.duration)
+	m.sends = register(reg, m.sends)
+	return m
+}
+
+// register returns the collector already registered under the same name, if any, so several clients can share it.
+func register[C prometheus.Collector](reg prometheus.Registerer, collector C) C {
+	if err := reg.Register(collector); err != nil {
+		var registered prometheus.AlreadyRegisteredError
+		if errors.As(err, &registered) {
+			if existing, ok := registered.ExistingCollector.(C); ok {
+				return existing
+			}
+		}
+		utils.Error(fmt.Errorf("failed to register SDK metrics: %v", err))
+	}
+	return collector
+}
+
+func (m *sendMetrics) observe(channel string, start time.Time, err error) {
+	if m == nil {
+		return
+	}
+	outcome := OutcomeSuccess
+	switch {
+	case errors.Is(err, sdkServices.ErrInvalidRequest):
+		outcome = OutcomeInvalid
+	case errors.Is(err, sdkServices.ErrDuplicateMessage):
+		outcome = OutcomeDuplicate
+	case err != nil:
+		outcome = OutcomeError
+	}
+	m.duration.WithLabelValues(channel).Observe(time.Since(start).Seconds())
+	m.sends.WithLabelValues(channel, outcome).Inc()
+}
@@ -3,6 +3,10 @@ package models
 type Config struct {
 	Port         string `envconfig:"API_SERVER_PORT"`
 	ConsumerPort string `envconfig:"CONSUMER_SERVER_PORT"`
+	GrpcPort     string `envconfig:"GRPC_SERVER_PORT" default:"9090"`
+	GrpcTlsCert  string `envconfig:"GRPC_TLS_CERT_FILE"` // the gRPC server only starts with a certificate and key, as clients send basic auth
+	GrpcTlsKey   string `envconfig:"GRPC_TLS_KEY_FILE"`
+	MetricsPort  string `envconfig:"METRICS_SERVER_PORT" default:"9091"` // internal only: not to be exposed outside the cluster
 
 	// Analytical DB variables
 	DbServerAnalytical   string `envconfig:"DB_SERVER_ANALYTICS"`
@@ -37,16 +41,41 @@ type Config struct {
 	AwsErrorQueueUrl string `envconfig:"AWS_COMM_ERROR_QUEUE_URL"`
 
 	// Redis Credentials
-	RedisAddress      string `envconfig:"REDIS_ADDRESS"`
-	RedisPassword     string `envconfig:"REDIS_PASSWORD"`
-	RedisMapKey       string `envconfig:"REDIS_MAP_KEY"`
-	CommIdempotentKey string `envconfig:"COMM_IDEMPOTENT_KEY"`
-
-	CreditSeaWhatsappCurrentCount string `envconfig:"CREDITSEA_WHATSAPP_CURRENT_COUNT"`
-	CreditSeaWhatsappMaxCount     string `envconfig:"CREDITSEA_WHATSAPP_MAX_COUNT"`
+	RedisAddress                 string `envconfig:"REDIS_ADDRESS"`
+	RedisPassword                string `envconfig:"REDIS_PASSWORD"`
+	RedisMapKey                  string `envconfig:"REDIS_MAP_KEY"`
+	CacheInvalidationChannel     string `envconfig:"CACHE_INVALIDATION_CHANNEL" default:"communication-sdk:cache-invalidation"`
+	StatusEventsChannel          string `envconfig:"STATUS_EVENTS_CHANNEL" default:"communication-sdk:status-events"`
+	LogLevelChannel              string `envconfig:"LOG_LEVEL_CHANNEL" default:"communication-sdk:log-level"`
+	CacheRefreshSeconds          string `envconfig:"CACHE_REFRESH_SECONDS" default:"300"` // default for the per dataset intervals below
+	AuthRefreshSeconds           string `envconfig:"AUTH_REFRESH_SECONDS"`
+	AdminKeysRefreshSeconds      string `envconfig:"ADMIN_KEYS_REFRESH_SECONDS"`
+	VendorsRefreshSeconds        string `envconfig:"VENDORS_REFRESH_SECONDS"`
+	ClientsRefreshSeconds        string `envconfig:"CLIENTS_REFRESH_SECONDS"`
+	TemplatesRefreshSeconds      string `envconfig:"TEMPLATES_REFRESH_SECONDS"`
+	VendorAccountsRefreshSeconds string `envconfig:"VENDOR_ACCOUNTS_REFRESH_SECONDS"`
+	CacheRefreshFailureThreshold string `envconfig:"CACHE_REFRESH_FAILURE_THRESHOLD" default:"3"` // consecutive failed refreshes before a dataset is stale
+	CommIdempotentKey            string `envconfig:"COMM_IDEMPOTENT_KEY"`
+	CommClaimLeaseSeconds        string `envconfig:"COMM_CLAIM_LEASE_SECONDS" default:"300"`  // a send not finished by then may be retried
+	CommClaimRetentionHours      string `envconfig:"COMM_CLAIM_RETENTION_HOURS" default:"96"` // how long a CommId is remembered after its send
 
 	// Auth Table Variables
 	BasicAuthTableName string `envconfig:"BASIC_AUTH_TABLE"`
+	AdminApiKeyTable   string `envconfig:"ADMIN_API_KEY_TABLE" default:"AdminApiKeys"`
+	// ADMIN_BOOTSTRAP_API_KEY, e.g. from openssl rand -hex 32, is stored as an ops-admin key while no active one exists
+	AdminBootstrapApiKey string `envconfig:"ADMIN_BOOTSTRAP_API_KEY"`
+
+	// Credential Variables. A username is locked out from a client IP after AUTH_MAX_FAILURES failed checks from it,
+	// for AUTH_LOCKOUT_SECONDS after the first one; the other IPs of the client are not affected.
+	AuthMaxFailures                string `envconfig:"AUTH_MAX_FAILURES" default:"5"`
+	AuthLockoutSeconds             string `envconfig:"AUTH_LOCKOUT_SECONDS" default:"900"`
+	CredentialRotationGraceSeconds string `envconfig:"CREDENTIAL_ROTATION_GRACE_SECONDS" default:"86400"`
+	// Legacy plaintext passwords are hashed on start but kept for pods that still compare them; set once every pod
+	// checks the hashes, the plaintext of hashed rows is cleared.
+	AuthClearPlaintextPasswords string `envconfig:"AUTH_CLEAR_PLAINTEXT_PASSWORDS" default:"false"`
+
+	// Communication API Variables
+	CommBatchMaxSize string `envconfig:"COMM_BATCH_MAX_SIZE" default:"500"`
 
 	// SDK Tables
 	SdkWhatsappInputTable string `envconfig:"SDK_WHATSAPP_INPUT_TABLE"`
@@ -64,26 +93,86 @@ type Config struct {
 	VendorTable          string `envconfig:"VENDORS_TABLE"`
 	ClientsTable         string `envconfig:"CLIENTS_TABLE"`
 	TemplateDetailsTable string `envconfig:"TEMPLATE_TABLE"`
+	TemplateVersionTable string `envconfig:"TEMPLATE_VERSION_TABLE" default:"TemplateVersions"`
+	VendorAccountTable   string `envconfig:"VENDOR_ACCOUNT_TABLE" default:"VendorAccounts"`
+
+	// Envelope encryption master keys as comma separated id:base64 pairs of 32 byte keys, e.g. "v2:...,v1:...".
+	// The first key wraps new data keys; the others only unwrap existing ones until they are re-encrypted.
+	SecretsMasterKeys string `envconfig:"SECRETS_MASTER_KEYS"`
+	// Local file of id:base64 master keys, one per line, standing in for a KMS; its keys follow SECRETS_MASTER_KEYS.
+	SecretsMasterKeyFile string `envconfig:"SECRETS_MASTER_KEY_FILE"`
 
 	CommAuditTable string `envconfig:"COMM_AUDIT_TABLE"`
 
+	// Client Webhook Variables
+	WebhookDeliveryTable       string `envconfig:"WEBHOOK_DELIVERY_TABLE" default:"WebhookDeliveries"`
+	WebhookMaxAttempts         string `envconfig:"WEBHOOK_MAX_ATTEMPTS" default:"8"`
+	WebhookPollIntervalSeconds string `envconfig:"WEBHOOK_POLL_INTERVAL_SECONDS" default:"5"`
+	WebhookTimeoutSeconds      string `envconfig:"WEBHOOK_TIMEOUT_SECONDS" default:"10"`
+	// Webhook secrets are sealed on start but kept in plaintext for pods that still read it; set once every pod
+	// opens the sealed secrets, the plaintext is cleared.
+	WebhookClearPlaintextSecrets string `envconfig:"WEBHOOK_CLEAR_PLAINTEXT_SECRETS" default:"false"`
+
+	// Vendor call audit log; rows older than the retention are purged nightly
+	VendorAuditTable         string `envconfig:"VENDOR_AUDIT_TABLE" default:"VendorCallAudits"`
+	VendorAuditRetentionDays string `envconfig:"VENDOR_AUDIT_RETENTION_DAYS" default:"90"`
+
+	// OpenTelemetry tracing: none, otlp or stdout. The otlp exporter is pointed at the collector with
+	// OTEL_EXPORTER_OTLP_ENDPOINT and the other standard OTEL_EXPORTER_OTLP_ variables.
+	TracingExporter    string `envconfig:"TRACING_EXPORTER" default:"none"`
+	TracingServiceName string `envconfig:"TRACING_SERVICE_NAME" default:"communication-consumer"`
+	TracingSampleRatio string `envconfig:"TRACING_SAMPLE_RATIO" default:"1"` // of the traces started by the consumer
+
+	// Authentication of consumed messages. SNS signatures are checked with certificates from SNS_SIGNING_CERT_HOSTS,
+	// by default the SNS endpoint of AWS_REGION. COMM_MESSAGE_SIGNING_KEYS are comma separated id:base64 HMAC keys
+	// of at least 32 bytes; the first signs published messages and all of them verify. A signature covers the time
+	// the message was signed, and messages older than COMM_CLAIM_RETENTION_HOURS fail it, so they cannot be replayed.
+	// Until COMM_MESSAGE_SIGNATURE_REQUIRED is true, messages failing the check are only logged. Roll out in order:
+	// the keys on the consumer, then on every producer, then, once no warning is logged, the required flag.
+	// A new key is added last on the consumer, then first on the producers, before the old one is dropped.
+	SnsVerifySignatures          string `envconfig:"SNS_VERIFY_SIGNATURES" default:"true"`
+	SnsSigningCertHosts          string `envconfig:"SNS_SIGNING_CERT_HOSTS"`
+	CommMessageSigningKeys       string `envconfig:"COMM_MESSAGE_SIGNING_KEYS"`
+	CommMessageSignatureRequired string `envconfig:"COMM_MESSAGE_SIGNATURE_REQUIRED" default:"false"`
+
+	// Protection of personal data. With PII_ENCRYPTION_ENABLED, producers encrypt the mobile number, email address,
+	// names and loan identifiers of a message with the master keys before publishing it, and the input and output
+	// rows keep them masked, with the envelope in the <column>Encrypted column and the keyed hash in <column>Hash.
+	// PII_HASH_KEY is a base64 key of at least 32 bytes; once set, Redis idempotency keys hash the mobile number.
+	// Producers and the consumer must share both keys; changing the hash key starts new idempotency keys.
+	PiiEncryptionEnabled string `envconfig:"PII_ENCRYPTION_ENABLED" default:"false"`
+	PiiHashKey           string `envconfig:"PII_HASH_KEY"`
+
 	// RCS Tables
 	RcsTemplateAppIdTable string `envconfig:"RCS_TEMPLATE_APP_ID_TABLE"`
 
 	// Sinch API Variables
 	SinchWhatsappTokenApiUrl   string `envconfig:"SINCH_GENERATE_TOKEN_API_URL"`
 	SinchWhatsappMessageApiUrl string `envconfig:"SINCH_SEND_WHATSAPP_MESSAGE_API_URL"`
-	SinchWhatsappGrantType     string `envconfig:"SINCH_API_GRANT_TYPE"`
+	SinchWhatsappGrantType     string `envconfig:"SINCH_API_GRANT_TYPE" default:"password"`
 	SinchWhatsappClientId      string `envconfig:"SINCH_API_CLIENT_ID"`
 	SinchWhatsappUserName      string `envconfig:"SINCH_API_USERNAME"`
 	SinchWhatsappPassword      string `envconfig:"SINCH_API_PASSWORD"`
+	SinchWhatsappAppId         string `envconfig:"SINCH_WP_APP_ID" default:"wecreditpd"`
 	SinchWhatsappCallbackURL   string `envconfig:"SINCH_WP_CALLBACK_URL"`
 	SinchRcsApiUrl             string `envconfig:"SINCH_RCS_API_URL"`
 
-	// Sinch Whatsapp CreditSea  Variables
+	// Deprecated: CreditSea Sinch Whatsapp account, only read at start to seed its VendorAccounts row
 	CreditSeaSinchWhatsappUsername string `envconfig:"SINCH_CREDITSEA_API_USERNAME"`
 	CreditSeaSinchWhatsappPassword string `envconfig:"SINCH_CREDITSEA_API_PASSWORD"`
 
+	// Vendor HTTP policies; the SINCH_ and TIMES_ settings override the VENDOR_ defaults.
+	// Retries also repeat message submissions, so they are off unless a vendor deduplicates them.
+	VendorHttpTimeoutSeconds string `envconfig:"VENDOR_HTTP_TIMEOUT_SECONDS" default:"10"`
+	VendorHttpRetryMax       string `envconfig:"VENDOR_HTTP_RETRY_MAX" default:"0"`
+	SinchHttpTimeoutSeconds  string `envconfig:"SINCH_HTTP_TIMEOUT_SECONDS"`
+	SinchHttpRetryMax        string `envconfig:"SINCH_HTTP_RETRY_MAX"`
+	TimesHttpTimeoutSeconds  string `envconfig:"TIMES_HTTP_TIMEOUT_SECONDS"`
+	TimesHttpRetryMax        string `envconfig:"TIMES_HTTP_RETRY_MAX"`
+
+	// Sinch access tokens are refreshed this long before they expire
+	SinchTokenRefreshMarginSeconds string `envconfig:"SINCH_TOKEN_REFRESH_MARGIN_SECONDS" default:"60"`
+
 	// Times API Details
 	TimesWpApiUrl   string `envconfig:"TIMES_WP_API_URL"`
 	TimesWpAPIToken string `envconfig:"TIMES_WP_API_TOKEN"`
@@ -103,7 +192,7 @@ type Config struct {
 	SinchSmsDltContentId string `envconfig:"SINCH_SMS_API_DLTCONTENTID"`
 	SinchSmsApiUrl       string `envconfig:"SINCH_SMS_API_URL"`
 
-	// CreditSea Sinch SMS API Variables
+	// Deprecated: CreditSea Sinch SMS account, only read at start to seed its VendorAccounts row
 	CreditSeaSinchSmsApiAppID    string `envconfig:"CREDITSEA_SINCH_SMS_API_APP_ID"`
 	CreditSeaSinchSmsApiUserName string `envconfig:"CREDITSEA_SINCH_SMS_API_USERNAME"`
 	CreditSeaSinchSmsApiPassword string `envconfig:"CREDITSEA_SINCH_SMS_API_PASSWORD"`
@@ -3,25 +3,51 @@ package sdkModels
 import "gorm.io/gorm"
 
 type CommApiRequestBody struct {
-	DbClient            *gorm.DB `json:"-" gorm:-`
-	InputTableName      string   `json:"inputTableName" gorm:-`
-	CommId              string   `json:"commId" gorm:"CommId"`
-	Mobile              string   `json:"mobile" gorm:"Mobile"`
-	Email               string   `json:"email" gorm:-`
-	Channel             string   `json:"channel" gorm:-` // Channel used for sending message
-	ProcessName         string   `json:"processName" gorm:"ProcessName"`
-	Stage               float64  `json:"stage" gorm:"Stage"`
-	IsPriority          bool     `json:"isPriority" gorm:"IsPriority"`
-	Vendor              string   `json:"vendor" gorm:-`                      // vendor who we use to send the message through
-	Client              string   `json:"client" gorm:"Client"`               // User using this sdk
-	EmiAmount           string   `json:"emiAmount,omitempty" gorm:-`         // variables used in creditsea Template
-	CustomerName        string   `json:"customerName,omitempty" gorm:-`      // variables used in creditsea Template
-	LoanId              string   `json:"loanId,omitempty" gorm:-`            // variables used in creditsea Template
-	ApplicationNumber   string   `json:"applicationNumber,omitempty" gorm:-` // variables used in creditsea Template
-	DueDate             string   `json:"dueDate,omitempty" gorm:-`
-	AzureIdempotencyKey string   `json:"azureIdempotencyKey,omitempty" gorm:"AzureIdempotencyKey"`
-	Description         string   `json:"description,omitempty" gorm:-` // variables used in creditsea Template
-	PaymentLink         string   `json:"paymentLink,omitempty" gorm:-` // payment link for the message
+	DbClient            *gorm.DB          `json:"-" gorm:"-"`              // Deprecated: only used by clients created WithDirectDB or WithOutbox
+	InputTableName      string            `json:"inputTableName" gorm:"-"` // Deprecated: only used by clients created WithDirectDB or WithOutbox
+	CommId              string            `json:"commId" gorm:"CommId"`
+	Mobile              string            `json:"mobile" gorm:"Mobile"`
+	Email               string            `json:"email" gorm:"-"`
+	Channel             string            `json:"channel" gorm:"-"` // Channel used for sending message
+	ProcessName         string            `json:"processName" gorm:"ProcessName"`
+	Stage               float64           `json:"stage" gorm:"Stage"`
+	IsPriority          bool              `json:"isPriority" gorm:"IsPriority"`
+	Vendor              string            `json:"vendor" gorm:"-"`                      // vendor who we use to send the message through
+	Client              string            `json:"client" gorm:"Client"`                 // User using this sdk
+	Variables           map[string]string `json:"variables,omitempty" gorm:"-"`         // template variables, keyed by the names in the template's TemplateVariables
+	EmiAmount           string            `json:"emiAmount,omitempty" gorm:"-"`         // Deprecated: alias of Variables["EmiAmount"]
+	CustomerName        string            `json:"customerName,omitempty" gorm:"-"`      // Deprecated: alias of Variables["CustomerName"]
+	LoanId              string            `json:"loanId,omitempty" gorm:"-"`            // Deprecated: alias of Variables["LoanId"]
+	ApplicationNumber   string            `json:"applicationNumber,omitempty" gorm:"-"` // Deprecated: alias of Variables["ApplicationNumber"]
+	DueDate             string            `json:"dueDate,omitempty" gorm:"-"`           // Deprecated: alias of Variables["DueDate"]
+	AzureIdempotencyKey string            `json:"azureIdempotencyKey,omitempty" gorm:"AzureIdempotencyKey"`
+	Description         string            `json:"description,omitempty" gorm:"-"` // variables used in creditsea Template
+	PaymentLink         string            `json:"paymentLink,omitempty" gorm:"-"` // payment link for the message
+}
+
+// TemplateVariables merges the legacy CreditSea fields into Variables.
+// A value present in Variables always wins over the legacy field with the same name.
+func (r CommApiRequestBody) TemplateVariables() map[string]string {
+	variables := make(map[string]string, len(r.Variables)+6)
+
+	aliases := map[string]string{
+		"EmiAmount":         r.EmiAmount,
+		"CustomerName":      r.CustomerName,
+		"LoanId":            r.LoanId,
+		"ApplicationNumber": r.ApplicationNumber,
+		"DueDate":           r.DueDate,
+		"PaymentLink":       r.PaymentLink,
+	}
+	for key, value := range aliases {
+		if value != "" {
+			variables[key] = value
+		}
+	}
+
+	for key, value := range r.Variables {
+		variables[key] = value
+	}
+	return variables
 }
 
 type CommApiResponseBody struct {
@@ -30,6 +56,38 @@ type CommApiResponseBody struct {
 	// ReqTimeStamp  string `json:"reqTimeStamp,omitempty"` // After processing
 }
 
+// CommApiResultBody is the answer of POST /v1/communications, carrying the error when the message was not accepted.
+type CommApiResultBody struct {
+	CommId  string `json:"commId"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// ValidateClientResponseBody is the answer of POST /clients/validate-client.
+type ValidateClientResponseBody struct {
+	Message      string `json:"message"`
+	User         string `json:"user"`
+	Channel      string `json:"channel"`
+	TopicArn     string `json:"topicArn"`
+	RedisAddress string `json:"redisAddress"`
+	Error        string `json:"error,omitempty"`
+}
+
+// CommBatchRequestBody is the body of POST /v1/communications/batch.
+type CommBatchRequestBody struct {
+	Messages []CommApiRequestBody `json:"messages"`
+}
+
+// CommBatchResult is the outcome of one message of a batch, at the same index as in the request.
+type CommBatchResult struct {
+	Index      int    `json:"index"`
+	CommId     string `json:"commId,omitempty"`
+	Success    bool   `json:"success"`
+	StatusCode int    `json:"statusCode"`
+	Error      string `json:"error,omitempty"`
+	Err        error  `json:"-"`
+}
+
 type CommApiErrorResponseBody struct {
 	StatusCode    int    `json:"statusCode"`
 	StatusMessage string `json:"statusMessage,omitempty"`
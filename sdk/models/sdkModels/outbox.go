@@ -0,0 +1,22 @@
+package sdkModels
+
+import "time"
+
+// OutboxMessage is a message waiting in the SDK outbox to be published to SNS.
+// It is written in the same transaction as the input row, so a message is either both stored and queued or neither.
+type OutboxMessage struct {
+	Id            int        `gorm:"column:Id;primaryKey" json:"id"`
+	CommId        string     `gorm:"column:CommId" json:"commId"`
+	RedisKey      string     `gorm:"column:RedisKey" json:"redisKey"`
+	TopicArn      string     `gorm:"column:TopicArn" json:"topicArn"`
+	Subject       string     `gorm:"column:Subject" json:"subject"`
+	Payload       string     `gorm:"column:Payload" json:"payload"`
+	TraceContext  string     `gorm:"column:TraceContext" json:"traceContext,omitempty"` // W3C trace context of Send as JSON
+	Status        string     `gorm:"column:Status" json:"status"`                       // PENDING, IN_FLIGHT, SENT, FAILED
+	Attempts      int        `gorm:"column:Attempts" json:"attempts"`
+	LastError     string     `gorm:"column:LastError" json:"lastError,omitempty"`
+	NextAttemptOn time.Time  `gorm:"column:NextAttemptOn" json:"nextAttemptOn"`
+	SentOn        *time.Time `gorm:"column:SentOn" json:"sentOn,omitempty"`
+	CreatedOn     time.Time  `gorm:"column:CreatedOn" json:"createdOn"`
+	UpdatedOn     *time.Time `gorm:"column:UpdatedOn" json:"updatedOn,omitempty"`
+}
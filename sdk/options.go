@@ -0,0 +1,50 @@
+package sdk
+
+import (
+	"log/slog"
+
+	"gorm.io/gorm"
+
+	sdkServices "github.com/wecredit/communication-sdk/sdk/services"
+	"github.com/wecredit/communication-sdk/sdk/utils"
+)
+
+// Option configures optional behaviour of a CommSdkClient.
+type Option func(*CommSdkClient)
+
+// WithDirectDB makes Send write the input row with the DbClient and InputTableName of each message
+// and publish it to SNS from the caller's process, as the SDK did before the ingestion endpoint.
+// It is kept for clients that have not migrated yet.
+func WithDirectDB() Option {
+	return func(c *CommSdkClient) {
+		c.directDB = true
+	}
+}
+
+// WithOutbox makes Send write each message to an outbox table in the same transaction as its input row,
+// instead of publishing it to SNS directly. Run StartOutboxRelay to publish the queued messages.
+// The outbox table must be in the same database as the DbClient passed with each message; it implies WithDirectDB.
+func WithOutbox(db *gorm.DB, table string) Option {
+	return func(c *CommSdkClient) {
+		c.directDB = true
+		c.outbox = sdkServices.NewOutbox(db, table)
+	}
+}
+
+// WithLogger sends the logs of the SDK to l instead of JSON lines on stdout. The logger is shared by the whole
+// process, so the last client created with WithLogger decides it. utils.SetLogLevel still filters what reaches l.
+func WithLogger(l *slog.Logger) Option {
+	return func(c *CommSdkClient) {
+		utils.SetLogger(l)
+	}
+}
+
+// WithMessageSigningKeys signs the messages the client publishes to SNS with the first of the comma separated
+// id:base64 HMAC keys, as the consumer requires once COMM_MESSAGE_SIGNING_KEYS is set. It only applies to clients
+// created WithDirectDB or WithOutbox; messages sent through the ingestion endpoint are signed by the server.
+// The keys are shared by the whole process.
+func WithMessageSigningKeys(keys string) Option {
+	return func(c *CommSdkClient) {
+		c.signingKeys = keys
+	}
+}
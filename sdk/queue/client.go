@@ -5,6 +5,7 @@ import (
 	"sync"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azservicebus"
+	"github.com/wecredit/communication-sdk/sdk/utils"
 )
 
 // Define global variables for the clients
@@ -24,7 +25,7 @@ func InitClient(connString string, isCallback bool) (*azservicebus.Client, error
 			if err != nil {
 				return nil, fmt.Errorf("failed to create Callback Azure Service Bus client: %v", err)
 			}
-			fmt.Println("Callback Azure Bus Service connection successful")
+			utils.Info("Callback Azure Bus Service connection successful")
 			return callbackClient, nil
 		}
 	} else {
@@ -33,7 +34,7 @@ func InitClient(connString string, isCallback bool) (*azservicebus.Client, error
 			if err != nil {
 				return nil, fmt.Errorf("failed to create Azure Service Bus client: %v", err)
 			}
-			fmt.Println("Azure Bus Service connection successful")
+			utils.Info("Azure Bus Service connection successful")
 			return client, nil
 		}
 	}
@@ -46,7 +47,7 @@ func GetClient(connString string) *azservicebus.Client {
 	var err error
 	if Client == nil {
 		if Client, err = InitClient(connString, false); err != nil {
-			fmt.Println("Failed to initialize Azure Service Bus client.")
+			utils.Error(fmt.Errorf("failed to initialize Azure Service Bus client: %v", err))
 			panic(err)
 		}
 	}
@@ -58,7 +59,7 @@ func GetCallbackClient(connString string) *azservicebus.Client {
 	var err error
 	if CallbackClient == nil {
 		if CallbackClient, err = InitClient(connString, true); err != nil {
-			fmt.Println("Failed to initialize Callback Azure Service Bus client.")
+			utils.Error(fmt.Errorf("failed to initialize Callback Azure Service Bus client: %v", err))
 			panic(err)
 		}
 	}
@@ -69,7 +70,7 @@ func GetSdkQueueClient(connString string) *azservicebus.Client {
 	var err error
 	var client *azservicebus.Client
 	if client, err = InitClient(connString, true); err != nil {
-		fmt.Println("Failed to initialize Azure Service Bus client.")
+		utils.Error(fmt.Errorf("failed to initialize Azure Service Bus client: %v", err))
 		panic(err)
 	}
 	return client
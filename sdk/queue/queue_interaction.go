@@ -4,13 +4,18 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azservicebus"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go/service/sns"
 	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/wecredit/communication-sdk/internal/snsAuth"
+	"github.com/wecredit/communication-sdk/internal/tracing"
 	"github.com/wecredit/communication-sdk/sdk/utils"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -18,8 +23,40 @@ const (
 	retryBackoff = 2 * time.Second // Wait between retries
 )
 
+var messageSigningKeys atomic.Pointer[snsAuth.SigningKeys]
+
+// SetSigningKeys sets the COMM_MESSAGE_SIGNING_KEYS messages published to SNS are signed with, so the consumer
+// can tell them from messages of unknown producers.
+func SetSigningKeys(value string) error {
+	keys, err := snsAuth.ParseSigningKeys(value)
+	if err != nil {
+		return err
+	}
+	messageSigningKeys.Store(&keys)
+	return nil
+}
+
+func signingKeys() snsAuth.SigningKeys {
+	if keys := messageSigningKeys.Load(); keys != nil {
+		return *keys
+	}
+	return snsAuth.SigningKeys{}
+}
+
 // SendMessage sends a message to an AWS SNS topic with the subject as a message attribute
 func SendMessageToAwsQueue(client *sns.SNS, messageMap interface{}, topicARN string, subject string) error {
+	return SendMessageToAwsQueueWithContext(context.Background(), client, messageMap, topicARN, subject)
+}
+
+// SendMessageToAwsQueueWithContext is SendMessageToAwsQueue in a publish span, whose trace context is added to the
+// message attributes so the consumer continues the trace of ctx.
+func SendMessageToAwsQueueWithContext(ctx context.Context, client *sns.SNS, messageMap interface{}, topicARN string, subject string) (err error) {
+	ctx, span := tracing.Start(ctx, "sns.publish", trace.SpanKindProducer,
+		attribute.String("messaging.system", "aws_sns"),
+		attribute.String("messaging.destination.name", topicARN),
+	)
+	defer func() { tracing.End(span, err) }()
+
 	// Convert message to JSON
 	messageBytes, err := json.Marshal(messageMap)
 	if err != nil {
@@ -33,9 +70,21 @@ func SendMessageToAwsQueue(client *sns.SNS, messageMap interface{}, topicARN str
 			StringValue: aws.String(subject),
 		},
 	}
+	tracing.InjectSNS(ctx, messageAttributes)
+	signedAt := time.Now()
+	if signature := signingKeys().Sign(subject, string(messageBytes), signedAt); signature != "" {
+		messageAttributes[snsAuth.SignatureAttribute] = &sns.MessageAttributeValue{
+			DataType:    aws.String("String"),
+			StringValue: aws.String(signature),
+		}
+		messageAttributes[snsAuth.SignedAtAttribute] = &sns.MessageAttributeValue{
+			DataType:    aws.String("String"),
+			StringValue: aws.String(snsAuth.FormatSignedAt(signedAt)),
+		}
+	}
 
 	// Publish message using global SNSClient
-	response, err := client.Publish(&sns.PublishInput{
+	response, err := client.PublishWithContext(ctx, &sns.PublishInput{
 		Message:           aws.String(string(messageBytes)),
 		TopicArn:          aws.String(topicARN),
 		MessageAttributes: messageAttributes,
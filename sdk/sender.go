@@ -1,15 +1,44 @@
 package sdk
 
 import (
+	"context"
 	"fmt"
+	"net/http"
 	"strings"
+	"time"
 
+	"github.com/wecredit/communication-sdk/internal/tracing"
 	"github.com/wecredit/communication-sdk/sdk/models/sdkModels"
 	sdkServices "github.com/wecredit/communication-sdk/sdk/services"
 	"github.com/wecredit/communication-sdk/sdk/utils"
+	"github.com/wecredit/communication-sdk/sdk/variables"
+	"go.opentelemetry.io/otel/trace"
 )
 
 func (c *CommSdkClient) Send(msg *sdkModels.CommApiRequestBody) (*sdkModels.CommApiResponseBody, error) {
+	return c.SendWithContext(context.Background(), msg)
+}
+
+// SendWithContext is Send as a child of the span in ctx. The trace continues through the consumer to the vendor
+// call when the caller has installed an OpenTelemetry tracer provider.
+func (c *CommSdkClient) SendWithContext(ctx context.Context, msg *sdkModels.CommApiRequestBody) (response *sdkModels.CommApiResponseBody, err error) {
+	start := time.Now()
+	ctx, span := tracing.Start(ctx, "sdk.Send", trace.SpanKindClient)
+	defer func() {
+		if response != nil && response.CommId != "" {
+			span.SetAttributes(tracing.AttrCommId.String(response.CommId))
+		}
+		tracing.End(span, err)
+	}()
+
+	response, err = c.send(ctx, msg)
+	if c != nil && msg != nil {
+		c.metrics.observe(strings.ToUpper(msg.Channel), start, err)
+	}
+	return response, err
+}
+
+func (c *CommSdkClient) send(ctx context.Context, msg *sdkModels.CommApiRequestBody) (*sdkModels.CommApiResponseBody, error) {
 	if c == nil {
 		return &sdkModels.CommApiResponseBody{Success: false}, fmt.Errorf("please initialize the client first")
 	}
@@ -26,16 +55,30 @@ func (c *CommSdkClient) Send(msg *sdkModels.CommApiRequestBody) (*sdkModels.Comm
 	if c.Channel != msg.Channel {
 		return &sdkModels.CommApiResponseBody{Success: false}, fmt.Errorf("channel mismatch: expected %s, got %s", c.Channel, msg.Channel)
 	}
+
+	msg.Client = c.ClientName
+	if !c.directDB {
+		// The server writes the input row itself, so a message meant for the caller's own table would not be stored there
+		if msg.DbClient != nil || msg.InputTableName != "" {
+			return &sdkModels.CommApiResponseBody{Success: false}, fmt.Errorf("%w: DbClient and InputTableName are only used by clients created WithDirectDB or WithOutbox", sdkServices.ErrInvalidRequest)
+		}
+		return c.sendThroughServer(ctx, msg)
+	}
+
 	if c.AwsSnsClient == nil || c.TopicArn == "" {
 		return &sdkModels.CommApiResponseBody{Success: false}, fmt.Errorf("aws sns client or topic arn not initialized")
 	}
-
-	msg.Client = c.ClientName
 	if c.RedisClient == nil {
 		return &sdkModels.CommApiResponseBody{Success: false}, fmt.Errorf("redis client not initialized")
 	}
 
-	response, err := sdkServices.ProcessCommApiData(msg, c.AwsSnsClient, c.TopicArn, c.RedisClient)
+	var response sdkModels.CommApiResponseBody
+	var err error
+	if c.outbox != nil {
+		response, err = sdkServices.ProcessCommApiDataWithOutbox(ctx, msg, c.outbox, c.TopicArn, c.RedisClient)
+	} else {
+		response, err = sdkServices.ProcessCommApiData(ctx, msg, c.AwsSnsClient, c.TopicArn, c.RedisClient)
+	}
 	if err != nil {
 		utils.Error(fmt.Errorf("error in processing message for mobile %s and channel %s for stage %f: %v", msg.Mobile, msg.Channel, msg.Stage, err))
 		return &sdkModels.CommApiResponseBody{Success: false}, err
@@ -43,3 +86,59 @@ func (c *CommSdkClient) Send(msg *sdkModels.CommApiRequestBody) (*sdkModels.Comm
 
 	return &response, nil
 }
+
+// sendThroughServer posts the message to the ingestion endpoint, which writes the input row and publishes it.
+func (c *CommSdkClient) sendThroughServer(ctx context.Context, msg *sdkModels.CommApiRequestBody) (*sdkModels.CommApiResponseBody, error) {
+	apiUrl := c.baseUrl + "/v1/communications"
+	apiHeaders := map[string]string{
+		"Content-Type": "application/json",
+	}
+	tracing.InjectHeaders(ctx, apiHeaders)
+
+	apiResponse, err := utils.ApiCall(ctx, variables.PostMethod, apiUrl, apiHeaders, c.username, c.password, msg, variables.ContentTypeJSON)
+	if err != nil {
+		utils.Error(fmt.Errorf("error in sending message for mobile %s and channel %s for stage %f: %v", msg.Mobile, msg.Channel, msg.Stage, err))
+		return &sdkModels.CommApiResponseBody{Success: false}, err
+	}
+
+	var response sdkModels.CommApiResultBody
+	decodeErr := apiResponse.Decode(&response)
+	if statusCode := apiResponse.StatusCode; statusCode != http.StatusOK {
+		message := response.Error
+		if decodeErr != nil {
+			message = decodeErr.Error()
+		}
+		err := fmt.Errorf("communication api returned status %d: %s", statusCode, message)
+		// The same errors as with WithDirectDB, so callers can tell them apart either way
+		switch statusCode {
+		case http.StatusUnprocessableEntity:
+			err = fmt.Errorf("%w: %v", sdkServices.ErrInvalidRequest, err)
+		case http.StatusConflict:
+			err = fmt.Errorf("%w: %v", sdkServices.ErrDuplicateMessage, err)
+		case http.StatusServiceUnavailable:
+			err = fmt.Errorf("%w: %v", sdkServices.ErrIdempotencyUnavailable, err)
+		}
+		return &sdkModels.CommApiResponseBody{Success: false}, err
+	}
+	if decodeErr != nil {
+		return &sdkModels.CommApiResponseBody{Success: false}, decodeErr
+	}
+
+	commId := response.CommId
+	msg.CommId = commId
+	return &sdkModels.CommApiResponseBody{Success: true, CommId: commId}, nil
+}
+
+// StartOutboxRelay publishes the messages Send wrote to the outbox until ctx is cancelled.
+// It is only needed for clients created with WithOutbox.
+func (c *CommSdkClient) StartOutboxRelay(ctx context.Context) error {
+	if c == nil || c.outbox == nil {
+		return fmt.Errorf("outbox is not enabled for this client")
+	}
+	if c.AwsSnsClient == nil || c.RedisClient == nil {
+		return fmt.Errorf("aws sns client or redis client not initialized")
+	}
+
+	c.outbox.StartRelay(ctx, c.AwsSnsClient, c.RedisClient)
+	return nil
+}
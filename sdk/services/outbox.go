@@ -0,0 +1,200 @@
+package sdkServices
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/redis/go-redis/v9"
+	"github.com/wecredit/communication-sdk/internal/database"
+	"github.com/wecredit/communication-sdk/internal/tracing"
+	"github.com/wecredit/communication-sdk/sdk/models/sdkModels"
+	"github.com/wecredit/communication-sdk/sdk/queue"
+	"github.com/wecredit/communication-sdk/sdk/utils"
+	"github.com/wecredit/communication-sdk/sdk/variables"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultOutboxPollInterval = 2 * time.Second
+	defaultOutboxMaxAttempts  = 10
+	outboxBaseBackoff         = 5 * time.Second
+	outboxMaxBackoff          = 10 * time.Minute
+	outboxInFlightTimeout     = 2 * time.Minute
+	outboxBatch               = 50
+)
+
+// Outbox holds messages written by Send until the relay has published them to SNS.
+// Its table must live in the same database as the input tables, so both rows share one transaction.
+type Outbox struct {
+	DB           *gorm.DB
+	Table        string
+	PollInterval time.Duration
+	MaxAttempts  int
+}
+
+func NewOutbox(db *gorm.DB, table string) *Outbox {
+	return &Outbox{
+		DB:           db,
+		Table:        table,
+		PollInterval: defaultOutboxPollInterval,
+		MaxAttempts:  defaultOutboxMaxAttempts,
+	}
+}
+
+// Enqueue writes the input row and the outbox row in one transaction.
+// The trace context of ctx is stored with the message, so its publish joins the trace of Send.
+func (o *Outbox) Enqueue(ctx context.Context, data *sdkModels.CommApiRequestBody, dbMappedData, dataMap map[string]interface{}, topicArn, subject, redisKey string) error {
+	payload, err := json.Marshal(dataMap)
+	if err != nil {
+		return fmt.Errorf("failed to serialize outbox payload: %w", err)
+	}
+	traceContext, err := json.Marshal(tracing.Carrier(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to serialize outbox trace context: %w", err)
+	}
+
+	db := data.DbClient
+	if db == nil {
+		db = o.DB
+	}
+
+	now := utils.IstNow()
+	message := sdkModels.OutboxMessage{
+		CommId:        data.CommId,
+		RedisKey:      redisKey,
+		TopicArn:      topicArn,
+		Subject:       subject,
+		Payload:       string(payload),
+		TraceContext:  string(traceContext),
+		Status:        variables.OutboxPending,
+		NextAttemptOn: now,
+		CreatedOn:     now,
+	}
+
+	return db.Session(&gorm.Session{NewDB: true}).Transaction(func(tx *gorm.DB) error {
+		if err := database.InsertDataTx(data.InputTableName, tx, dbMappedData); err != nil {
+			return err
+		}
+		if err := tx.Table(o.Table).Create(&message).Error; err != nil {
+			return fmt.Errorf("failed to insert outbox message: %w", err)
+		}
+		return nil
+	})
+}
+
+// StartRelay publishes pending outbox messages to SNS until ctx is cancelled.
+// Failed publishes are retried with backoff; a message that keeps failing is marked FAILED and its
+// Redis idempotency key is released so the caller can send it again.
+func (o *Outbox) StartRelay(ctx context.Context, snsClient *sns.SNS, redisClient *redis.Client) {
+	utils.Info(fmt.Sprintf("Starting outbox relay on %s with poll interval %s", o.Table, o.PollInterval))
+	ticker := time.NewTicker(o.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			utils.Warn("Context cancelled. Stopping outbox relay.")
+			return
+		case <-ticker.C:
+		}
+		o.relayDue(ctx, snsClient, redisClient)
+	}
+}
+
+func (o *Outbox) relayDue(ctx context.Context, snsClient *sns.SNS, redisClient *redis.Client) {
+	now := utils.IstNow()
+
+	var messages []sdkModels.OutboxMessage
+	err := o.DB.Table(o.Table).
+		Where("(Status = ? AND NextAttemptOn <= ?) OR (Status = ? AND UpdatedOn <= ?)",
+			variables.OutboxPending, now, variables.OutboxInFlight, now.Add(-outboxInFlightTimeout)).
+		Order("NextAttemptOn").
+		Limit(outboxBatch).
+		Find(&messages).Error
+	if err != nil {
+		utils.Error(fmt.Errorf("failed to fetch due outbox messages: %v", err))
+		return
+	}
+
+	for _, message := range messages {
+		if ctx.Err() != nil {
+			return
+		}
+		if !o.claim(message) {
+			continue // picked up by another relay
+		}
+		o.publish(snsClient, redisClient, message)
+	}
+}
+
+// claim moves a message to IN_FLIGHT only if nobody else changed it since it was read
+func (o *Outbox) claim(message sdkModels.OutboxMessage) bool {
+	result := o.DB.Table(o.Table).
+		Where("Id = ? AND Status = ? AND Attempts = ?", message.Id, message.Status, message.Attempts).
+		Updates(map[string]interface{}{
+			"Status":    variables.OutboxInFlight,
+			"UpdatedOn": utils.IstNow(),
+		})
+	if result.Error != nil {
+		utils.Error(fmt.Errorf("failed to claim outbox message %d: %v", message.Id, result.Error))
+		return false
+	}
+	return result.RowsAffected == 1
+}
+
+func (o *Outbox) publish(snsClient *sns.SNS, redisClient *redis.Client, message sdkModels.OutboxMessage) {
+	// Rows written before the trace context was stored publish in a new trace
+	var carrier map[string]string
+	_ = json.Unmarshal([]byte(message.TraceContext), &carrier)
+	ctx := tracing.FromCarrier(context.Background(), carrier)
+
+	var dataMap map[string]interface{}
+	err := json.Unmarshal([]byte(message.Payload), &dataMap)
+	if err == nil {
+		err = queue.SendMessageToAwsQueueWithContext(ctx, snsClient, dataMap, message.TopicArn, message.Subject)
+	}
+
+	attempts := message.Attempts + 1
+	now := utils.IstNow()
+	updates := map[string]interface{}{
+		"Attempts":  attempts,
+		"UpdatedOn": now,
+	}
+
+	if err == nil {
+		updates["Status"] = variables.OutboxSent
+		updates["LastError"] = ""
+		updates["SentOn"] = now
+		utils.Info(fmt.Sprintf("[CommId:%s] outbox message published to AWS SNS on attempt %d", message.CommId, attempts))
+	} else {
+		updates["LastError"] = err.Error()
+		if attempts >= o.MaxAttempts {
+			updates["Status"] = variables.OutboxFailed
+			utils.Error(fmt.Errorf("[CommId:%s] outbox message %d failed permanently after %d attempts: %v", message.CommId, message.Id, attempts, err))
+			rollbackIdempotencyKey(redisClient, message.RedisKey)
+		} else {
+			updates["Status"] = variables.OutboxPending
+			updates["NextAttemptOn"] = now.Add(outboxBackoff(attempts))
+			utils.Warn(fmt.Sprintf("[CommId:%s] outbox message %d attempt %d failed, retrying in %s: %v", message.CommId, message.Id, attempts, outboxBackoff(attempts), err))
+		}
+	}
+
+	if err := o.DB.Table(o.Table).Where("Id = ?", message.Id).Updates(updates).Error; err != nil {
+		utils.Error(fmt.Errorf("failed to update outbox message %d: %v", message.Id, err))
+	}
+}
+
+// outboxBackoff doubles the wait after every failed attempt, capped at outboxMaxBackoff
+func outboxBackoff(attempts int) time.Duration {
+	wait := outboxBaseBackoff
+	for i := 1; i < attempts; i++ {
+		wait *= 2
+		if wait >= outboxMaxBackoff {
+			return outboxMaxBackoff
+		}
+	}
+	return wait
+}
@@ -1,7 +1,9 @@
 package sdkServices
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -12,13 +14,26 @@ import (
 	"github.com/wecredit/communication-sdk/config"
 	"github.com/wecredit/communication-sdk/internal/channels/channelHelper"
 	"github.com/wecredit/communication-sdk/internal/database"
+	"github.com/wecredit/communication-sdk/internal/pii"
 	redisInteraction "github.com/wecredit/communication-sdk/internal/redis"
 	dbservices "github.com/wecredit/communication-sdk/internal/services/dbService"
+	"github.com/wecredit/communication-sdk/internal/tracing"
 	sdkHelper "github.com/wecredit/communication-sdk/sdk/helper"
 	"github.com/wecredit/communication-sdk/sdk/models/sdkModels"
 	"github.com/wecredit/communication-sdk/sdk/queue"
 	"github.com/wecredit/communication-sdk/sdk/utils"
 	"github.com/wecredit/communication-sdk/sdk/variables"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	// ErrInvalidRequest is returned when a message fails validation.
+	ErrInvalidRequest = errors.New("invalid request")
+	// ErrDuplicateMessage is returned when a message was already accepted for the same mobile, channel and stage.
+	ErrDuplicateMessage = errors.New("duplicate message")
+	// ErrIdempotencyUnavailable is returned when Redis could not tell whether the message is a duplicate; the
+	// message was not accepted and can be retried.
+	ErrIdempotencyUnavailable = errors.New("idempotency check unavailable")
 )
 
 // GenerateCommID generates a unique lead ID using the UUID library
@@ -35,18 +50,78 @@ func GenerateCommID() string {
 	return commID
 }
 
-func ProcessCommApiData(data *sdkModels.CommApiRequestBody, snsClient *sns.SNS, topicArn string, redisClient *redis.Client) (sdkModels.CommApiResponseBody, error) {
+func ProcessCommApiData(ctx context.Context, data *sdkModels.CommApiRequestBody, snsClient *sns.SNS, topicArn string, redisClient *redis.Client) (sdkModels.CommApiResponseBody, error) {
+	redisKey, err := reserveIdempotencyKey(data, redisClient)
+	if err != nil {
+		return sdkModels.CommApiResponseBody{Success: false}, err
+	}
+
+	subject, dbMappedData, dataMap, err := prepareCommData(data)
+	if err != nil {
+		rollbackIdempotencyKey(redisClient, redisKey)
+		return sdkModels.CommApiResponseBody{Success: false}, err
+	}
+	trace.SpanFromContext(ctx).SetAttributes(tracing.AttrCommId.String(data.CommId))
+
+	if err := database.InsertData(data.InputTableName, data.DbClient, dbMappedData); err != nil {
+		utils.Error(fmt.Errorf("error inserting data into input table %s for mobile %s and channel %s: %v", data.InputTableName, data.Mobile, data.Channel, err))
+		rollbackIdempotencyKey(redisClient, redisKey)
+		return sdkModels.CommApiResponseBody{Success: false}, fmt.Errorf("error inserting data into input table %s for mobile %s and channel %s: %v", data.InputTableName, data.Mobile, data.Channel, err)
+	}
+
+	// Send the map to AWS Queue
+	err = queue.SendMessageToAwsQueueWithContext(ctx, snsClient, dataMap, topicArn, subject)
+	if err != nil {
+		utils.Error(fmt.Errorf("error occurred while sending data to queue for mobile %s and channel %s: %w", data.Mobile, data.Channel, err))
+		// The message never reached the queue, so a retry must not be rejected as already processed
+		rollbackIdempotencyKey(redisClient, redisKey)
+		return sdkModels.CommApiResponseBody{
+			Success: false,
+		}, fmt.Errorf("error occurred while sending data to queue for mobile %s and channel %s: %w", data.Mobile, data.Channel, err)
+	}
+	utils.Info(fmt.Sprintf("Message sent to AWS SNS for mobile %s and channel %s for stage %f", data.Mobile, data.Channel, data.Stage))
+
+	return sdkModels.CommApiResponseBody{Success: true, CommId: data.CommId}, nil
+}
+
+// ProcessCommApiDataWithOutbox stores the message and its outbox row in one transaction instead of publishing it.
+// The outbox relay publishes it to SNS afterwards, so the input row and the queue cannot diverge.
+func ProcessCommApiDataWithOutbox(ctx context.Context, data *sdkModels.CommApiRequestBody, outbox *Outbox, topicArn string, redisClient *redis.Client) (sdkModels.CommApiResponseBody, error) {
+	redisKey, err := reserveIdempotencyKey(data, redisClient)
+	if err != nil {
+		return sdkModels.CommApiResponseBody{Success: false}, err
+	}
+
+	subject, dbMappedData, dataMap, err := prepareCommData(data)
+	if err != nil {
+		rollbackIdempotencyKey(redisClient, redisKey)
+		return sdkModels.CommApiResponseBody{Success: false}, err
+	}
+	trace.SpanFromContext(ctx).SetAttributes(tracing.AttrCommId.String(data.CommId))
+
+	if err := outbox.Enqueue(ctx, data, dbMappedData, dataMap, topicArn, subject, redisKey); err != nil {
+		utils.Error(fmt.Errorf("error writing message to outbox for mobile %s and channel %s: %v", data.Mobile, data.Channel, err))
+		rollbackIdempotencyKey(redisClient, redisKey)
+		return sdkModels.CommApiResponseBody{Success: false}, fmt.Errorf("error writing message to outbox for mobile %s and channel %s: %v", data.Mobile, data.Channel, err)
+	}
+	utils.Info(fmt.Sprintf("Message queued in outbox for mobile %s and channel %s for stage %f", data.Mobile, data.Channel, data.Stage))
+
+	return sdkModels.CommApiResponseBody{Success: true, CommId: data.CommId}, nil
+}
+
+// reserveIdempotencyKey validates the request and claims its mobile/channel/stage key in Redis.
+func reserveIdempotencyKey(data *sdkModels.CommApiRequestBody, redisClient *redis.Client) (string, error) {
 	isValidate, message := sdkHelper.ValidateCommRequest(*data)
 
 	if !isValidate {
-		return sdkModels.CommApiResponseBody{Success: false}, fmt.Errorf("%s", message)
+		return "", fmt.Errorf("%w: %s", ErrInvalidRequest, message)
 	}
 
 	redisKey := channelHelper.GenerateRedisKey(data.Mobile, data.Channel, data.Stage)
 	exists, transactionId, errorMessage, err := redisInteraction.GetMobileDataFromRedis(config.Configs.CommIdempotentKey, redisKey, redisClient)
 	if err != nil {
 		utils.Error(fmt.Errorf("error in checking mobile: %s, redisKey: %s on redis: %v", data.Mobile, redisKey, err))
-		return sdkModels.CommApiResponseBody{Success: false}, fmt.Errorf("error in checking mobile: %s, redisKey: %s on redis: %v", data.Mobile, redisKey, err)
+		return "", fmt.Errorf("%w: error in checking mobile: %s, redisKey: %s on redis: %v", ErrIdempotencyUnavailable, data.Mobile, redisKey, err)
 	}
 
 	// If we have data from Redis, handle accordingly
@@ -57,22 +132,22 @@ func ProcessCommApiData(data *sdkModels.CommApiRequestBody, snsClient *sns.SNS,
 			// dataExistsAlready, err := services.CheckIfDataAlreadyExists(*data, redisKey, transactionId)
 			// if err != nil {
 			// 	utils.Error(fmt.Errorf("error checking if data exists for mobile: %s, redisKey: %s, transactionId: %s: %v", data.Mobile, redisKey, transactionId, err))
-			// 	return sdkModels.CommApiResponseBody{Success: false}, fmt.Errorf("error checking if data exists for mobile: %s, redisKey: %s, transactionId: %s: %v", data.Mobile, redisKey, transactionId, err)
+			// 	return "", fmt.Errorf("error checking if data exists for mobile: %s, redisKey: %s, transactionId: %s: %v", data.Mobile, redisKey, transactionId, err)
 			// }
 
 			// // for debugging purpose
 			// if dataExistsAlready {
 			// 	utils.Debug(fmt.Sprintf("Data already exists in output table for mobile: %s and channel: %s, redisKey: %s, transactionId: %s", data.Mobile, data.Channel, redisKey, transactionId))
-			return sdkModels.CommApiResponseBody{Success: false}, fmt.Errorf("data already exists in output table for mobile: %s and channel: %s, redisKey: %s, transactionId: %s", data.Mobile, data.Channel, redisKey, transactionId)
+			return "", fmt.Errorf("%w: data already exists in output table for mobile: %s and channel: %s, redisKey: %s, transactionId: %s", ErrDuplicateMessage, data.Mobile, data.Channel, redisKey, transactionId)
 		}
 
 		// If we have an error message (and no transactionId), return error
 		if errorMessage != "" && transactionId == "" {
-			return sdkModels.CommApiResponseBody{Success: false}, fmt.Errorf("message already processed for mobile: %s and channel: %s, redisKey: %s with error: %s", data.Mobile, data.Channel, redisKey, errorMessage)
+			return "", fmt.Errorf("%w: message already processed for mobile: %s and channel: %s, redisKey: %s with error: %s", ErrDuplicateMessage, data.Mobile, data.Channel, redisKey, errorMessage)
 		}
 
 		// Redis key exists but no transactionId or errorMessage - return error
-		return sdkModels.CommApiResponseBody{Success: false}, fmt.Errorf("message already processed for redisKey: %s (key exists but no transactionId/errorMessage)", redisKey)
+		return "", fmt.Errorf("%w: message already processed for redisKey: %s (key exists but no transactionId/errorMessage)", ErrDuplicateMessage, redisKey)
 	}
 
 	// If not exists, add key with blank value
@@ -85,23 +160,29 @@ func ProcessCommApiData(data *sdkModels.CommApiRequestBody, snsClient *sns.SNS,
 			// Key was created between our check and set - re-check to get full details
 			exists, transactionId, errorMessage, err := redisInteraction.GetMobileDataFromRedis(config.Configs.CommIdempotentKey, redisKey, redisClient)
 			if err != nil {
+				// The conflict says another request holds the key, but not whether it was accepted, so the caller retries
 				utils.Error(fmt.Errorf("error re-checking mobile after key creation conflict: %s, redisKey: %s: %v", data.Mobile, redisKey, err))
-				return sdkModels.CommApiResponseBody{Success: false}, fmt.Errorf("key already exists for mobile: %s and channel: %s, redisKey: %s", data.Mobile, data.Channel, redisKey)
+				return "", fmt.Errorf("%w: error re-checking mobile: %s and channel: %s, redisKey: %s: %v", ErrIdempotencyUnavailable, data.Mobile, data.Channel, redisKey, err)
 			}
 			if exists {
 				if transactionId != "" {
-					return sdkModels.CommApiResponseBody{Success: false}, fmt.Errorf("data already exists for mobile: %s and channel: %s, redisKey: %s, transactionId: %s", data.Mobile, data.Channel, redisKey, transactionId)
+					return "", fmt.Errorf("%w: data already exists for mobile: %s and channel: %s, redisKey: %s, transactionId: %s", ErrDuplicateMessage, data.Mobile, data.Channel, redisKey, transactionId)
 				}
 				if errorMessage != "" {
-					return sdkModels.CommApiResponseBody{Success: false}, fmt.Errorf("message already processed for mobile: %s and channel: %s, redisKey: %s with error: %s", data.Mobile, data.Channel, redisKey, errorMessage)
+					return "", fmt.Errorf("%w: message already processed for mobile: %s and channel: %s, redisKey: %s with error: %s", ErrDuplicateMessage, data.Mobile, data.Channel, redisKey, errorMessage)
 				}
-				return sdkModels.CommApiResponseBody{Success: false}, fmt.Errorf("message already processed for redisKey: %s (key exists but no transactionId/errorMessage)", redisKey)
+				return "", fmt.Errorf("%w: message already processed for redisKey: %s (key exists but no transactionId/errorMessage)", ErrDuplicateMessage, redisKey)
 			}
 		}
 		utils.Error(fmt.Errorf("redis add failed for mobile: %s, channel: %s, redisKey: %s: %v", data.Mobile, data.Channel, redisKey, redisSetErr))
-		return sdkModels.CommApiResponseBody{Success: false}, fmt.Errorf("redis add failed for mobile: %s, channel: %s, redisKey: %s: %v", data.Mobile, data.Channel, redisKey, redisSetErr)
+		return "", fmt.Errorf("%w: redis add failed for mobile: %s, channel: %s, redisKey: %s: %v", ErrIdempotencyUnavailable, data.Mobile, data.Channel, redisKey, redisSetErr)
 	}
 
+	return redisKey, nil
+}
+
+// prepareCommData assigns the CommId and builds the input row and the queue message for the request.
+func prepareCommData(data *sdkModels.CommApiRequestBody) (string, map[string]interface{}, map[string]interface{}, error) {
 	// Set CommId for requested Data
 	data.CommId = GenerateCommID()
 
@@ -116,7 +197,7 @@ func ProcessCommApiData(data *sdkModels.CommApiRequestBody, snsClient *sns.SNS,
 	dbMappedData, err := dbservices.MapIntoDbModel(data)
 	if err != nil {
 		utils.Error(fmt.Errorf("error in mapping data into dbModel for mobile %s and channel %s: %v", data.Mobile, data.Channel, err))
-		return sdkModels.CommApiResponseBody{Success: false}, fmt.Errorf("error in mapping data into dbModel for mobile %s and channel %s: %v", data.Mobile, data.Channel, err)
+		return "", nil, nil, fmt.Errorf("error in mapping data into dbModel for mobile %s and channel %s: %v", data.Mobile, data.Channel, err)
 	}
 
 	if data.Channel == variables.Email {
@@ -124,11 +205,24 @@ func ProcessCommApiData(data *sdkModels.CommApiRequestBody, snsClient *sns.SNS,
 		dbMappedData["Email"] = data.Email
 	}
 
+	// Personal data is stored and published encrypted, the queued message keeping it for the consumer only
+	if err := pii.ProtectRow(data.CommId, dbMappedData); err != nil {
+		utils.Error(fmt.Errorf("failed to protect input row of CommId %s: %v", data.CommId, err))
+		return "", nil, nil, fmt.Errorf("failed to protect input row of CommId %s: %v", data.CommId, err)
+	}
+	payload := *data
+	if pii.Enabled() {
+		if payload, err = pii.EncryptRequest(*data); err != nil {
+			utils.Error(fmt.Errorf("failed to encrypt message of CommId %s: %v", data.CommId, err))
+			return "", nil, nil, fmt.Errorf("failed to encrypt message of CommId %s: %v", data.CommId, err)
+		}
+	}
+
 	// Convert the struct to JSON (byte slice)
-	jsonBytes, err := json.Marshal(data)
+	jsonBytes, err := json.Marshal(payload)
 	if err != nil {
 		utils.Error(fmt.Errorf("failed to serialize data for mobile %s and channel %s: %w", data.Mobile, data.Channel, err))
-		return sdkModels.CommApiResponseBody{Success: false}, fmt.Errorf("failed to serialize data for mobile %s and channel %s: %w", data.Mobile, data.Channel, err)
+		return "", nil, nil, fmt.Errorf("failed to serialize data for mobile %s and channel %s: %w", data.Mobile, data.Channel, err)
 	}
 
 	// Initialize the map
@@ -138,23 +232,15 @@ func ProcessCommApiData(data *sdkModels.CommApiRequestBody, snsClient *sns.SNS,
 	err = json.Unmarshal(jsonBytes, &dataMap)
 	if err != nil {
 		utils.Error(fmt.Errorf("failed to convert data to map for mobile %s and channel %s: %w", data.Mobile, data.Channel, err))
-		return sdkModels.CommApiResponseBody{Success: false}, fmt.Errorf("failed to convert data to map for mobile %s and channel %s: %w", data.Mobile, data.Channel, err)
+		return "", nil, nil, fmt.Errorf("failed to convert data to map for mobile %s and channel %s: %w", data.Mobile, data.Channel, err)
 	}
 
-	if err := database.InsertData(data.InputTableName, data.DbClient, dbMappedData); err != nil {
-		utils.Error(fmt.Errorf("error inserting data into input table %s for mobile %s and channel %s: %v", data.InputTableName, data.Mobile, data.Channel, err))
-		return sdkModels.CommApiResponseBody{Success: false}, fmt.Errorf("error inserting data into input table %s for mobile %s and channel %s: %v", data.InputTableName, data.Mobile, data.Channel, err)
-	}
+	return subject, dbMappedData, dataMap, nil
+}
 
-	// Send the map to AWS Queue
-	err = queue.SendMessageToAwsQueue(snsClient, dataMap, topicArn, subject)
-	if err != nil {
-		utils.Error(fmt.Errorf("error occurred while sending data to queue for mobile %s and channel %s: %w", data.Mobile, data.Channel, err))
-		return sdkModels.CommApiResponseBody{
-			Success: false,
-		}, fmt.Errorf("error occurred while sending data to queue for mobile %s and channel %s: %w", data.Mobile, data.Channel, err)
+// rollbackIdempotencyKey releases a key claimed for a message that was never queued, so it can be sent again.
+func rollbackIdempotencyKey(redisClient *redis.Client, redisKey string) {
+	if err := redisInteraction.DeleteMobileChannelKey(redisClient, config.Configs.CommIdempotentKey, redisKey); err != nil {
+		utils.Error(fmt.Errorf("failed to roll back redisKey %s: %v", redisKey, err))
 	}
-	utils.Info(fmt.Sprintf("Message sent to AWS SNS for mobile %s and channel %s for stage %f", data.Mobile, data.Channel, data.Stage))
-
-	return sdkModels.CommApiResponseBody{Success: true, CommId: data.CommId}, nil
 }
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -9,10 +10,36 @@ import (
 	"net/url"
 	"time"
 
+	"github.com/hashicorp/go-cleanhttp"
 	"github.com/hashicorp/go-retryablehttp"
 	"github.com/wecredit/communication-sdk/sdk/variables"
 )
 
+// pooledTransport is shared by every call, so connections are reused rather than opened per request.
+var pooledTransport = cleanhttp.DefaultPooledTransport()
+
+// apiTimeout bounds each attempt of a call, so a hung server never blocks the caller.
+const apiTimeout = 30 * time.Second
+
+// ApiResponse is the raw answer of an API.
+type ApiResponse struct {
+	StatusCode int
+	Body       []byte
+}
+
+// Decode unmarshals the JSON body into v. A body that is not JSON is reported with its status code and content.
+func (r *ApiResponse) Decode(v interface{}) error {
+	if err := json.Unmarshal(r.Body, v); err != nil {
+		return fmt.Errorf("error unmarshalling response with status %d: %v: %s", r.StatusCode, err, truncateBody(r.Body))
+	}
+	return nil
+}
+
+// ApiCall sends the request with ctx and returns the answer whatever its status; only transport failures are errors.
+func ApiCall(ctx context.Context, method, apiURL string, headers map[string]string, username, password string, data interface{}, reqType int) (*ApiResponse, error) {
+	return doApiCall(ctx, newApiClient(0, 0, 0), method, apiURL, headers, username, password, data, reqType)
+}
+
 // RetryApiCall handles retries for an API call
 func RetryApiCall(
 	method, apiURL string,
@@ -23,11 +50,41 @@ func RetryApiCall(
 	retryMax int,
 	retryWaitMin, retryWaitMax time.Duration,
 ) (map[string]interface{}, error) {
+	response, err := doApiCall(context.Background(), newApiClient(retryMax, retryWaitMin, retryWaitMax), method, apiURL, headers, username, password, data, reqType)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse JSON response
+	var result map[string]interface{}
+	if err := response.Decode(&result); err != nil {
+		return nil, err
+	}
+	Info(fmt.Sprintf("API_RESPONSE: %v", result))
+	result["ApistatusCode"] = response.StatusCode
+	return result, nil
+}
+
+// ApiHit makes an API call with optional retries
+//
+// Deprecated: use ApiCall, which takes a context and decodes into a typed response.
+func ApiHit(method, apiURL string, headers map[string]string, username, password string, data interface{}, reqType int) (map[string]interface{}, error) {
+	return RetryApiCall(method, apiURL, headers, username, password, data, reqType, 0, 0*time.Second, 0*time.Second) //TODO: Retry api call is paused for now
+}
+
+func newApiClient(retryMax int, retryWaitMin, retryWaitMax time.Duration) *retryablehttp.Client {
 	client := retryablehttp.NewClient()
+	client.HTTPClient = &http.Client{Transport: pooledTransport, Timeout: apiTimeout}
 	client.RetryMax = retryMax
 	client.RetryWaitMin = retryWaitMin
 	client.RetryWaitMax = retryWaitMax
+	client.Logger = nil
+	// The last response is returned instead of an error, so its status code and body are not lost
+	client.ErrorHandler = retryablehttp.PassthroughErrorHandler
+	return client
+}
 
+func doApiCall(ctx context.Context, client *retryablehttp.Client, method, apiURL string, headers map[string]string, username, password string, data interface{}, reqType int) (*ApiResponse, error) {
 	var bodyBuffer *bytes.Buffer
 	// Prepare the request body
 	if reqType == variables.ContentTypeFormEncoded {
@@ -59,11 +116,11 @@ func RetryApiCall(
 	var err error
 	switch method {
 	case "POST", "post":
-		req, err = retryablehttp.NewRequest(http.MethodPost, apiURL, bodyBuffer)
+		req, err = retryablehttp.NewRequestWithContext(ctx, http.MethodPost, apiURL, bodyBuffer)
 	case "PUT", "put":
-		req, err = retryablehttp.NewRequest(http.MethodPut, apiURL, bodyBuffer)
+		req, err = retryablehttp.NewRequestWithContext(ctx, http.MethodPut, apiURL, bodyBuffer)
 	case "GET", "get":
-		req, err = retryablehttp.NewRequest(http.MethodGet, apiURL, nil)
+		req, err = retryablehttp.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
 	default:
 		return nil, fmt.Errorf("invalid HTTP method: %s", method)
 	}
@@ -89,7 +146,6 @@ func RetryApiCall(
 
 	// Make the HTTP request with retry
 	resp, err := client.Do(req)
-	// utils.Debug(fmt.Sprintf("Response: %v", resp))
 	if err != nil {
 		return nil, fmt.Errorf("error sending request: %v", err)
 	}
@@ -100,37 +156,12 @@ func RetryApiCall(
 	if err != nil {
 		return nil, fmt.Errorf("error reading response body: %v", err)
 	}
-
-	// Parse JSON response
-	var result map[string]interface{}
-	err = json.Unmarshal(body, &result)
-	if err != nil {
-		return nil, fmt.Errorf("error unmarshalling response: %v", err)
-	}
-	Info(fmt.Sprintf("API_RESPONSE: %v", result))
-	result["ApistatusCode"] = int(resp.StatusCode)
-	return result, nil
+	return &ApiResponse{StatusCode: resp.StatusCode, Body: body}, nil
 }
 
-// ApiHit makes an API call with optional retries
-func ApiHit(method, apiURL string, headers map[string]string, username, password string, data interface{}, reqType int) (map[string]interface{}, error) {
-	// Handle retry logic in a goroutine
-	resultChan := make(chan map[string]interface{})
-	errChan := make(chan error)
-	go func() {
-		result, err := RetryApiCall(method, apiURL, headers, username, password, data, reqType, 0, 0*time.Second, 0*time.Second) //TODO: Retry api call is paused for now
-		if err != nil {
-			errChan <- err
-			return
-		}
-		resultChan <- result
-	}()
-
-	// Wait for the result or error
-	select {
-	case result := <-resultChan:
-		return result, nil
-	case err := <-errChan:
-		return nil, err
+func truncateBody(body []byte) string {
+	if len(body) > 512 {
+		return string(body[:512]) + "..."
 	}
+	return string(body)
 }
@@ -1,16 +1,28 @@
 package utils
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"log/slog"
 	"os"
+	"runtime"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/wecredit/communication-sdk/internal/redact"
 	env "github.com/wecredit/communication-sdk/sdk/constant"
 	"github.com/wecredit/communication-sdk/sdk/variables"
 )
 
-// Log levels
+// levelNoLogs is above every level that is logged, so NOLOGS silences the logger.
+const levelNoLogs = slog.LevelError + 4
+
+// Log levels of the first versions of the SDK.
+//
+// Deprecated: levels are set by name with SetLogLevel, e.g. variables.Debug, and read with LogLevel.
 const (
 	DEBUG = iota
 	INFO
@@ -19,7 +31,15 @@ const (
 	NOLOGS
 )
 
-var logLevel = INFO
+// level is the minimum level logged; SetLogLevel changes it at runtime.
+var level = new(slog.LevelVar)
+
+// logger writes JSON lines to stdout unless SetLogger replaced it.
+var logger atomic.Pointer[slog.Logger]
+
+// Logger is the logger of the first versions of the SDK.
+//
+// Deprecated: log through Error, Warn, Info and Debug, or replace the logger with SetLogger.
 var Logger = log.New(os.Stdout, "LOG: ", log.Ldate|log.Ltime|log.Lshortfile)
 
 func init() {
@@ -28,68 +48,154 @@ func init() {
 		log.Printf("Warning: No .env file found: %v", err)
 	}
 
-	/* 	// Create logs directory if not exists
-	   	logDir := "logs"
-	   	if err := os.MkdirAll(logDir, os.ModePerm); err != nil {
-	   		log.Fatalf("Failed to create log directory: %v", err)
-	   	}
-
-	   	// Open log file
-	   	logFilePath := filepath.Join(logDir, "app.log")
-	   	logFile, err := os.OpenFile(logFilePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
-	   	if err != nil {
-	   		log.Fatalf("Failed to open log file: %v", err)
-	   	}
-
-	   	// MultiWriter to log both to console and file
-	   	multiWriter := io.MultiWriter(os.Stdout, logFile)
-	   	Logger = log.New(multiWriter, "LOG: ", log.Ldate|log.Ltime|log.Lshortfile)
-	*/
-
-	// Set log level
-	level := env.LOG_LEVEL
-	switch strings.ToUpper(level) {
+	logger.Store(slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{AddSource: true, Level: level})))
+
+	// LOG_LEVEL from the environment overrides the compiled in default
+	name := os.Getenv("LOG_LEVEL")
+	if name == "" {
+		name = env.LOG_LEVEL
+	}
+	if err := SetLogLevel(name); err != nil {
+		level.Set(slog.LevelInfo)
+	}
+
+	Info(fmt.Sprintf("Logger initialized with level: %s", LogLevel()))
+}
+
+// SetLogger replaces the logger of the SDK and of the consumer. The level set with SetLogLevel still applies.
+func SetLogger(l *slog.Logger) {
+	if l != nil {
+		logger.Store(l)
+	}
+}
+
+// SetLogLevel sets the minimum level logged: DEBUG, INFO, WARN, ERROR or NOLOGS.
+func SetLogLevel(name string) error {
+	switch strings.ToUpper(strings.TrimSpace(name)) {
 	case variables.Debug:
-		logLevel = DEBUG
+		level.Set(slog.LevelDebug)
 	case variables.Info:
-		logLevel = INFO
+		level.Set(slog.LevelInfo)
 	case variables.Warn:
-		logLevel = WARN
+		level.Set(slog.LevelWarn)
 	case variables.Error:
-		logLevel = ERROR
+		level.Set(slog.LevelError)
 	case variables.NoLogs:
-		logLevel = NOLOGS
+		level.Set(levelNoLogs)
 	default:
-		logLevel = INFO // Default to INFO if not set
+		return fmt.Errorf("unknown log level %q", name)
+	}
+	return nil
+}
+
+// LogLevel returns the name of the minimum level logged.
+func LogLevel() string {
+	switch current := level.Level(); {
+	case current >= levelNoLogs:
+		return variables.NoLogs
+	case current >= slog.LevelError:
+		return variables.Error
+	case current >= slog.LevelWarn:
+		return variables.Warn
+	case current >= slog.LevelInfo:
+		return variables.Info
+	}
+	return variables.Debug
+}
+
+type logAttrsKey struct{}
+
+// WithLogAttrs returns a context whose log lines carry attrs, besides those the parent context already carries.
+// An attr replaces the one of the same key.
+func WithLogAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	parent, _ := ctx.Value(logAttrsKey{}).([]slog.Attr)
+	merged := make([]slog.Attr, 0, len(parent)+len(attrs))
+	for _, attr := range parent {
+		replaced := false
+		for _, override := range attrs {
+			if override.Key == attr.Key {
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, attr)
+		}
 	}
+	return context.WithValue(ctx, logAttrsKey{}, append(merged, attrs...))
+}
+
+// WithMessage returns a context whose log lines identify the message.
+func WithMessage(ctx context.Context, commId, client, channel string, stage float64) context.Context {
+	return WithLogAttrs(ctx,
+		slog.String("commId", commId),
+		slog.String("client", client),
+		slog.String("channel", channel),
+		slog.Float64("stage", stage),
+	)
+}
 
-	Logger.Printf("Logger initialized with level: %s", strings.ToUpper(level))
+// WithVendor returns a context whose log lines name the vendor the message is sent through.
+func WithVendor(ctx context.Context, vendor string) context.Context {
+	return WithLogAttrs(ctx, slog.String("vendor", vendor))
 }
 
 // Error logs an error message
 func Error(err error) {
-	if logLevel <= ERROR {
-		Logger.Println("ERROR:", err)
-	}
+	write(context.Background(), slog.LevelError, fmt.Sprint(err))
 }
 
 // Warn logs a warning message
 func Warn(message string) {
-	if logLevel <= WARN {
-		Logger.Println("WARN:", message)
-	}
+	write(context.Background(), slog.LevelWarn, message)
 }
 
 // Info logs an informational message
 func Info(message string) {
-	if logLevel <= INFO {
-		Logger.Println("INFO:", message)
-	}
+	write(context.Background(), slog.LevelInfo, message)
 }
 
 // Debug logs a debug message
 func Debug(message string) {
-	if logLevel <= DEBUG {
-		Logger.Println("DEBUG:", message)
+	write(context.Background(), slog.LevelDebug, message)
+}
+
+// ErrorCtx logs an error message with the fields of ctx
+func ErrorCtx(ctx context.Context, err error) {
+	write(ctx, slog.LevelError, fmt.Sprint(err))
+}
+
+// WarnCtx logs a warning message with the fields of ctx
+func WarnCtx(ctx context.Context, message string) {
+	write(ctx, slog.LevelWarn, message)
+}
+
+// InfoCtx logs an informational message with the fields of ctx
+func InfoCtx(ctx context.Context, message string) {
+	write(ctx, slog.LevelInfo, message)
+}
+
+// DebugCtx logs a debug message with the fields of ctx
+func DebugCtx(ctx context.Context, message string) {
+	write(ctx, slog.LevelDebug, message)
+}
+
+func write(ctx context.Context, lvl slog.Level, message string) {
+	if lvl < level.Level() {
+		return
+	}
+	l := logger.Load()
+	if !l.Enabled(ctx, lvl) {
+		return
+	}
+
+	// The source is the caller of Error, Warn, Info or Debug rather than this file
+	var pcs [1]uintptr
+	runtime.Callers(3, pcs[:])
+	// Mobile numbers and email addresses never reach the log; the commId identifies the message
+	record := slog.NewRecord(time.Now(), lvl, redact.Text(message), pcs[0])
+	if attrs, ok := ctx.Value(logAttrsKey{}).([]slog.Attr); ok {
+		record.AddAttrs(attrs...)
 	}
+	_ = l.Handler().Handle(ctx, record)
 }
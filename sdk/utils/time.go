@@ -0,0 +1,9 @@
+package utils
+
+import "time"
+
+// IstNow returns the current Indian Standard Time wall clock as a UTC time, the way the tables store timestamps.
+func IstNow() time.Time {
+	istOffset := 5*time.Hour + 30*time.Minute
+	return time.Now().UTC().Add(istOffset)
+}
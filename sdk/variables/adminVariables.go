@@ -0,0 +1,8 @@
+package variables
+
+// Admin API key roles
+const (
+	RoleViewer         string = "viewer"
+	RoleTemplateEditor string = "template-editor"
+	RoleOpsAdmin       string = "ops-admin"
+)
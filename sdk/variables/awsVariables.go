@@ -6,4 +6,5 @@ var (
 	OutputInsertionFails string = "outputInsertionFails"
 	ApiHitsFails         string = "apiHitsFails"
 	RedisValueMissing    string = "redisValueMissing"
+	UntrustedMessage     string = "untrustedMessage" // failed SNS signature or producer signature verification
 )
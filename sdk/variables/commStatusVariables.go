@@ -0,0 +1,8 @@
+package variables
+
+// Communication statuses returned by status lookups
+const (
+	CommQueued string = "QUEUED"
+	CommSent   string = "SENT"
+	CommFailed string = "FAILED"
+)
@@ -0,0 +1,9 @@
+package variables
+
+// SDK outbox message statuses
+const (
+	OutboxPending  string = "PENDING"
+	OutboxInFlight string = "IN_FLIGHT"
+	OutboxSent     string = "SENT"
+	OutboxFailed   string = "FAILED"
+)
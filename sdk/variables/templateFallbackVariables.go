@@ -0,0 +1,8 @@
+package variables
+
+// Template resolution steps, tried in the order listed in a client's TemplateFallbackPolicy
+const (
+	TemplateMatchExact        string = "EXACT"
+	TemplateMatchSiblingStage string = "SIBLING_STAGE"
+	TemplateMatchOtherVendor  string = "OTHER_VENDOR"
+)
@@ -0,0 +1,9 @@
+package variables
+
+// Template version states
+const (
+	TemplateDraft      string = "DRAFT"
+	TemplateApproved   string = "APPROVED"
+	TemplateActive     string = "ACTIVE"
+	TemplateSuperseded string = "SUPERSEDED"
+)
@@ -0,0 +1,15 @@
+package variables
+
+// Client webhook event types
+const (
+	WebhookEventSent   string = "SENT"
+	WebhookEventFailed string = "FAILED"
+)
+
+// Client webhook delivery statuses
+const (
+	WebhookPending   string = "PENDING"
+	WebhookInFlight  string = "IN_FLIGHT"
+	WebhookDelivered string = "DELIVERED"
+	WebhookFailed    string = "FAILED"
+)
@@ -52,7 +52,7 @@ func GetVendorByClientAndChannel(channel, client, idempotencyKey string) string
 	channelKey := strings.ToUpper(channel)
 	clientKey := strings.ToLower(client)
 
-	if channelSlots, ok := cache.ChannelVendorSlots[channelKey]; ok {
+	if channelSlots, ok := cache.Current().VendorSlots[channelKey]; ok {
 		if slots, ok := channelSlots[clientKey]; ok {
 			if vendor := slots[val]; vendor != "" {
 				return vendor